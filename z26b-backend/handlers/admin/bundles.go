@@ -0,0 +1,168 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+
+	"z26b-backend/internal"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bundleItemInput 组合商品组成项请求参数
+type bundleItemInput struct {
+	SKUID    string `json:"skuId" binding:"required"`
+	Quantity int    `json:"quantity" binding:"required"`
+}
+
+func toBundleItems(inputs []bundleItemInput) []internal.BundleItem {
+	items := make([]internal.BundleItem, 0, len(inputs))
+	for _, in := range inputs {
+		items = append(items, internal.BundleItem{SKUID: in.SKUID, Quantity: in.Quantity})
+	}
+	return items
+}
+
+// AdminGetBundles 获取组合商品列表
+func (h *Handler) AdminGetBundles(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+
+	bundles, total, err := h.AdminBundleService.GetBundles(page, pageSize, c.Query("keyword"), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取组合商品失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{"list": bundles, "total": total, "page": page, "pageSize": pageSize},
+	})
+}
+
+// AdminGetBundle 获取组合商品详情
+func (h *Handler) AdminGetBundle(c *gin.Context) {
+	bundle, err := h.AdminBundleService.GetBundle(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": bundle})
+}
+
+// AdminCreateBundle 创建组合商品
+func (h *Handler) AdminCreateBundle(c *gin.Context) {
+	var req struct {
+		Name        string            `json:"name" binding:"required"`
+		Description string            `json:"description"`
+		CoverImage  string            `json:"cover_image"`
+		Price       float64           `json:"price" binding:"required"`
+		Status      string            `json:"status"`
+		Priority    int               `json:"priority"`
+		Items       []bundleItemInput `json:"items" binding:"required,dive"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请填写完整信息"})
+		return
+	}
+
+	adminID := c.GetString("adminID")
+	bundle := &internal.Bundle{
+		Name:        req.Name,
+		Description: req.Description,
+		CoverImage:  req.CoverImage,
+		Price:       req.Price,
+		Status:      req.Status,
+		Priority:    req.Priority,
+		Items:       toBundleItems(req.Items),
+		CreatedBy:   adminID,
+		UpdatedBy:   adminID,
+	}
+	if err := h.AdminBundleService.CreateBundle(bundle); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": bundle})
+}
+
+// AdminUpdateBundle 更新组合商品，传入 items 时整体替换组成项
+func (h *Handler) AdminUpdateBundle(c *gin.Context) {
+	id := c.Param("id")
+
+	var req struct {
+		Name        *string           `json:"name"`
+		Description *string           `json:"description"`
+		CoverImage  *string           `json:"cover_image"`
+		Price       *float64          `json:"price"`
+		Status      string            `json:"status"`
+		Priority    *int              `json:"priority"`
+		Items       []bundleItemInput `json:"items" binding:"omitempty,dive"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	updates := map[string]interface{}{"updated_by": c.GetString("adminID")}
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.CoverImage != nil {
+		updates["cover_image"] = *req.CoverImage
+	}
+	if req.Price != nil {
+		updates["price"] = *req.Price
+	}
+	if req.Status != "" {
+		updates["status"] = req.Status
+	}
+	if req.Priority != nil {
+		updates["priority"] = *req.Priority
+	}
+
+	var items []internal.BundleItem
+	if req.Items != nil {
+		items = toBundleItems(req.Items)
+	}
+
+	if err := h.AdminBundleService.UpdateBundle(id, updates, items); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "更新成功"})
+}
+
+// AdminDeleteBundle 删除组合商品
+func (h *Handler) AdminDeleteBundle(c *gin.Context) {
+	if err := h.AdminBundleService.DeleteBundle(c.Param("id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// AdminToggleBundleStatus 切换组合商品上下架状态
+func (h *Handler) AdminToggleBundleStatus(c *gin.Context) {
+	id := c.Param("id")
+
+	bundle, err := h.AdminBundleService.GetBundle(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	newStatus := "DISABLED"
+	if bundle.Status == "DISABLED" {
+		newStatus = "ENABLED"
+	}
+	if err := h.AdminBundleService.UpdateBundleStatus(id, newStatus); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "更新失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": newStatus})
+}
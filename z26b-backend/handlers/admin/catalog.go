@@ -0,0 +1,111 @@
+package admin
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"z26b-backend/internal"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportFileSize 导入文件大小上限
+const maxImportFileSize = 10 * 1024 * 1024
+
+// AdminImportProducts 批量导入商品（CSV/XLSX），dryRun=true 时只校验不写入
+func (h *Handler) AdminImportProducts(c *gin.Context) {
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请选择要导入的文件"})
+		return
+	}
+	defer file.Close()
+
+	if header.Size > maxImportFileSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "文件大小不能超过 10MB"})
+		return
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "读取文件失败"})
+		return
+	}
+
+	var rows [][]string
+	switch strings.ToLower(filepath.Ext(header.Filename)) {
+	case ".csv":
+		rows, err = internal.ReadCSV(bytes.NewReader(data))
+	case ".xlsx":
+		rows, err = internal.ReadXLSX(bytes.NewReader(data), int64(len(data)))
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "只支持 CSV 或 XLSX 文件"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "解析文件失败: " + err.Error()})
+		return
+	}
+	if len(rows) > internal.MaxSpreadsheetRows {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "单次最多导入 10000 行"})
+		return
+	}
+
+	dryRun := c.DefaultPostForm("dryRun", c.Query("dryRun"))
+	result, err := h.AdminCatalogService.ImportCatalog(rows, dryRun == "true" || dryRun == "1", c.GetString("adminID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "导入失败: " + err.Error()})
+		return
+	}
+	if len(result.Errors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "导入数据校验失败", "data": result})
+		return
+	}
+
+	if !result.DryRun {
+		for _, id := range result.SPUIDs {
+			h.syncSearchIndex(id)
+		}
+	}
+
+	message := "导入成功"
+	if result.DryRun {
+		message = "校验通过"
+	}
+	c.JSON(http.StatusOK, gin.H{"message": message, "data": result})
+}
+
+// AdminExportProducts 导出全部商品，格式与导入一致，format=csv|xlsx
+func (h *Handler) AdminExportProducts(c *gin.Context) {
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "xlsx" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "只支持 csv 或 xlsx 格式"})
+		return
+	}
+
+	rows, err := h.AdminCatalogService.ExportCatalog()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "导出失败"})
+		return
+	}
+
+	var buf bytes.Buffer
+	contentType := "text/csv; charset=utf-8"
+	if format == "xlsx" {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		err = internal.WriteXLSX(&buf, "products", rows)
+	} else {
+		err = internal.WriteCSV(&buf, rows)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "导出失败"})
+		return
+	}
+
+	filename := "products-" + time.Now().Format("20060102150405") + "." + format
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
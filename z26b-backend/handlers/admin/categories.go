@@ -18,6 +18,16 @@ func (h *Handler) AdminGetCategories(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": categories})
 }
 
+// AdminGetCategoryTree 获取树形分类
+func (h *Handler) AdminGetCategoryTree(c *gin.Context) {
+	tree, err := h.AdminCategoryService.GetCategoryTree()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch categories"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": tree})
+}
+
 // AdminGetCategory 获取分类详情
 func (h *Handler) AdminGetCategory(c *gin.Context) {
 	id := c.Param("id")
@@ -54,7 +64,7 @@ func (h *Handler) AdminCreateCategory(c *gin.Context) {
 
 	err := h.AdminCategoryService.CreateCategory(category)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create category"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -89,13 +99,13 @@ func (h *Handler) AdminUpdateCategory(c *gin.Context) {
 		updates["image"] = req.Image
 	}
 	if req.ParentID != "" {
-		updates["parentId"] = req.ParentID
+		updates["parent_id"] = req.ParentID
 	}
 	updates["sort"] = req.Sort
 
 	err := h.AdminCategoryService.UpdateCategory(id, updates)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update category"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -109,13 +119,39 @@ func (h *Handler) AdminUpdateCategory(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": category})
 }
 
+// AdminMoveCategory 移动分类（连同子分类），parentId 为空时移动为一级分类
+func (h *Handler) AdminMoveCategory(c *gin.Context) {
+	id := c.Param("id")
+
+	var req struct {
+		ParentID string `json:"parentId"`
+		Sort     *int   `json:"sort"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	if err := h.AdminCategoryService.MoveCategory(id, req.ParentID, req.Sort); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tree, err := h.AdminCategoryService.GetCategoryTree()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch categories"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": tree, "message": "移动成功"})
+}
+
 // AdminDeleteCategory 删除分类
 func (h *Handler) AdminDeleteCategory(c *gin.Context) {
 	id := c.Param("id")
 
 	err := h.AdminCategoryService.DeleteCategory(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "分类下有商品，无法删除"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
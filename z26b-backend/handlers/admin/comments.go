@@ -0,0 +1,151 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"z26b-backend/services/admin_services"
+	"z26b-backend/services/moderation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminGetComments 获取评论列表（审核队列），支持按审核状态、商品、评分、评论时间、关键词和是否已回复筛选
+func (h *Handler) AdminGetComments(c *gin.Context) {
+	page, pageSize := inventoryPage(c)
+	q := admin_services.AdminCommentQuery{
+		Status:  c.Query("status"),
+		SPUID:   c.Query("spuId"),
+		Keyword: c.Query("keyword"),
+	}
+	q.Score, _ = strconv.Atoi(c.Query("score"))
+	q.StartTime, _ = strconv.ParseInt(c.Query("startTime"), 10, 64)
+	q.EndTime, _ = strconv.ParseInt(c.Query("endTime"), 10, 64)
+	if replied, err := strconv.ParseBool(c.Query("replied")); err == nil {
+		q.Replied = &replied
+	}
+	comments, total, err := h.AdminCommentService.GetComments(q, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取评论列表失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"list": comments, "total": total, "page": page, "pageSize": pageSize}})
+}
+
+// AdminBatchComments 批量审核评论：approve / reject / hide / delete
+func (h *Handler) AdminBatchComments(c *gin.Context) {
+	var req struct {
+		IDs    []string `json:"ids" binding:"required"`
+		Action string   `json:"action" binding:"required"`
+		Reason string   `json:"reason"` // 驳回原因
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	count, err := h.AdminCommentService.BatchComments(req.IDs, req.Action, req.Reason, c.GetString("adminID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"count": count}, "message": "操作成功"})
+}
+
+// AdminReplyComment 发表或修改商家回复
+func (h *Handler) AdminReplyComment(c *gin.Context) {
+	var req struct {
+		Content string `json:"content" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请填写回复内容"})
+		return
+	}
+
+	comment, err := h.AdminCommentService.ReplyComment(c.Param("id"), req.Content, c.GetString("adminID"))
+	if errors.Is(err, admin_services.ErrCommentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": comment, "message": "回复成功"})
+}
+
+// AdminDeleteCommentReply 删除商家回复
+func (h *Handler) AdminDeleteCommentReply(c *gin.Context) {
+	err := h.AdminCommentService.DeleteReply(c.Param("id"))
+	if errors.Is(err, admin_services.ErrCommentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// AdminGetSensitiveWords 获取敏感词列表
+func (h *Handler) AdminGetSensitiveWords(c *gin.Context) {
+	page, pageSize := inventoryPage(c)
+	words, total, err := h.ModerationService.GetWords(c.Query("keyword"), c.Query("level"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取敏感词失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"list": words, "total": total, "page": page, "pageSize": pageSize}})
+}
+
+// AdminAddSensitiveWords 批量添加敏感词，已存在的词更新级别
+func (h *Handler) AdminAddSensitiveWords(c *gin.Context) {
+	var req struct {
+		Words []string `json:"words" binding:"required"`
+		Level string   `json:"level" binding:"required"` // MASK / REVIEW / BLOCK
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请填写敏感词和处理级别"})
+		return
+	}
+
+	count, err := h.ModerationService.AddWords(req.Words, req.Level, c.GetString("adminID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"count": count}, "message": "保存成功"})
+}
+
+// AdminUpdateSensitiveWord 修改敏感词处理级别
+func (h *Handler) AdminUpdateSensitiveWord(c *gin.Context) {
+	var req struct {
+		Level string `json:"level" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请选择处理级别"})
+		return
+	}
+
+	err := h.ModerationService.UpdateWord(c.Param("id"), req.Level)
+	if errors.Is(err, moderation.ErrWordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "保存成功"})
+}
+
+// AdminDeleteSensitiveWord 删除敏感词
+func (h *Handler) AdminDeleteSensitiveWord(c *gin.Context) {
+	if _, err := h.ModerationService.DeleteWords([]string{c.Param("id")}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
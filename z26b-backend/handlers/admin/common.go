@@ -3,35 +3,77 @@ package admin
 import (
 	admin_services "z26b-backend/services/admin_services"
 	"z26b-backend/services/crm"
+	"z26b-backend/services/distribution"
+	"z26b-backend/services/history"
+	"z26b-backend/services/inventory"
+	"z26b-backend/services/moderation"
+	"z26b-backend/services/search"
+	"z26b-backend/services/wallet"
 
 	"gorm.io/gorm"
 )
 
 // Handler 管理后台处理器
 type Handler struct {
-	AdminGoodsService    admin_services.AdminGoodsServiceInterface
-	AdminCategoryService admin_services.AdminCategoryServiceInterface
-	CRMEventService      *crm.CRMEventService
-	CustomerStatsService *crm.CustomerStatsService
-	ProductStatsService  *crm.ProductStatsService
-	DB                   *gorm.DB // 暂时保留，用于其他功能迁移
+	AdminGoodsService      admin_services.AdminGoodsServiceInterface
+	AdminCategoryService   admin_services.AdminCategoryServiceInterface
+	AdminBundleService     admin_services.AdminBundleServiceInterface
+	AdminCatalogService    admin_services.AdminCatalogServiceInterface
+	AdminRecycleBinService admin_services.AdminRecycleBinServiceInterface
+	AdminWarehouseService  admin_services.AdminWarehouseServiceInterface
+	AdminQuestionService   admin_services.AdminQuestionServiceInterface
+	AdminCommentService    admin_services.AdminCommentServiceInterface
+	CRMEventService        *crm.CRMEventService
+	CustomerStatsService   *crm.CustomerStatsService
+	ProductStatsService    *crm.ProductStatsService
+	CommissionService      *distribution.CommissionService
+	WalletService          *wallet.WalletService
+	SearchService          *search.SearchService
+	ProductHistoryService  history.ProductHistoryServiceInterface
+	InventoryService       *inventory.InventoryService
+	ModerationService      *moderation.ModerationService
+	DB                     *gorm.DB // 暂时保留，用于其他功能迁移
 }
 
 // NewHandler 创建处理器实例
 func NewHandler(
 	adminGoodsService admin_services.AdminGoodsServiceInterface,
 	adminCategoryService admin_services.AdminCategoryServiceInterface,
+	adminBundleService admin_services.AdminBundleServiceInterface,
+	adminCatalogService admin_services.AdminCatalogServiceInterface,
+	adminRecycleBinService admin_services.AdminRecycleBinServiceInterface,
+	adminWarehouseService admin_services.AdminWarehouseServiceInterface,
+	adminQuestionService admin_services.AdminQuestionServiceInterface,
+	adminCommentService admin_services.AdminCommentServiceInterface,
 	crmEventService *crm.CRMEventService,
 	customerStatsService *crm.CustomerStatsService,
 	productStatsService *crm.ProductStatsService,
+	commissionService *distribution.CommissionService,
+	walletService *wallet.WalletService,
+	searchService *search.SearchService,
+	productHistoryService history.ProductHistoryServiceInterface,
+	inventoryService *inventory.InventoryService,
+	moderationService *moderation.ModerationService,
 	db *gorm.DB,
 ) *Handler {
 	return &Handler{
-		AdminGoodsService:    adminGoodsService,
-		AdminCategoryService: adminCategoryService,
-		CRMEventService:      crmEventService,
-		CustomerStatsService: customerStatsService,
-		ProductStatsService:  productStatsService,
-		DB:                   db,
+		AdminGoodsService:      adminGoodsService,
+		AdminCategoryService:   adminCategoryService,
+		AdminBundleService:     adminBundleService,
+		AdminCatalogService:    adminCatalogService,
+		AdminRecycleBinService: adminRecycleBinService,
+		AdminWarehouseService:  adminWarehouseService,
+		AdminQuestionService:   adminQuestionService,
+		AdminCommentService:    adminCommentService,
+		CRMEventService:        crmEventService,
+		CustomerStatsService:   customerStatsService,
+		ProductStatsService:    productStatsService,
+		CommissionService:      commissionService,
+		WalletService:          walletService,
+		SearchService:          searchService,
+		ProductHistoryService:  productHistoryService,
+		InventoryService:       inventoryService,
+		ModerationService:      moderationService,
+		DB:                     db,
 	}
 }
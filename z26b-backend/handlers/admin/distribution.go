@@ -0,0 +1,152 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"z26b-backend/internal"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ============================================
+// 分销佣金规则 API
+// ============================================
+
+// AdminGetCommissionRules 获取佣金规则列表
+func (h *Handler) AdminGetCommissionRules(c *gin.Context) {
+	rules, err := h.CommissionService.GetRules(c.Query("scope"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取佣金规则失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rules})
+}
+
+// AdminCreateCommissionRule 创建佣金规则
+func (h *Handler) AdminCreateCommissionRule(c *gin.Context) {
+	var req struct {
+		Scope    string  `json:"scope" binding:"required"`
+		TargetID string  `json:"targetId" binding:"required"`
+		Rate     float64 `json:"rate" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请填写完整信息"})
+		return
+	}
+
+	rule := &internal.CommissionRule{
+		Scope:     req.Scope,
+		TargetID:  req.TargetID,
+		Rate:      req.Rate,
+		CreatedBy: c.GetString("adminID"),
+	}
+	if err := h.CommissionService.CreateRule(rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": rule})
+}
+
+// AdminUpdateCommissionRule 更新佣金规则
+func (h *Handler) AdminUpdateCommissionRule(c *gin.Context) {
+	id := c.Param("id")
+
+	var req struct {
+		Rate   *float64 `json:"rate"`
+		Status string   `json:"status"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if req.Rate != nil {
+		updates["rate"] = *req.Rate
+	}
+	if req.Status != "" {
+		updates["status"] = req.Status
+	}
+
+	if err := h.CommissionService.UpdateRule(id, updates); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "佣金规则不存在"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "更新成功"})
+}
+
+// AdminDeleteCommissionRule 删除佣金规则
+func (h *Handler) AdminDeleteCommissionRule(c *gin.Context) {
+	if err := h.CommissionService.DeleteRule(c.Param("id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// ============================================
+// 分销佣金记录 API
+// ============================================
+
+// AdminGetCommissions 获取佣金记录列表
+func (h *Handler) AdminGetCommissions(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+	referrerID := c.Query("referrerId")
+	status := c.Query("status")
+
+	records, total, err := h.CommissionService.GetCommissions(referrerID, status, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取佣金记录失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{"list": records, "total": total, "page": page, "pageSize": pageSize},
+	})
+}
+
+// AdminSettleCommissions 结算佣金
+func (h *Handler) AdminSettleCommissions(c *gin.Context) {
+	var req struct {
+		IDs        []string `json:"ids"`
+		ReferrerID string   `json:"referrerId"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	settled, err := h.CommissionService.SettleCommissions(req.IDs, req.ReferrerID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "结算成功", "settled": settled})
+}
+
+// AdminGetReferrerReport 按推荐人汇总待结算/已结算佣金
+func (h *Handler) AdminGetReferrerReport(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+
+	report, total, err := h.CommissionService.GetReferrerReport(page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取推荐人报表失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{"list": report, "total": total, "page": page, "pageSize": pageSize},
+	})
+}
@@ -0,0 +1,99 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"z26b-backend/internal"
+	"z26b-backend/services/history"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminGetProductHistory 获取商品变更历史（按版本号倒序）
+func (h *Handler) AdminGetProductHistory(c *gin.Context) {
+	id := c.Param("id")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	revisions, total, err := h.ProductHistoryService.GetHistory(id, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取变更历史失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"list": revisions, "total": total, "page": page, "pageSize": pageSize}})
+}
+
+// AdminGetProductRevision 获取商品指定版本的完整快照
+func (h *Handler) AdminGetProductRevision(c *gin.Context) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "版本号无效"})
+		return
+	}
+
+	revision, err := h.ProductHistoryService.GetRevision(c.Param("id"), version)
+	if errors.Is(err, history.ErrRevisionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取版本失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": revision})
+}
+
+// AdminRestoreProductRevision 将商品恢复到指定版本
+func (h *Handler) AdminRestoreProductRevision(c *gin.Context) {
+	id := c.Param("id")
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "版本号无效"})
+		return
+	}
+
+	result, err := h.ProductHistoryService.Restore(id, version, c.GetString("adminID"))
+	if errors.Is(err, history.ErrRevisionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "恢复失败: " + err.Error()})
+		return
+	}
+	h.syncSearchIndex(id)
+
+	message := "恢复成功"
+	if result.Revision == nil {
+		message = "当前已是该版本内容"
+	}
+	c.JSON(http.StatusOK, gin.H{"data": result, "message": message})
+}
+
+// snapshotProduct 变更前获取商品快照，失败时返回 nil，不影响主流程
+func (h *Handler) snapshotProduct(spuID string) *history.ProductSnapshot {
+	snap, err := h.ProductHistoryService.Capture(spuID)
+	if err != nil {
+		internal.GlobalLogger.Warn("Failed to capture product snapshot", map[string]interface{}{"spuId": spuID, "error": err.Error()})
+		return nil
+	}
+	return snap
+}
+
+// recordRevision 变更后记录商品版本，失败只记录日志
+func (h *Handler) recordRevision(spuID, action, adminID string, before *history.ProductSnapshot) {
+	if before == nil {
+		return
+	}
+	if _, err := h.ProductHistoryService.Record(spuID, action, adminID, before); err != nil {
+		internal.GlobalLogger.Warn("Failed to record product revision", map[string]interface{}{"spuId": spuID, "error": err.Error()})
+	}
+}
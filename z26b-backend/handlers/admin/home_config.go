@@ -473,6 +473,7 @@ func (h *Handler) AdminSaveHomeContent(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
 		return
 	}
+	req.Content = internal.SanitizeHTML(req.Content)
 
 	// 查找是否已存在
 	var existing internal.HomeContent
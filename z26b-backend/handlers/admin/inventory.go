@@ -0,0 +1,129 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"z26b-backend/internal"
+	"z26b-backend/services/inventory"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminGetStockMovements 获取 SKU 库存流水，可按 reason 筛选
+func (h *Handler) AdminGetStockMovements(c *gin.Context) {
+	page, pageSize := inventoryPage(c)
+	movements, total, err := h.InventoryService.GetMovements(c.Param("id"), c.Query("reason"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取库存流水失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"list": movements, "total": total, "page": page, "pageSize": pageSize}})
+}
+
+// AdminAdjustStock 按增量调整 SKU 库存，必须填写调整原因
+func (h *Handler) AdminAdjustStock(c *gin.Context) {
+	var req struct {
+		Quantity int    `json:"quantity" binding:"required"`
+		Remark   string `json:"remark" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请填写调整数量和原因"})
+		return
+	}
+
+	id := c.Param("id")
+	var sku internal.SKU
+	if err := h.DB.Select("id", "SPUID").First(&sku, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "SKU不存在"})
+		return
+	}
+
+	adminID := c.GetString("adminID")
+	before := h.snapshotProduct(sku.SPUID)
+	movement, err := h.InventoryService.Change(id, req.Quantity, internal.StockReasonAdjust, "", adminID, req.Remark)
+	if errors.Is(err, inventory.ErrInsufficientStock) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "调整库存失败: " + err.Error()})
+		return
+	}
+	h.recordRevision(sku.SPUID, internal.RevisionActionSKUUpdate, adminID, before)
+
+	c.JSON(http.StatusOK, gin.H{"data": movement, "message": "调整成功"})
+}
+
+// AdminGetLowStock 低库存报表
+func (h *Handler) AdminGetLowStock(c *gin.Context) {
+	page, pageSize := inventoryPage(c)
+	items, total, err := h.InventoryService.GetLowStock(c.Query("keyword"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取低库存报表失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"list": items, "total": total, "page": page, "pageSize": pageSize}})
+}
+
+// AdminGetStockAlerts 获取低库存预警，status=open 时只返回未处理的预警
+func (h *Handler) AdminGetStockAlerts(c *gin.Context) {
+	page, pageSize := inventoryPage(c)
+	alerts, total, err := h.InventoryService.GetAlerts(c.Query("status") == "open", page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取库存预警失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"list": alerts, "total": total, "page": page, "pageSize": pageSize}})
+}
+
+// AdminAcknowledgeStockAlerts 处理库存预警，ids 为空时处理全部
+func (h *Handler) AdminAcknowledgeStockAlerts(c *gin.Context) {
+	var req struct {
+		IDs []string `json:"ids"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	count, err := h.InventoryService.AcknowledgeAlerts(req.IDs, c.GetString("adminID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "处理预警失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"count": count}, "message": "处理成功"})
+}
+
+// AdminSetStockThreshold 批量设置低库存预警值，0 表示不预警
+func (h *Handler) AdminSetStockThreshold(c *gin.Context) {
+	var req struct {
+		SPUID     string   `json:"spuId"`
+		SKUIDs    []string `json:"skuIds"`
+		Threshold *int     `json:"threshold" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请填写预警值"})
+		return
+	}
+
+	count, err := h.InventoryService.SetThreshold(req.SPUID, req.SKUIDs, *req.Threshold)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"count": count}, "message": "设置成功"})
+}
+
+func inventoryPage(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return page, pageSize
+}
@@ -1,11 +1,14 @@
 package admin
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
 	"z26b-backend/internal"
+	"z26b-backend/services/admin_services"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -32,10 +35,14 @@ func (h *Handler) AdminGetOrders(c *gin.Context) {
 	if userId != "" {
 		query = query.Where("userId = ?", userId)
 	}
+	// 按发货仓库筛选
+	if warehouseID := c.Query("warehouseId"); warehouseID != "" {
+		query = query.Where("id IN (SELECT order_id FROM order_shipment WHERE warehouse_id = ?)", warehouseID)
+	}
 
 	query.Count(&total)
 	offset := (page - 1) * pageSize
-	query.Preload("Items.SKU.SPU").Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&orders)
+	query.Preload("Items.SKU", internal.WithDeleted).Preload("Items.SKU.SPU", internal.WithDeleted).Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&orders)
 
 	type OrderWithUser struct {
 		internal.Order
@@ -62,7 +69,7 @@ func (h *Handler) AdminGetOrder(c *gin.Context) {
 	id := c.Param("id")
 
 	var order internal.Order
-	if err := h.DB.Preload("Items.SKU.SPU").First(&order, "id = ?", id).Error; err != nil {
+	if err := h.DB.Preload("Items.SKU", internal.WithDeleted).Preload("Items.SKU.SPU", internal.WithDeleted).Preload("Shipments.Warehouse").First(&order, "id = ?", id).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "订单不存在"})
 		return
 	}
@@ -73,28 +80,40 @@ func (h *Handler) AdminGetOrder(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": gin.H{"order": order, "user": user}})
 }
 
-// AdminShipOrder 发货
+// AdminShipOrder 发货，订单的各仓库发货单一并发出
 func (h *Handler) AdminShipOrder(c *gin.Context) {
-	id := c.Param("id")
-
-	var order internal.Order
-	if err := h.DB.First(&order, "id = ?", id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "订单不存在"})
+	err := h.AdminWarehouseService.ShipOrder(c.Param("id"), c.GetString("adminID"))
+	if errors.Is(err, admin_services.ErrOrderNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
-
-	if order.Status != internal.OrderStatusToSend {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "订单状态不正确，无法发货"})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	order.Status = internal.OrderStatusToReceive
-	order.UpdatedAt = time.Now().UnixMilli()
-	h.DB.Save(&order)
-
 	c.JSON(http.StatusOK, gin.H{"message": "发货成功"})
 }
 
+// errOrderStatusChanged 条件更新未命中：订单状态已被并发修改
+var errOrderStatusChanged = errors.New("订单状态已变化，请刷新后重试")
+
+// refundOrder 退款完成时在事务中归还下单实际扣减的库存、将余额支付部分退回储值钱包并冲正推荐佣金，
+// 返回退回钱包的金额。调用方需先以条件更新把订单改为退款完成，保证只执行一次
+func (h *Handler) refundOrder(tx *gorm.DB, orderID, adminID string) (float64, error) {
+	if err := h.InventoryService.WithTx(tx).RestoreOrder(orderID, internal.StockReasonRefund, adminID, "订单退款"); err != nil {
+		return 0, fmt.Errorf("恢复库存失败: %w", err)
+	}
+	balanceRefund, err := h.WalletService.WithTx(tx).RefundOrder(orderID)
+	if err != nil {
+		return 0, fmt.Errorf("退款失败: %w", err)
+	}
+	if _, err := h.CommissionService.WithTx(tx).ReverseOrderCommissions(orderID); err != nil {
+		return 0, fmt.Errorf("冲正佣金失败: %w", err)
+	}
+	return balanceRefund, nil
+}
+
 // AdminRefundOrder 退款
 func (h *Handler) AdminRefundOrder(c *gin.Context) {
 	id := c.Param("id")
@@ -110,22 +129,36 @@ func (h *Handler) AdminRefundOrder(c *gin.Context) {
 		return
 	}
 
-	tx := h.DB.Begin()
+	var balanceRefund float64
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		// 条件更新，并发退款或状态修改时只有一个请求会归还库存和退款
+		result := tx.Model(&internal.Order{}).
+			Where("id = ? AND status IN ?", id, []string{internal.OrderStatusToSend, internal.OrderStatusToReceive}).
+			Updates(map[string]interface{}{
+				"status":     internal.OrderStatusReturnFinish,
+				"updated_at": time.Now().UnixMilli(),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("更新订单状态失败: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return errOrderStatusChanged
+		}
 
-	// 恢复库存
-	var items []internal.OrderItem
-	h.DB.Where("order_id = ?", id).Find(&items)
-	for _, item := range items {
-		tx.Model(&internal.SKU{}).Where("id = ?", item.SKUID).Update("count", gorm.Expr("count + ?", item.Quantity))
+		var err error
+		balanceRefund, err = h.refundOrder(tx, id, c.GetString("adminID"))
+		return err
+	})
+	if errors.Is(err, errOrderStatusChanged) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
 
-	order.Status = internal.OrderStatusReturnFinish
-	order.UpdatedAt = time.Now().UnixMilli()
-	tx.Save(&order)
-
-	tx.Commit()
-
-	c.JSON(http.StatusOK, gin.H{"message": "退款成功", "refundAmount": order.FinalPrice})
+	c.JSON(http.StatusOK, gin.H{"message": "退款成功", "refundAmount": order.FinalPrice, "balanceRefund": balanceRefund})
 }
 
 // AdminUpdateOrderStatus 更新订单状态
@@ -146,9 +179,54 @@ func (h *Handler) AdminUpdateOrderStatus(c *gin.Context) {
 		return
 	}
 
-	order.Status = req.Status
-	order.UpdatedAt = time.Now().UnixMilli()
-	h.DB.Save(&order)
+	// 状态更新、归还库存、余额退款和佣金处理在同一事务中完成，任一步失败整体回滚
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		// 条件更新，避免并发修改时重复归还库存或退款
+		result := tx.Model(&internal.Order{}).Where("id = ? AND status = ?", id, order.Status).
+			Updates(map[string]interface{}{
+				"status":     req.Status,
+				"updated_at": time.Now().UnixMilli(),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("更新订单状态失败: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return errOrderStatusChanged
+		}
+		if req.Status == order.Status {
+			return nil
+		}
+
+		switch req.Status {
+		case internal.OrderStatusCanceled:
+			// 取消订单时归还下单实际扣减的库存，余额支付部分退回储值钱包
+			if err := h.InventoryService.WithTx(tx).RestoreOrder(id, internal.StockReasonCancel, c.GetString("adminID"), "取消订单"); err != nil {
+				return fmt.Errorf("恢复库存失败: %w", err)
+			}
+			if _, err := h.WalletService.WithTx(tx).RefundOrder(id); err != nil {
+				return fmt.Errorf("退款失败: %w", err)
+			}
+		case internal.OrderStatusReturnFinish:
+			// 与退款接口一致：归还库存、退回余额并冲正佣金
+			if _, err := h.refundOrder(tx, id, c.GetString("adminID")); err != nil {
+				return err
+			}
+		case internal.OrderStatusFinished:
+			// 订单完成生成佣金
+			if _, err := h.CommissionService.WithTx(tx).CreateOrderCommissions(id); err != nil {
+				return fmt.Errorf("生成佣金失败: %w", err)
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, errOrderStatusChanged) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "状态更新成功"})
 }
@@ -8,6 +8,7 @@ import (
 	"z26b-backend/internal"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // AdminGetProducts 获取商品列表
@@ -35,13 +36,18 @@ func (h *Handler) AdminGetProducts(c *gin.Context) {
 	if status != "" {
 		query = query.Where("status = ?", status)
 	}
+	if c.Query("scheduled") == "true" {
+		query = query.Where("publish_at IS NOT NULL OR unpublish_at IS NOT NULL")
+	}
 
 	query.Count(&total)
 	offset := (page - 1) * pageSize
 	query.Preload("Category").Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&products)
 
-	// Load tags
+	// Load tags and effective status
+	now := time.Now().UnixMilli()
 	for i := range products {
+		products[i].EffectiveStatus = internal.SPUEffectiveStatus(products[i].Status, products[i].PublishAt, products[i].UnpublishAt, now)
 		var spuTags []internal.SPUTag
 		h.DB.Preload("Tag").Where("spu_id = ?", products[i].ID).Find(&spuTags)
 		var tags []internal.Tag
@@ -116,7 +122,7 @@ func (h *Handler) AdminCreateProduct(c *gin.Context) {
 	adminID := c.GetString("adminID")
 
 	product := internal.SPU{
-		ID: internal.GenerateUUID(), Name: req.Name, Detail: req.Detail,
+		ID: internal.GenerateUUID(), Name: req.Name, Detail: internal.SanitizeHTML(req.Detail),
 		CoverImage: req.CoverImage, CategoryID: req.CategoryID,
 		Status: status, Priority: req.Priority, CreatedAt: now, UpdatedAt: now,
 		CreatedBy: adminID, UpdatedBy: adminID,
@@ -165,6 +171,7 @@ func (h *Handler) AdminCreateProduct(c *gin.Context) {
 	}
 
 	tx.Commit()
+	h.syncSearchIndex(product.ID)
 	c.JSON(http.StatusCreated, gin.H{"data": product})
 }
 
@@ -200,13 +207,14 @@ func (h *Handler) AdminUpdateProduct(c *gin.Context) {
 	}
 
 	adminID := c.GetString("adminID")
+	before := h.snapshotProduct(id)
 	updates := map[string]interface{}{"updated_at": time.Now().UnixMilli(), "updated_by": adminID}
 
 	if req.Name != "" {
 		updates["name"] = req.Name
 	}
 	if req.Detail != "" {
-		updates["detail"] = req.Detail
+		updates["detail"] = internal.SanitizeHTML(req.Detail)
 	}
 	if req.CoverImage != "" {
 		updates["cover_image"] = req.CoverImage
@@ -227,8 +235,8 @@ func (h *Handler) AdminUpdateProduct(c *gin.Context) {
 	tx := h.DB.Begin()
 	tx.Model(&product).Updates(updates)
 
-	// Update tags - 总是先删除旧的关联
-	tx.Where("spu_id = ?", id).Delete(&internal.SPUTag{})
+	// Update tags - 总是先删除旧的关联（回收站中标签的关联保留，以便恢复标签时一并恢复）
+	tx.Where("spu_id = ? AND tag_id IN (SELECT id FROM tag WHERE deleted_at IS NULL)", id).Delete(&internal.SPUTag{})
 
 	// 然后创建新的关联
 	if len(req.Tags) > 0 {
@@ -262,6 +270,8 @@ func (h *Handler) AdminUpdateProduct(c *gin.Context) {
 	}
 
 	tx.Commit()
+	h.recordRevision(id, internal.RevisionActionUpdate, adminID, before)
+	h.syncSearchIndex(id)
 	h.DB.Preload("Category").First(&product, "id = ?", id)
 
 	var spuTags []internal.SPUTag
@@ -277,7 +287,7 @@ func (h *Handler) AdminUpdateProduct(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": product})
 }
 
-// AdminDeleteProduct 删除商品
+// AdminDeleteProduct 删除商品（移入回收站）
 func (h *Handler) AdminDeleteProduct(c *gin.Context) {
 	id := c.Param("id")
 
@@ -287,11 +297,11 @@ func (h *Handler) AdminDeleteProduct(c *gin.Context) {
 		return
 	}
 
-	tx := h.DB.Begin()
-	tx.Where(`"SPUID" = ?`, id).Delete(&internal.SKU{})
-	tx.Where("spu_id = ?", id).Delete(&internal.SPUTag{})
-	tx.Delete(&product)
-	tx.Commit()
+	if err := h.AdminGoodsService.DeleteProduct(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除失败"})
+		return
+	}
+	h.syncSearchIndex(id)
 
 	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
 }
@@ -306,18 +316,50 @@ func (h *Handler) AdminToggleProductStatus(c *gin.Context) {
 		return
 	}
 
+	// 以考虑定时上下架后的实际状态为准切换，已到期的定时计划一并清除
+	now := time.Now().UnixMilli()
 	newStatus := "DISABLED"
-	if product.Status == "DISABLED" {
+	if internal.SPUEffectiveStatus(product.Status, product.PublishAt, product.UnpublishAt, now) == "DISABLED" {
 		newStatus = "ENABLED"
 	}
+	updates := map[string]interface{}{"status": newStatus, "updated_at": now}
+	if product.PublishAt != nil && *product.PublishAt <= now {
+		updates["publish_at"] = nil
+	}
+	if product.UnpublishAt != nil && *product.UnpublishAt <= now {
+		updates["unpublish_at"] = nil
+	}
 
-	h.DB.Model(&product).Updates(map[string]interface{}{
-		"status": newStatus, "updatedAt": time.Now().UnixMilli(),
-	})
+	before := h.snapshotProduct(id)
+	h.DB.Model(&product).Updates(updates)
+	h.recordRevision(id, internal.RevisionActionStatus, c.GetString("adminID"), before)
+	h.syncSearchIndex(id)
 
 	c.JSON(http.StatusOK, gin.H{"status": newStatus})
 }
 
+// AdminScheduleProduct 设置商品定时上架/下架时间
+func (h *Handler) AdminScheduleProduct(c *gin.Context) {
+	id := c.Param("id")
+
+	var req struct {
+		PublishAt   *int64 `json:"publishAt"`   // 毫秒时间戳，null 表示取消
+		UnpublishAt *int64 `json:"unpublishAt"` // 毫秒时间戳，null 表示取消
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	if err := h.AdminGoodsService.ScheduleProduct(id, req.PublishAt, req.UnpublishAt, c.GetString("adminID")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.syncSearchIndex(id)
+
+	c.JSON(http.StatusOK, gin.H{"data": req, "message": "保存成功"})
+}
+
 // AdminGetSKUs 获取商品SKU列表
 func (h *Handler) AdminGetSKUs(c *gin.Context) {
 	id := c.Param("id")
@@ -329,11 +371,12 @@ func (h *Handler) AdminGetSKUs(c *gin.Context) {
 // AdminCreateSKU 创建SKU
 func (h *Handler) AdminCreateSKU(c *gin.Context) {
 	var req struct {
-		SPUID       string  `json:"spuId" binding:"required"`
-		Description string  `json:"description" binding:"required"`
-		Image       string  `json:"image"`
-		Price       float64 `json:"price" binding:"required"`
-		Count       int     `json:"count"`
+		SPUID       string   `json:"spuId" binding:"required"`
+		Description string   `json:"description" binding:"required"`
+		Image       string   `json:"image"`
+		Price       float64  `json:"price" binding:"required"`
+		Count       int      `json:"count"`
+		SpecValues  []string `json:"specValues"` // 规格值组合，与商品规格维度顺序一致
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -350,13 +393,35 @@ func (h *Handler) AdminCreateSKU(c *gin.Context) {
 		CreatedAt: now, UpdatedAt: now, CreatedBy: adminID, UpdatedBy: adminID,
 	}
 
-	if err := h.DB.Create(&sku).Error; err != nil {
+	// 绑定规格组合
+	if len(req.SpecValues) > 0 {
+		if err := h.AdminGoodsService.ValidateSKUSpec(req.SPUID, req.SpecValues, ""); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		sku.SpecValues = internal.ToJSON(req.SpecValues)
+		sku.SpecKey = internal.SpecKey(req.SpecValues)
+	}
+
+	before := h.snapshotProduct(req.SPUID)
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&sku).Error; err != nil {
+			return err
+		}
+		if err := h.InventoryService.WithTx(tx).RecordCreated(&sku, internal.StockReasonInitial, "", adminID, ""); err != nil {
+			return err
+		}
+		// 记录初始价格并更新 SPU 价格范围
+		changes := []internal.SKUPriceHistory{internal.PriceChange(sku.ID, nil, sku.Price)}
+		return internal.RecordSKUPriceChanges(tx, req.SPUID, changes, internal.PriceSourceCreate, adminID)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "创建SKU失败: " + err.Error()})
 		return
 	}
 
-	// 更新 SPU 价格范围
-	h.updateSPUPriceRange(req.SPUID)
+	h.recordRevision(req.SPUID, internal.RevisionActionSKUCreate, adminID, before)
+	h.syncSearchIndex(req.SPUID)
 
 	c.JSON(http.StatusCreated, gin.H{"data": sku})
 }
@@ -376,6 +441,9 @@ func (h *Handler) AdminUpdateSKU(c *gin.Context) {
 		Image       *string  `json:"image"`
 		Price       *float64 `json:"price"`
 		Count       *int     `json:"count"`
+		StockRemark string   `json:"stockRemark"` // 库存调整备注，记入库存流水
+		Threshold   *int     `json:"lowStockThreshold"`
+		SpecValues  []string `json:"specValues"` // 传入空数组时解除规格绑定
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -398,50 +466,150 @@ func (h *Handler) AdminUpdateSKU(c *gin.Context) {
 		updates["price"] = *req.Price
 		priceChanged = true
 	}
-	if req.Count != nil {
-		updates["count"] = *req.Count
+	if req.Threshold != nil {
+		if *req.Threshold < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "预警值不能为负数"})
+			return
+		}
+		updates["low_stock_threshold"] = *req.Threshold
+	}
+	if req.SpecValues != nil {
+		if len(req.SpecValues) == 0 {
+			updates["spec_values"] = nil
+			updates["spec_key"] = ""
+		} else {
+			if err := h.AdminGoodsService.ValidateSKUSpec(sku.SPUID, req.SpecValues, sku.ID); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			updates["spec_values"] = internal.ToJSON(req.SpecValues)
+			updates["spec_key"] = internal.SpecKey(req.SpecValues)
+		}
 	}
 
 	// 只有当有实际更新时才更新时间戳和操作人
-	if len(updates) > 0 {
+	if len(updates) > 0 || req.Count != nil {
 		updates["updated_at"] = now
 		updates["updated_by"] = adminID
 	}
 
-	h.DB.Model(&sku).Updates(updates)
+	before := h.snapshotProduct(sku.SPUID)
+	oldPrice := sku.Price
+	var stockErr error
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		// 库存经库存服务调整，记录流水，与其他字段在同一事务内生效
+		if req.Count != nil {
+			if _, stockErr = h.InventoryService.WithTx(tx).SetCount(sku.ID, *req.Count, internal.StockReasonAdjust, "", adminID, req.StockRemark); stockErr != nil {
+				return stockErr
+			}
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(&sku).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		// 价格变更记录调价历史并更新 SPU 价格范围
+		if !priceChanged {
+			return nil
+		}
+		changes := []internal.SKUPriceHistory{internal.PriceChange(sku.ID, &oldPrice, *req.Price)}
+		return internal.RecordSKUPriceChanges(tx, sku.SPUID, changes, internal.PriceSourceUpdate, adminID)
+	})
+	if stockErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": stockErr.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "更新SKU失败: " + err.Error()})
+		return
+	}
 	h.DB.First(&sku, "id = ?", id)
 
-	// 如果价格变更，更新 SPU 价格范围
 	if priceChanged {
-		h.updateSPUPriceRange(sku.SPUID)
+		h.syncSearchIndex(sku.SPUID)
 	}
+	h.recordRevision(sku.SPUID, internal.RevisionActionSKUUpdate, adminID, before)
 
 	c.JSON(http.StatusOK, gin.H{"data": sku})
 }
 
-// AdminDeleteSKU 删除SKU
+// AdminGetSKUPriceHistory 获取 SKU 调价记录
+func (h *Handler) AdminGetSKUPriceHistory(c *gin.Context) {
+	page, pageSize := inventoryPage(c)
+	history, total, err := h.AdminGoodsService.GetPriceHistory(c.Param("id"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取调价记录失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"list": history, "total": total, "page": page, "pageSize": pageSize}})
+}
+
+// AdminSaveProductSpecs 保存商品规格维度
+func (h *Handler) AdminSaveProductSpecs(c *gin.Context) {
+	var req struct {
+		Specs []internal.SpecDimension `json:"specs"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	id := c.Param("id")
+	before := h.snapshotProduct(id)
+	if err := h.AdminGoodsService.SaveSpecs(id, req.Specs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.recordRevision(id, internal.RevisionActionSpecs, c.GetString("adminID"), before)
+	c.JSON(http.StatusOK, gin.H{"data": req.Specs, "message": "保存成功"})
+}
+
+// AdminGenerateSKUMatrix 按规格维度生成全部组合的 SKU，已有组合保持不变
+func (h *Handler) AdminGenerateSKUMatrix(c *gin.Context) {
+	id := c.Param("id")
+
+	var req struct {
+		Specs []internal.SpecDimension `json:"specs" binding:"required"`
+		Price float64                  `json:"price"` // 新生成 SKU 的默认价格
+		Count int                      `json:"count"` // 新生成 SKU 的默认库存
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请设置规格维度"})
+		return
+	}
+
+	adminID := c.GetString("adminID")
+	before := h.snapshotProduct(id)
+	result, err := h.AdminGoodsService.GenerateSKUMatrix(id, req.Specs, req.Price, req.Count, adminID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(result.Created) > 0 {
+		h.syncSearchIndex(id)
+	}
+	h.recordRevision(id, internal.RevisionActionSpecs, adminID, before)
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// AdminDeleteSKU 删除SKU（移入回收站）
 func (h *Handler) AdminDeleteSKU(c *gin.Context) {
 	id := c.Param("id")
 
 	// 先获取 SKU 信息以便后续更新 SPU 价格
 	var sku internal.SKU
 	if err := h.DB.First(&sku, "id = ?", id).Error; err == nil {
+		before := h.snapshotProduct(sku.SPUID)
 		h.DB.Delete(&internal.SKU{}, "id = ?", id)
 		// 更新 SPU 价格范围
-		h.updateSPUPriceRange(sku.SPUID)
+		internal.UpdateSPUPriceRange(h.DB, sku.SPUID)
+		h.recordRevision(sku.SPUID, internal.RevisionActionSKUDelete, c.GetString("adminID"), before)
+		h.syncSearchIndex(sku.SPUID)
 	} else {
 		h.DB.Delete(&internal.SKU{}, "id = ?", id)
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
 }
-
-// updateSPUPriceRange 更新 SPU 的价格范围
-func (h *Handler) updateSPUPriceRange(spuID string) {
-	h.DB.Exec(`
-		UPDATE spu SET 
-			min_price = COALESCE((SELECT MIN(price) FROM sku WHERE "SPUID" = ?), 0),
-			max_price = COALESCE((SELECT MAX(price) FROM sku WHERE "SPUID" = ?), 0)
-		WHERE id = ?
-	`, spuID, spuID, spuID)
-}
@@ -0,0 +1,125 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	"z26b-backend/services/admin_services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminGetQuestions 获取商品问答列表，支持按审核状态、商品和关键词筛选
+func (h *Handler) AdminGetQuestions(c *gin.Context) {
+	page, pageSize := inventoryPage(c)
+	questions, total, err := h.AdminQuestionService.GetQuestions(c.Query("status"), c.Query("spuId"), c.Query("keyword"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取问答列表失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"list": questions, "total": total, "page": page, "pageSize": pageSize}})
+}
+
+// AdminGetAnswers 获取回答列表，用于回答审核
+func (h *Handler) AdminGetAnswers(c *gin.Context) {
+	page, pageSize := inventoryPage(c)
+	answers, total, err := h.AdminQuestionService.GetAnswers(c.Query("status"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取回答列表失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"list": answers, "total": total, "page": page, "pageSize": pageSize}})
+}
+
+// qaReviewRequest 问答审核请求
+type qaReviewRequest struct {
+	Status string `json:"status" binding:"required"` // PENDING / APPROVED / REJECTED
+	Reason string `json:"reason"`                    // 驳回原因
+}
+
+// AdminReviewQuestion 审核提问
+func (h *Handler) AdminReviewQuestion(c *gin.Context) {
+	var req qaReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+	err := h.AdminQuestionService.ReviewQuestion(c.Param("id"), req.Status, req.Reason)
+	if errors.Is(err, admin_services.ErrQuestionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "审核成功"})
+}
+
+// AdminReviewAnswer 审核回答
+func (h *Handler) AdminReviewAnswer(c *gin.Context) {
+	var req qaReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+	err := h.AdminQuestionService.ReviewAnswer(c.Param("id"), req.Status, req.Reason)
+	if errors.Is(err, admin_services.ErrAnswerNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "审核成功"})
+}
+
+// AdminAnswerQuestion 商家回答提问
+func (h *Handler) AdminAnswerQuestion(c *gin.Context) {
+	var req struct {
+		Content string `json:"content" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请填写回答内容"})
+		return
+	}
+	answer, err := h.AdminQuestionService.AnswerQuestion(c.Param("id"), c.GetString("adminID"), req.Content)
+	if errors.Is(err, admin_services.ErrQuestionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": answer})
+}
+
+// AdminDeleteQuestion 删除提问及其回答
+func (h *Handler) AdminDeleteQuestion(c *gin.Context) {
+	err := h.AdminQuestionService.DeleteQuestion(c.Param("id"))
+	if errors.Is(err, admin_services.ErrQuestionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// AdminDeleteAnswer 删除回答
+func (h *Handler) AdminDeleteAnswer(c *gin.Context) {
+	err := h.AdminQuestionService.DeleteAnswer(c.Param("id"))
+	if errors.Is(err, admin_services.ErrAnswerNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
@@ -0,0 +1,87 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"z26b-backend/internal"
+	admin_services "z26b-backend/services/admin_services"
+	"z26b-backend/services/history"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminGetRecycleBin 获取回收站列表，type=product|sku|category|tag
+func (h *Handler) AdminGetRecycleBin(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	items, total, err := h.AdminRecycleBinService.GetItems(c.DefaultQuery("type", admin_services.RecycleTypeProduct), c.Query("keyword"), page, pageSize)
+	if err != nil {
+		h.recycleBinError(c, err, "获取回收站失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"list": items, "total": total, "page": page, "pageSize": pageSize}})
+}
+
+// AdminRestoreRecycleBinItem 从回收站恢复；恢复商品时一并恢复随商品删除的 SKU
+func (h *Handler) AdminRestoreRecycleBinItem(c *gin.Context) {
+	itemType, id := c.Param("type"), c.Param("id")
+	adminID := c.GetString("adminID")
+
+	// SKU、标签恢复会改变商品内容，记录变更历史
+	var snapshots map[string]*history.ProductSnapshot
+	switch itemType {
+	case admin_services.RecycleTypeSKU:
+		var sku internal.SKU
+		if err := h.DB.Unscoped().Select("id", "SPUID").First(&sku, "id = ?", id).Error; err == nil {
+			snapshots = map[string]*history.ProductSnapshot{sku.SPUID: h.snapshotProduct(sku.SPUID)}
+		}
+	case admin_services.RecycleTypeTag:
+		snapshots = h.snapshotTagProducts(id)
+	}
+
+	spuIDs, err := h.AdminRecycleBinService.Restore(itemType, id)
+	if err != nil {
+		h.recycleBinError(c, err, "恢复失败")
+		return
+	}
+
+	for spuID, before := range snapshots {
+		h.recordRevision(spuID, internal.RevisionActionRecycle, adminID, before)
+	}
+	for _, spuID := range spuIDs {
+		h.syncSearchIndex(spuID)
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "恢复成功"})
+}
+
+// AdminPurgeRecycleBinItem 彻底删除回收站中的记录
+func (h *Handler) AdminPurgeRecycleBinItem(c *gin.Context) {
+	if err := h.AdminRecycleBinService.Purge(c.Param("type"), c.Param("id")); err != nil {
+		h.recycleBinError(c, err, "彻底删除失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "已彻底删除"})
+}
+
+// recycleBinError 回收站业务错误返回具体原因，其他错误返回通用提示
+func (h *Handler) recycleBinError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, admin_services.ErrRecycleItemNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, admin_services.ErrInvalidRecycleType),
+		errors.Is(err, admin_services.ErrRestoreBlocked),
+		errors.Is(err, admin_services.ErrPurgeBlocked):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,41 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+
+	"z26b-backend/internal"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminRebuildSearchIndex 全量重建商品搜索索引
+func (h *Handler) AdminRebuildSearchIndex(c *gin.Context) {
+	count, err := h.SearchService.Rebuild()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "重建搜索索引失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "重建成功", "count": count})
+}
+
+// AdminGetSearchReport 搜索词报表：热门搜索词和无结果搜索词
+func (h *Handler) AdminGetSearchReport(c *gin.Context) {
+	startTime, _ := strconv.ParseInt(c.Query("startTime"), 10, 64)
+	endTime, _ := strconv.ParseInt(c.Query("endTime"), 10, 64)
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	report, err := h.SearchService.QueryReport(startTime, endTime, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取搜索报表失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// syncSearchIndex 商品变更后同步搜索索引，失败只记录日志不影响主流程
+func (h *Handler) syncSearchIndex(spuID string) {
+	if err := h.SearchService.IndexProduct(spuID); err != nil {
+		internal.GlobalLogger.Warn("Failed to sync search index", map[string]interface{}{"spuId": spuID, "error": err.Error()})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"z26b-backend/internal"
+	"z26b-backend/services/history"
 
 	"github.com/gin-gonic/gin"
 )
@@ -47,10 +48,11 @@ func (h *Handler) AdminCreateTag(c *gin.Context) {
 		return
 	}
 
+	// 回收站中的标签也占用名称
 	var count int64
-	h.DB.Model(&internal.Tag{}).Where("name = ?", input.Name).Count(&count)
+	h.DB.Unscoped().Model(&internal.Tag{}).Where("name = ?", input.Name).Count(&count)
 	if count > 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"code": 400, "error": "标签名称已存在"})
+		c.JSON(http.StatusBadRequest, gin.H{"code": 400, "error": "标签名称已存在（或在回收站中）"})
 		return
 	}
 
@@ -96,9 +98,9 @@ func (h *Handler) AdminUpdateTag(c *gin.Context) {
 
 	if input.Name != "" && input.Name != tag.Name {
 		var count int64
-		h.DB.Model(&internal.Tag{}).Where("name = ? AND id != ?", input.Name, id).Count(&count)
+		h.DB.Unscoped().Model(&internal.Tag{}).Where("name = ? AND id != ?", input.Name, id).Count(&count)
 		if count > 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"code": 400, "error": "标签名称已存在"})
+			c.JSON(http.StatusBadRequest, gin.H{"code": 400, "error": "标签名称已存在（或在回收站中）"})
 			return
 		}
 		tag.Name = input.Name
@@ -119,11 +121,27 @@ func (h *Handler) AdminUpdateTag(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"code": 0, "data": tag})
 }
 
-// AdminDeleteTag 删除标签
+// AdminDeleteTag 删除标签（移入回收站）
 func (h *Handler) AdminDeleteTag(c *gin.Context) {
 	id := c.Param("id")
 
-	h.DB.Where("tag_id = ?", id).Delete(&internal.SPUTag{})
+	// 移入回收站，商品关联保留以便恢复
+	snapshots := h.snapshotTagProducts(id)
 	h.DB.Delete(&internal.Tag{}, "id = ?", id)
+	for spuID, before := range snapshots {
+		h.recordRevision(spuID, internal.RevisionActionTagRemoved, c.GetString("adminID"), before)
+		h.syncSearchIndex(spuID)
+	}
 	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "删除成功"})
 }
+
+// snapshotTagProducts 标签变更前获取关联商品的快照
+func (h *Handler) snapshotTagProducts(tagID string) map[string]*history.ProductSnapshot {
+	var spuIDs []string
+	h.DB.Model(&internal.SPUTag{}).Where("tag_id = ? AND spu_id IN (SELECT id FROM spu WHERE deleted_at IS NULL)", tagID).Distinct().Pluck("spu_id", &spuIDs)
+	snapshots := make(map[string]*history.ProductSnapshot, len(spuIDs))
+	for _, spuID := range spuIDs {
+		snapshots[spuID] = h.snapshotProduct(spuID)
+	}
+	return snapshots
+}
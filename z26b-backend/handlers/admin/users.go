@@ -66,7 +66,7 @@ func (h *Handler) AdminGetUserOrders(c *gin.Context) {
 	query := h.DB.Model(&internal.Order{}).Where("userId = ?", id)
 	query.Count(&total)
 	offset := (page - 1) * pageSize
-	query.Preload("Items.SKU").Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&orders)
+	query.Preload("Items.SKU", internal.WithDeleted).Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&orders)
 
 	c.JSON(http.StatusOK, gin.H{
 		"data": gin.H{"list": orders, "total": total, "page": page, "pageSize": pageSize},
@@ -85,9 +85,10 @@ func (h *Handler) AdminCreateTestUser(c *gin.Context) {
 	}
 
 	user := internal.User{
-		ID:       internal.GenerateUUID(),
-		OpenID:   "test_" + internal.GenerateUUID()[:8],
-		NickName: req.NickName,
+		ID:           internal.GenerateUUID(),
+		OpenID:       "test_" + internal.GenerateUUID()[:8],
+		NickName:     req.NickName,
+		ReferralCode: internal.GenerateReferralCode(),
 	}
 	h.DB.Create(&user)
 
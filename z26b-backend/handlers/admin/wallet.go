@@ -0,0 +1,105 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"z26b-backend/services/wallet"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminGetWallets 获取储值钱包列表
+func (h *Handler) AdminGetWallets(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+	keyword := c.Query("keyword")
+
+	wallets, total, err := h.WalletService.GetWallets(page, pageSize, keyword)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取钱包列表失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{"list": wallets, "total": total, "page": page, "pageSize": pageSize},
+	})
+}
+
+// AdminGetWallet 获取用户储值钱包
+func (h *Handler) AdminGetWallet(c *gin.Context) {
+	userWallet, err := h.WalletService.GetOrCreateWallet(c.Param("userId"))
+	if errors.Is(err, wallet.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取钱包失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": userWallet})
+}
+
+// AdminGetWalletTransactions 获取用户钱包流水（含复式记账分录）
+func (h *Handler) AdminGetWalletTransactions(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	txnType := c.Query("type")
+
+	txns, total, err := h.WalletService.GetTransactions(c.Param("userId"), txnType, page, pageSize, true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取钱包流水失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{"list": txns, "total": total, "page": page, "pageSize": pageSize},
+	})
+}
+
+// AdminTopUpWallet 储值充值
+func (h *Handler) AdminTopUpWallet(c *gin.Context) {
+	var req struct {
+		Amount float64 `json:"amount" binding:"required"`
+		Remark string  `json:"remark"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请填写充值金额"})
+		return
+	}
+
+	txn, err := h.WalletService.TopUp(c.Param("userId"), req.Amount, c.GetString("adminID"), req.Remark)
+	if errors.Is(err, wallet.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": txn})
+}
+
+// AdminAdjustWallet 人工调整余额，金额为负数时扣减
+func (h *Handler) AdminAdjustWallet(c *gin.Context) {
+	var req struct {
+		Amount float64 `json:"amount" binding:"required"`
+		Remark string  `json:"remark" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请填写调整金额和原因"})
+		return
+	}
+
+	txn, err := h.WalletService.Adjust(c.Param("userId"), req.Amount, c.GetString("adminID"), req.Remark)
+	if errors.Is(err, wallet.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": txn})
+}
@@ -0,0 +1,185 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	"z26b-backend/internal"
+	"z26b-backend/services/admin_services"
+	"z26b-backend/services/inventory"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminGetWarehouses 获取仓库列表
+func (h *Handler) AdminGetWarehouses(c *gin.Context) {
+	warehouses, err := h.AdminWarehouseService.GetWarehouses(c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取仓库列表失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": warehouses})
+}
+
+// AdminCreateWarehouse 创建仓库
+func (h *Handler) AdminCreateWarehouse(c *gin.Context) {
+	var req struct {
+		Code     string   `json:"code" binding:"required"`
+		Name     string   `json:"name" binding:"required"`
+		Address  string   `json:"address"`
+		Regions  []string `json:"regions"` // 优先发货的省份（名称或编码）
+		Priority int      `json:"priority"`
+		Status   string   `json:"status"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请填写仓库名称和编码"})
+		return
+	}
+	if req.Regions == nil {
+		req.Regions = []string{}
+	}
+
+	warehouse := &internal.Warehouse{
+		Code:     req.Code,
+		Name:     req.Name,
+		Address:  req.Address,
+		Regions:  internal.ToJSON(req.Regions),
+		Priority: req.Priority,
+		Status:   req.Status,
+	}
+	if err := h.AdminWarehouseService.CreateWarehouse(warehouse); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": warehouse})
+}
+
+// AdminUpdateWarehouse 更新仓库
+func (h *Handler) AdminUpdateWarehouse(c *gin.Context) {
+	var req struct {
+		Code     *string  `json:"code"`
+		Name     *string  `json:"name"`
+		Address  *string  `json:"address"`
+		Regions  []string `json:"regions"`
+		Priority *int     `json:"priority"`
+		Status   string   `json:"status"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if req.Code != nil {
+		updates["code"] = *req.Code
+	}
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.Address != nil {
+		updates["address"] = *req.Address
+	}
+	if req.Regions != nil {
+		updates["regions"] = internal.ToJSON(req.Regions)
+	}
+	if req.Priority != nil {
+		updates["priority"] = *req.Priority
+	}
+	if req.Status != "" {
+		updates["status"] = req.Status
+	}
+
+	err := h.AdminWarehouseService.UpdateWarehouse(c.Param("id"), updates)
+	if errors.Is(err, admin_services.ErrWarehouseNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "更新成功"})
+}
+
+// AdminDeleteWarehouse 删除仓库
+func (h *Handler) AdminDeleteWarehouse(c *gin.Context) {
+	err := h.AdminWarehouseService.DeleteWarehouse(c.Param("id"))
+	if errors.Is(err, admin_services.ErrWarehouseNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// AdminGetWarehouseStock 获取仓库库存
+func (h *Handler) AdminGetWarehouseStock(c *gin.Context) {
+	page, pageSize := inventoryPage(c)
+	stocks, total, err := h.InventoryService.GetWarehouseStock(c.Param("id"), c.Query("keyword"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取仓库库存失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"list": stocks, "total": total, "page": page, "pageSize": pageSize}})
+}
+
+// AdminSetWarehouseStock 设置仓库中 SKU 的库存，差额同步到 SKU 总库存
+func (h *Handler) AdminSetWarehouseStock(c *gin.Context) {
+	var req struct {
+		SKUID  string `json:"skuId" binding:"required"`
+		Count  *int   `json:"count" binding:"required"`
+		Remark string `json:"remark"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请选择SKU并填写库存"})
+		return
+	}
+
+	movement, err := h.InventoryService.SetWarehouseCount(c.Param("id"), req.SKUID, *req.Count, internal.StockReasonAdjust, "", c.GetString("adminID"), req.Remark)
+	if errors.Is(err, inventory.ErrWarehouseNotFound) || errors.Is(err, inventory.ErrSKUNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": movement, "message": "设置成功"})
+}
+
+// AdminGetWarehouseShipments 仓库发货队列，status 默认为待发货
+func (h *Handler) AdminGetWarehouseShipments(c *gin.Context) {
+	page, pageSize := inventoryPage(c)
+	status := c.DefaultQuery("status", internal.ShipmentStatusPending)
+	if status == "all" {
+		status = ""
+	}
+	shipments, total, err := h.AdminWarehouseService.GetShipments(c.Param("id"), status, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取发货队列失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"list": shipments, "total": total, "page": page, "pageSize": pageSize}})
+}
+
+// AdminShipShipment 发货单发货，订单的发货单全部发出后订单变为待收货
+func (h *Handler) AdminShipShipment(c *gin.Context) {
+	var req struct {
+		TrackingNo string `json:"trackingNo"`
+	}
+	_ = c.ShouldBindJSON(&req)
+
+	shipment, err := h.AdminWarehouseService.ShipShipment(c.Param("id"), req.TrackingNo, c.GetString("adminID"))
+	if errors.Is(err, admin_services.ErrShipmentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": shipment, "message": "发货成功"})
+}
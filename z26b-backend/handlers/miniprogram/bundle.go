@@ -0,0 +1,39 @@
+package miniprogram
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetBundleList 获取组合商品列表
+func (h *Handler) GetBundleList(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	bundles, total, err := h.BundleService.GetBundles(page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch bundles"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"records": bundles, "total": total}})
+}
+
+// GetBundle 获取组合商品详情
+func (h *Handler) GetBundle(c *gin.Context) {
+	bundle, err := h.BundleService.GetBundle(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Bundle not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": bundle})
+}
@@ -1,22 +1,31 @@
 package miniprogram
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
-	"time"
 
 	"z26b-backend/internal"
+	miniprogram_services "z26b-backend/services/miniprogram"
 
 	"github.com/gin-gonic/gin"
 )
 
-// GetGoodsComments 获取商品评论
+// GetGoodsComments 获取商品评论（含商家回复），可按评分和是否带图筛选，同时用于 /goods/:id/comments 和 /comment/list/:spuId
 func (h *Handler) GetGoodsComments(c *gin.Context) {
 	spuID := c.Param("spuId")
+	if spuID == "" {
+		spuID = c.Param("id")
+	}
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
 
-	comments, total, err := h.CommentService.GetGoodsComments(spuID, page, pageSize)
+	var q miniprogram_services.CommentQuery
+	q.Score, _ = strconv.Atoi(c.Query("score"))
+	q.WithMedia, _ = strconv.ParseBool(c.Query("withMedia"))
+
+	comments, total, err := h.CommentService.GetGoodsComments(spuID, q, page, pageSize)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
 		return
@@ -27,41 +36,132 @@ func (h *Handler) GetGoodsComments(c *gin.Context) {
 	})
 }
 
-// SubmitComment 提交评论
+// SubmitComment 评价已完成订单中的商品，同时用于 /comment/submit 和 /order/submit-comment/:id
 func (h *Handler) SubmitComment(c *gin.Context) {
 	var req struct {
-		SPUID          string `json:"spuId" binding:"required"`
-		SKUID          string `json:"skuId"`
-		UserName       string `json:"userName"`
-		CommentContent string `json:"commentContent" binding:"required"`
-		CommentScore   int    `json:"commentScore" binding:"required,min=1,max=5"`
-		IsAnonymity    bool   `json:"isAnonymity"`
+		OrderItemID    string   `json:"orderItemId"`
+		SKUID          string   `json:"skuId"` // 通过订单提交时可用 SKU 指定订单商品
+		CommentContent string   `json:"commentContent" binding:"required"`
+		CommentScore   int      `json:"commentScore" binding:"required,min=1,max=5"`
+		IsAnonymity    bool     `json:"isAnonymity"`
+		MediaIDs       []string `json:"mediaIds"` // 通过 /upload/comment-media 上传的图片/视频
 	}
 
-	if err := c.BindJSON(&req); err != nil {
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
 		return
 	}
 
-	userID := c.DefaultQuery("userId", "ANONYMOUS")
-
-	comment := &internal.Comment{
-		ID:             internal.GenerateUUID(),
-		SPUID:          req.SPUID,
-		SKUID:          req.SKUID,
-		UserID:         userID,
-		UserName:       req.UserName,
-		CommentContent: req.CommentContent,
-		CommentScore:   req.CommentScore,
-		IsAnonymity:    req.IsAnonymity,
-		CreatedAt:      time.Now().Unix(),
+	user, err := h.GetOrCreateUser(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return
 	}
 
-	err := h.CommentService.CreateComment(comment)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create comment"})
+	comment, err := h.CommentService.SubmitReview(user, miniprogram_services.ReviewInput{
+		OrderID:     c.Param("id"),
+		OrderItemID: req.OrderItemID,
+		SKUID:       req.SKUID,
+		Content:     req.CommentContent,
+		Score:       req.CommentScore,
+		IsAnonymity: req.IsAnonymity,
+		MediaIDs:    req.MediaIDs,
+	})
+	switch {
+	case errors.Is(err, miniprogram_services.ErrOrderItemNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, miniprogram_services.ErrOrderNotFinished):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, miniprogram_services.ErrAlreadyReviewed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	// 记录评价事件
+	go h.CRMEventService.RecordEvent(&internal.CRMEvent{
+		UserID:    user.ID,
+		EventType: internal.CRMEventTypeComment,
+		SPUID:     comment.SPUID,
+		SKUID:     comment.SKUID,
+		OrderID:   comment.OrderID,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+
 	c.JSON(http.StatusOK, gin.H{"data": comment})
 }
+
+// UploadCommentMedia 上传评价图片/视频，返回的ID在提交评价时通过 mediaIds 关联
+func (h *Handler) UploadCommentMedia(c *gin.Context) {
+	if !internal.IsMinIOInitialized() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Storage service unavailable"})
+		return
+	}
+
+	user, err := h.GetOrCreateUser(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+		return
+	}
+	defer file.Close()
+
+	// 按文件内容识别类型，不信任客户端提供的 Content-Type
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(file, head)
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+	mediaType, contentType, err := internal.ReviewMediaType(header.Filename, http.DetectContentType(head[:n]), header.Size)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.CommentService.CheckMediaQuota(user.ID, header.Size); err != nil {
+		if errors.Is(err, miniprogram_services.ErrMediaQuota) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check upload quota"})
+		return
+	}
+
+	url, err := internal.UploadFile(c.Request.Context(), file, header.Filename, contentType, header.Size)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Upload failed"})
+		return
+	}
+
+	media := &internal.ReviewMedia{
+		UserID:      user.ID,
+		Type:        mediaType,
+		URL:         url,
+		ContentType: contentType,
+		Size:        header.Size,
+	}
+	// 并发上传时以保存时的配额检查为准，未保存的文件随即删除
+	if err := h.CommentService.SaveMedia(media); err != nil {
+		if delErr := internal.DeleteFile(c.Request.Context(), url); delErr != nil {
+			internal.GlobalLogger.Warn("Failed to delete unsaved review media", map[string]interface{}{"url": url, "error": delErr.Error()})
+		}
+		if errors.Is(err, miniprogram_services.ErrMediaQuota) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save upload"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": media})
+}
@@ -3,7 +3,11 @@ package miniprogram
 import (
 	"z26b-backend/internal"
 	"z26b-backend/services/crm"
+	"z26b-backend/services/distribution"
 	miniprogram_services "z26b-backend/services/miniprogram"
+	"z26b-backend/services/recommend"
+	"z26b-backend/services/search"
+	"z26b-backend/services/wallet"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -11,14 +15,22 @@ import (
 
 // Handler 小程序端处理器
 type Handler struct {
-	GoodsService    miniprogram_services.GoodsServiceInterface
-	UserService     miniprogram_services.UserServiceInterface
-	CartService     miniprogram_services.CartServiceInterface
-	OrderService    miniprogram_services.OrderServiceInterface
-	CommentService  miniprogram_services.CommentServiceInterface
-	WechatService   miniprogram_services.WechatServiceInterface
-	CRMEventService *crm.CRMEventService
-	DB              *gorm.DB // 暂时保留，用于其他功能迁移
+	GoodsService         miniprogram_services.GoodsServiceInterface
+	UserService          miniprogram_services.UserServiceInterface
+	CartService          miniprogram_services.CartServiceInterface
+	OrderService         miniprogram_services.OrderServiceInterface
+	CommentService       miniprogram_services.CommentServiceInterface
+	BundleService        miniprogram_services.BundleServiceInterface
+	WechatService        miniprogram_services.WechatServiceInterface
+	FavoriteService      miniprogram_services.FavoriteServiceInterface
+	BrowseHistoryService miniprogram_services.BrowseHistoryServiceInterface
+	QuestionService      miniprogram_services.QuestionServiceInterface
+	CRMEventService      *crm.CRMEventService
+	CommissionService    *distribution.CommissionService
+	WalletService        *wallet.WalletService
+	SearchService        *search.SearchService
+	RecommendService     *recommend.RecommendService
+	DB                   *gorm.DB // 暂时保留，用于其他功能迁移
 }
 
 // NewHandler 创建处理器实例
@@ -28,19 +40,35 @@ func NewHandler(
 	cartService miniprogram_services.CartServiceInterface,
 	orderService miniprogram_services.OrderServiceInterface,
 	commentService miniprogram_services.CommentServiceInterface,
+	bundleService miniprogram_services.BundleServiceInterface,
 	wechatService miniprogram_services.WechatServiceInterface,
+	favoriteService miniprogram_services.FavoriteServiceInterface,
+	browseHistoryService miniprogram_services.BrowseHistoryServiceInterface,
+	questionService miniprogram_services.QuestionServiceInterface,
 	crmEventService *crm.CRMEventService,
+	commissionService *distribution.CommissionService,
+	walletService *wallet.WalletService,
+	searchService *search.SearchService,
+	recommendService *recommend.RecommendService,
 	db *gorm.DB,
 ) *Handler {
 	return &Handler{
-		GoodsService:    goodsService,
-		UserService:     userService,
-		CartService:     cartService,
-		OrderService:    orderService,
-		CommentService:  commentService,
-		WechatService:   wechatService,
-		CRMEventService: crmEventService,
-		DB:              db,
+		GoodsService:         goodsService,
+		UserService:          userService,
+		CartService:          cartService,
+		OrderService:         orderService,
+		CommentService:       commentService,
+		BundleService:        bundleService,
+		WechatService:        wechatService,
+		FavoriteService:      favoriteService,
+		BrowseHistoryService: browseHistoryService,
+		QuestionService:      questionService,
+		CRMEventService:      crmEventService,
+		CommissionService:    commissionService,
+		WalletService:        walletService,
+		SearchService:        searchService,
+		RecommendService:     recommendService,
+		DB:                   db,
 	}
 }
 
@@ -0,0 +1,90 @@
+package miniprogram
+
+import (
+	"net/http"
+	"strconv"
+
+	"z26b-backend/internal"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetFavorites 获取收藏列表
+func (h *Handler) GetFavorites(c *gin.Context) {
+	user, err := h.GetOrCreateUser(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	items, total, err := h.FavoriteService.GetFavorites(user.ID, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch favorites"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{"records": items, "total": total, "page": page, "pageSize": pageSize},
+	})
+}
+
+// AddFavorite 收藏商品
+func (h *Handler) AddFavorite(c *gin.Context) {
+	var req struct {
+		SPUID string `json:"spuId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	user, err := h.GetOrCreateUser(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return
+	}
+
+	favorite, created, err := h.FavoriteService.AddFavorite(user.ID, req.SPUID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 记录收藏事件
+	if created {
+		go h.CRMEventService.RecordEvent(&internal.CRMEvent{
+			UserID:    user.ID,
+			EventType: internal.CRMEventTypeFavorite,
+			SPUID:     req.SPUID,
+			IPAddress: c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": favorite})
+}
+
+// RemoveFavorite 取消收藏
+func (h *Handler) RemoveFavorite(c *gin.Context) {
+	user, err := h.GetOrCreateUser(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return
+	}
+
+	if err := h.FavoriteService.RemoveFavorite(user.ID, c.Param("spuId")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove favorite"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Removed from favorites"})
+}
@@ -1,10 +1,14 @@
 package miniprogram
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"z26b-backend/internal"
+	miniprogram_services "z26b-backend/services/miniprogram"
+	"z26b-backend/services/search"
 
 	"github.com/gin-gonic/gin"
 )
@@ -23,7 +27,37 @@ func (h *Handler) GetGoodsList(c *gin.Context) {
 		pageSize = 10
 	}
 
-	goods, total, err := h.GoodsService.GetGoodsList(page, pageSize, categoryID, search)
+	// tagIds 逗号分隔，tagMode=and 时需包含全部标签，默认命中任一标签；
+	// sort 取 default/price_asc/price_desc/sales/newest/rating
+	query := searchQuery(c)
+	if query.MinPrice != nil && query.MaxPrice != nil && *query.MinPrice > *query.MaxPrice {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "minPrice must not exceed maxPrice"})
+		return
+	}
+
+	// 带关键词时走搜索索引，未指定排序时按相关度排序
+	if search != "" {
+		query.Keyword = search
+		query.Page = page
+		query.PageSize = pageSize
+		result, err := h.SearchService.Search(query)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch goods"})
+			return
+		}
+		h.logSearch(c, search, result.Total)
+		c.JSON(http.StatusOK, gin.H{"data": gin.H{"records": result.Records, "total": result.Total}})
+		return
+	}
+
+	goods, total, err := h.GoodsService.GetGoodsList(page, pageSize, miniprogram_services.GoodsListQuery{
+		CategoryID: categoryID,
+		TagIDs:     query.TagIDs,
+		TagAll:     query.TagAll,
+		MinPrice:   query.MinPrice,
+		MaxPrice:   query.MaxPrice,
+		Sort:       query.Sort,
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch goods"})
 		return
@@ -42,12 +76,15 @@ func (h *Handler) GetGood(c *gin.Context) {
 		return
 	}
 
-	// 记录商品浏览事件
+	// 记录商品浏览事件和足迹
 	go func() {
 		user, _ := h.GetOrCreateUser(c)
 		userID := ""
 		if user != nil {
 			userID = user.ID
+			if err := h.BrowseHistoryService.RecordView(userID, id); err != nil {
+				internal.GlobalLogger.Warn("Failed to record browse history", map[string]interface{}{"spuId": id, "error": err.Error()})
+			}
 		}
 		h.CRMEventService.RecordEvent(&internal.CRMEvent{
 			UserID:    userID,
@@ -58,13 +95,64 @@ func (h *Handler) GetGood(c *gin.Context) {
 		})
 	}()
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": gin.H{
-			"_id": good.ID, "name": good.Name, "detail": good.Detail,
-			"cover_image": good.CoverImage, "swiper_images": good.SwipeImages,
-			"status": good.Status, "skus": skus,
-		},
-	})
+	specs := internal.ParseSpecs(good.Specs)
+	if specs == nil {
+		specs = []internal.SpecDimension{}
+	}
+
+	breadcrumbs, err := h.GoodsService.GetCategoryPath(good.CategoryID)
+	if err != nil {
+		breadcrumbs = []internal.Category{}
+	}
+
+	lowestPrice, err := h.GoodsService.GetLowestPrice(id)
+	if err != nil {
+		lowestPrice = good.MinPrice
+	}
+	favoriteCount, _ := h.FavoriteService.CountFavorites(id)
+	questionCount, _ := h.QuestionService.CountQuestions(id)
+	questions, _, err := h.QuestionService.GetQuestions(id, 1, 2)
+	if err != nil {
+		questions = []internal.ProductQuestion{}
+	}
+	isFavorite := false
+	if user, err := h.GetOrCreateUser(c); err == nil {
+		isFavorite, _ = h.FavoriteService.IsFavorite(user.ID, id)
+	}
+
+	// 历史数据可能保存于清洗规则上线前，输出前再清洗一次
+	detail := internal.SanitizeHTML(good.Detail)
+	data := gin.H{
+		"_id": good.ID, "name": good.Name, "detail": detail,
+		"cover_image": good.CoverImage, "swiper_images": good.SwipeImages,
+		"status": good.Status, "skus": skus, "tags": good.Tags,
+		"categoryId": good.CategoryID, "breadcrumbs": breadcrumbs,
+		"specs": specs, "skuMap": skuSpecMap(skus),
+		"favoriteCount": favoriteCount, "isFavorite": isFavorite,
+		"questionCount": questionCount, "questions": questions,
+		"lowestPrice30d": lowestPrice,
+	}
+	// richText=nodes 时附带 rich-text 组件可直接使用的节点树
+	if c.Query("richText") == "nodes" {
+		data["detailNodes"] = internal.MiniProgramRichText(detail)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// skuSpecMap 按规格组合键索引 SKU，供小程序规格选择器查询价格和库存
+func skuSpecMap(skus []internal.SKU) map[string]gin.H {
+	skuMap := make(map[string]gin.H, len(skus))
+	for _, sku := range skus {
+		if sku.SpecKey == "" {
+			continue
+		}
+		skuMap[sku.SpecKey] = gin.H{
+			"_id": sku.ID, "price": sku.Price, "count": sku.Count,
+			"image": sku.Image, "specValues": internal.ParseSpecValues(sku.SpecValues),
+		}
+	}
+	return skuMap
 }
 
 // GetSKU 获取SKU详情
@@ -101,6 +189,16 @@ func (h *Handler) GetCategories(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": categories})
 }
 
+// GetCategoryTree 获取树形分类
+func (h *Handler) GetCategoryTree(c *gin.Context) {
+	tree, err := h.GoodsService.GetCategoryTree()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch categories"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": tree})
+}
+
 // SearchGoods 搜索商品
 func (h *Handler) SearchGoods(c *gin.Context) {
 	keyword := c.Query("keyword")
@@ -114,11 +212,79 @@ func (h *Handler) SearchGoods(c *gin.Context) {
 		pageSize = 10
 	}
 
-	goods, total, err := h.GoodsService.SearchGoods(keyword, page, pageSize)
+	query := searchQuery(c)
+	query.Keyword = keyword
+	query.Page = page
+	query.PageSize = pageSize
+
+	result, err := h.SearchService.Search(query)
+	if errors.Is(err, search.ErrInvalidPriceRange) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "minPrice must not exceed maxPrice"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search goods"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": gin.H{"records": goods, "total": total}})
+	h.logSearch(c, keyword, result.Total)
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// SearchSuggest 搜索联想
+func (h *Handler) SearchSuggest(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	suggestions, err := h.SearchService.Suggest(c.Query("keyword"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch suggestions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": suggestions})
+}
+
+// GetHotKeywords 获取热门搜索词
+func (h *Handler) GetHotKeywords(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	keywords, err := h.SearchService.HotKeywords(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch hot keywords"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": keywords})
+}
+
+// logSearch 异步记录一次关键词搜索，搜索接口和带关键词的商品列表共用
+func (h *Handler) logSearch(c *gin.Context, keyword string, total int64) {
+	cc := c.Copy()
+	go func() {
+		userID := ""
+		if user, _ := h.GetOrCreateUser(cc); user != nil {
+			userID = user.ID
+		}
+		if err := h.SearchService.LogQuery(keyword, userID, total); err != nil {
+			internal.GlobalLogger.Warn("Failed to log search query", map[string]interface{}{"keyword": keyword, "error": err.Error()})
+		}
+	}()
+}
+
+// searchQuery 解析搜索筛选参数：分类、标签（逗号分隔）、价格区间
+func searchQuery(c *gin.Context) search.Query {
+	q := search.Query{CategoryID: c.Query("categoryId")}
+	if tagIDs := c.Query("tagIds"); tagIDs != "" {
+		q.TagIDs = strings.Split(tagIDs, ",")
+	}
+	q.TagAll = strings.EqualFold(c.Query("tagMode"), "and")
+	if v, err := strconv.ParseFloat(c.Query("minPrice"), 64); err == nil {
+		q.MinPrice = &v
+	}
+	if v, err := strconv.ParseFloat(c.Query("maxPrice"), 64); err == nil {
+		q.MaxPrice = &v
+	}
+	q.Sort = c.Query("sort")
+	return q
 }
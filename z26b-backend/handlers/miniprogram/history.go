@@ -0,0 +1,74 @@
+package miniprogram
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetBrowseHistory 获取浏览足迹（按日期分组）
+func (h *Handler) GetBrowseHistory(c *gin.Context) {
+	user, err := h.GetOrCreateUser(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	groups, total, err := h.BrowseHistoryService.GetHistory(user.ID, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch browse history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{"records": groups, "total": total, "page": page, "pageSize": pageSize},
+	})
+}
+
+// DeleteBrowseHistory 删除指定商品的足迹
+func (h *Handler) DeleteBrowseHistory(c *gin.Context) {
+	var req struct {
+		SPUIDs []string `json:"spuIds" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	user, err := h.GetOrCreateUser(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return
+	}
+
+	if err := h.BrowseHistoryService.DeleteHistory(user.ID, req.SPUIDs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete browse history"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Deleted"})
+}
+
+// ClearBrowseHistory 清空足迹
+func (h *Handler) ClearBrowseHistory(c *gin.Context) {
+	user, err := h.GetOrCreateUser(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return
+	}
+
+	if err := h.BrowseHistoryService.ClearHistory(user.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clear browse history"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Cleared"})
+}
@@ -22,7 +22,7 @@ func (h *Handler) GetHomeSwiper(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": swipers[0]})
 }
 
-// GetHomeContent 获取首页富文本内容
+// GetHomeContent 获取首页富文本内容，richText=nodes 时附带 rich-text 节点树
 func (h *Handler) GetHomeContent(c *gin.Context) {
 	key := c.DefaultQuery("key", "main")
 	content, err := h.GoodsService.GetHomeContent(key)
@@ -30,6 +30,11 @@ func (h *Handler) GetHomeContent(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"data": nil})
 		return
 	}
+	// 历史数据可能保存于清洗规则上线前，输出前再清洗一次
+	content.Content = internal.SanitizeHTML(content.Content)
+	if c.Query("richText") == "nodes" {
+		content.Nodes = internal.MiniProgramRichText(content.Content)
+	}
 	c.JSON(http.StatusOK, gin.H{"data": content})
 }
 
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"z26b-backend/internal"
+	miniprogram_services "z26b-backend/services/miniprogram"
 
 	"github.com/gin-gonic/gin"
 )
@@ -53,8 +54,12 @@ func (h *Handler) GetOrderDetail(c *gin.Context) {
 // CreateOrder 创建订单
 func (h *Handler) CreateOrder(c *gin.Context) {
 	var req struct {
-		AddressID string `json:"addressId" binding:"required"`
-		Remarks   string `json:"remarks"`
+		AddressID     string  `json:"addressId" binding:"required"`
+		Remarks       string  `json:"remarks"`
+		UseBalance    bool    `json:"useBalance"`    // 使用储值余额支付
+		BalanceAmount float64 `json:"balanceAmount"` // 余额支付金额，不填则尽量使用余额
+
+		Bundles []miniprogram_services.BundleLine `json:"bundles"` // 组合商品
 	}
 	if err := c.BindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
@@ -82,7 +87,7 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 		}
 	}
 
-	if len(selectedItems) == 0 {
+	if len(selectedItems) == 0 && len(req.Bundles) == 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "No items selected"})
 		return
 	}
@@ -101,7 +106,21 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 		}
 	}
 
-	order, err := h.OrderService.CreateOrder(user.ID, orderItems, req.AddressID)
+	// 计算余额支付金额
+	balanceAmount := 0.0
+	if req.UseBalance {
+		balanceAmount = req.BalanceAmount
+		if balanceAmount <= 0 {
+			userWallet, err := h.WalletService.GetOrCreateWallet(user.ID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wallet"})
+				return
+			}
+			balanceAmount = userWallet.Balance
+		}
+	}
+
+	order, err := h.OrderService.CreateOrder(user.ID, orderItems, req.Bundles, req.AddressID, balanceAmount)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -123,13 +142,29 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 				})
 			}
 		}
+		// 组合商品按组成项记录
+		for _, item := range order.Items {
+			if item.BundleID != "" && item.SKU != nil {
+				h.CRMEventService.RecordEvent(&internal.CRMEvent{
+					UserID:    user.ID,
+					EventType: internal.CRMEventTypePurchase,
+					SPUID:     item.SKU.SPUID,
+					SKUID:     item.SKUID,
+					OrderID:   order.ID,
+					Amount:    item.Price * float64(item.Quantity),
+					IPAddress: c.ClientIP(),
+					UserAgent: c.Request.UserAgent(),
+				})
+			}
+		}
 	}()
 
 	c.JSON(http.StatusOK, gin.H{
 		"data": gin.H{
 			"order":         order,
 			"paidAmount":    order.FinalPrice,
-			"paymentMethod": "DIRECT",
+			"balancePaid":   order.BalancePaid,
+			"paymentMethod": order.PaymentMethod,
 		},
 	})
 }
@@ -167,7 +202,8 @@ func (h *Handler) ConfirmReceipt(c *gin.Context) {
 		return
 	}
 
-	err = h.OrderService.UpdateOrderStatus(id, user.ID, internal.OrderStatusFinished)
+	// 订单完成并生成推荐人佣金，佣金生成失败时整体回滚，可重新确认收货
+	err = h.OrderService.ConfirmReceipt(id, user.ID)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to confirm receipt"})
 		return
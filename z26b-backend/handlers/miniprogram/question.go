@@ -0,0 +1,144 @@
+package miniprogram
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	miniprogram_services "z26b-backend/services/miniprogram"
+
+	"github.com/gin-gonic/gin"
+)
+
+// questionPage 解析问答分页参数
+func questionPage(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+	return page, pageSize
+}
+
+// GetGoodsQuestions 获取商品问答列表，按回答的“有用”数排序
+func (h *Handler) GetGoodsQuestions(c *gin.Context) {
+	page, pageSize := questionPage(c)
+	questions, total, err := h.QuestionService.GetQuestions(c.Param("id"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch questions"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{"records": questions, "total": total, "page": page, "pageSize": pageSize},
+	})
+}
+
+// GetQuestion 获取提问详情及全部回答
+func (h *Handler) GetQuestion(c *gin.Context) {
+	question, err := h.QuestionService.GetQuestion(c.Param("id"))
+	if errors.Is(err, miniprogram_services.ErrQuestionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch question"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": question})
+}
+
+// AskQuestion 对商品提问
+func (h *Handler) AskQuestion(c *gin.Context) {
+	var req struct {
+		SPUID   string `json:"spuId" binding:"required"`
+		Content string `json:"content" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	user, err := h.GetOrCreateUser(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return
+	}
+
+	question, err := h.QuestionService.AskQuestion(user, req.SPUID, req.Content)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": question, "message": "Question submitted for review"})
+}
+
+// AnswerQuestion 回答提问，仅已购买该商品的用户可以回答
+func (h *Handler) AnswerQuestion(c *gin.Context) {
+	var req struct {
+		Content string `json:"content" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	user, err := h.GetOrCreateUser(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return
+	}
+
+	answer, err := h.QuestionService.AnswerQuestion(user, c.Param("id"), req.Content)
+	switch {
+	case errors.Is(err, miniprogram_services.ErrQuestionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, miniprogram_services.ErrNotBuyer):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusOK, gin.H{"data": answer, "message": "Answer submitted for review"})
+	}
+}
+
+// MarkAnswerHelpful 标记回答“有用”
+func (h *Handler) MarkAnswerHelpful(c *gin.Context) {
+	user, err := h.GetOrCreateUser(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return
+	}
+
+	count, err := h.QuestionService.MarkHelpful(user.ID, c.Param("id"))
+	if errors.Is(err, miniprogram_services.ErrAnswerNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark answer helpful"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"helpfulCount": count}})
+}
+
+// GetMyQuestions 获取我的提问（含审核状态）
+func (h *Handler) GetMyQuestions(c *gin.Context) {
+	user, err := h.GetOrCreateUser(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return
+	}
+
+	page, pageSize := questionPage(c)
+	questions, total, err := h.QuestionService.GetMyQuestions(user.ID, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch questions"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{"records": questions, "total": total, "page": page, "pageSize": pageSize},
+	})
+}
@@ -0,0 +1,43 @@
+package miniprogram
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recommendLimit 解析推荐数量参数，默认 10，最多 50
+func recommendLimit(c *gin.Context) int {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+	return limit
+}
+
+// GetRelatedGoods 获取商品详情页“猜你喜欢”
+func (h *Handler) GetRelatedGoods(c *gin.Context) {
+	goods, err := h.RecommendService.GetRelated(c.Param("id"), recommendLimit(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch related goods"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": goods})
+}
+
+// GetHomeRecommend 获取首页个性化推荐
+func (h *Handler) GetHomeRecommend(c *gin.Context) {
+	user, err := h.GetOrCreateUser(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return
+	}
+
+	goods, err := h.RecommendService.GetHomeFeed(user.ID, recommendLimit(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch recommendations"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": goods})
+}
@@ -2,6 +2,7 @@ package miniprogram
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -48,3 +49,42 @@ func (h *Handler) UpdateUserInfo(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"data": updatedUser})
 }
+
+// GetReferralInfo 获取我的推荐码和推广佣金概况
+func (h *Handler) GetReferralInfo(c *gin.Context) {
+	user, err := h.GetOrCreateUser(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return
+	}
+
+	summary, err := h.CommissionService.GetReferralSummary(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get referral info"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": summary})
+}
+
+// GetReferralCommissions 获取我的佣金明细
+func (h *Handler) GetReferralCommissions(c *gin.Context) {
+	user, err := h.GetOrCreateUser(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+	status := c.Query("status")
+
+	records, total, err := h.CommissionService.GetCommissions(user.ID, status, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch commissions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{"records": records, "total": total, "page": page, "pageSize": pageSize},
+	})
+}
@@ -0,0 +1,47 @@
+package miniprogram
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetWallet 获取储值余额
+func (h *Handler) GetWallet(c *gin.Context) {
+	user, err := h.GetOrCreateUser(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return
+	}
+
+	wallet, err := h.WalletService.GetOrCreateWallet(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wallet"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": wallet})
+}
+
+// GetWalletTransactions 获取储值余额流水
+func (h *Handler) GetWalletTransactions(c *gin.Context) {
+	user, err := h.GetOrCreateUser(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+	txnType := c.Query("type")
+
+	txns, total, err := h.WalletService.GetTransactions(user.ID, txnType, page, pageSize, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch transactions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{"records": txns, "total": total, "page": page, "pageSize": pageSize},
+	})
+}
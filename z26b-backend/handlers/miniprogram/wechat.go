@@ -15,14 +15,15 @@ type WechatConfig struct {
 // WxLogin 微信登录
 func (h *Handler) WxLogin(c *gin.Context) {
 	var req struct {
-		Code string `json:"code" binding:"required"`
+		Code         string `json:"code" binding:"required"`
+		ReferralCode string `json:"referralCode"` // 分享链接中的推荐码，仅新用户生效
 	}
 	if err := c.BindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
 		return
 	}
 
-	result, err := h.WechatService.WxLogin(req.Code)
+	result, err := h.WechatService.WxLogin(req.Code, req.ReferralCode)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -0,0 +1,63 @@
+package internal
+
+// BundleShare 组合价分摊明细：Index 为组成项下标，Quantity 为每套组合中按 UnitPrice 计价的数量
+type BundleShare struct {
+	Index     int
+	Quantity  int
+	UnitPrice float64
+}
+
+// ProrateBundlePrice 按组成项原价占比分摊组合价，返回每套组合的分摊明细，各明细数量×单价之和等于组合价。
+// prices 为组成项原单价，quantities 为组成项数量；尾差计入数量为 1 的组成项，
+// 没有时从单价最高的组成项拆出一件单独计价，避免尾差除以数量后再次舍入
+func ProrateBundlePrice(bundlePrice float64, prices []float64, quantities []int) []BundleShare {
+	n := len(prices)
+	if n == 0 || len(quantities) != n {
+		return nil
+	}
+
+	weights := make([]float64, n)
+	var total float64
+	for i := range prices {
+		weights[i] = prices[i]
+		total += prices[i] * float64(quantities[i])
+	}
+	// 原价全部为 0 时按数量平均分摊
+	if total <= 0 {
+		total = 0
+		for i := range weights {
+			weights[i] = 1
+			total += float64(quantities[i])
+		}
+	}
+
+	shares := make([]BundleShare, n)
+	var allocated float64
+	for i := range shares {
+		shares[i] = BundleShare{Index: i, Quantity: quantities[i], UnitPrice: RoundMoney(bundlePrice * weights[i] / total)}
+		allocated += shares[i].UnitPrice * float64(quantities[i])
+	}
+
+	residual := RoundMoney(bundlePrice - allocated)
+	if residual == 0 {
+		return shares
+	}
+	// 优先选数量为 1 的组成项，其次选单价最高的组成项，尾差为负时单价不会被扣成负数
+	k := -1
+	for i, sh := range shares {
+		better := k < 0 || sh.UnitPrice > shares[k].UnitPrice
+		if k >= 0 && (sh.Quantity == 1) != (shares[k].Quantity == 1) {
+			better = sh.Quantity == 1
+		}
+		if better {
+			k = i
+		}
+	}
+	unit := RoundMoney(shares[k].UnitPrice + residual)
+	if shares[k].Quantity == 1 {
+		shares[k].UnitPrice = unit
+		return shares
+	}
+	shares[k].Quantity--
+	return append(shares, BundleShare{Index: k, Quantity: 1, UnitPrice: unit})
+}
@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProrateBundlePrice(t *testing.T) {
+	tests := []struct {
+		name       string
+		price      float64
+		prices     []float64
+		quantities []int
+		wantLines  int
+	}{
+		{"even split", 100, []float64{60, 40}, []int{1, 1}, 2},
+		{"residual to single item", 100, []float64{10, 10, 10}, []int{1, 1, 1}, 3},
+		{"multi quantity", 99.9, []float64{20, 35.5}, []int{3, 1}, 2},
+		{"zero prices", 30, []float64{0, 0}, []int{1, 2}, 2},
+		{"no single item splits one unit off", 100, []float64{10, 10}, []int{3, 3}, 3},
+		{"negative residual on split unit", 200, []float64{10, 20}, []int{3, 7}, 3},
+		{"exact split with quantities", 60, []float64{10, 10}, []int{3, 3}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares := ProrateBundlePrice(tt.price, tt.prices, tt.quantities)
+			if len(shares) != tt.wantLines {
+				t.Errorf("ProrateBundlePrice() lines = %d, want %d", len(shares), tt.wantLines)
+			}
+			var sum float64
+			quantities := make([]int, len(tt.quantities))
+			for _, sh := range shares {
+				if sh.UnitPrice < 0 {
+					t.Errorf("unit price of item %d is negative: %v", sh.Index, sh.UnitPrice)
+				}
+				sum += sh.UnitPrice * float64(sh.Quantity)
+				quantities[sh.Index] += sh.Quantity
+			}
+			if RoundMoney(sum) != tt.price {
+				t.Errorf("ProrateBundlePrice() total = %v, want %v", RoundMoney(sum), tt.price)
+			}
+			if !reflect.DeepEqual(quantities, tt.quantities) {
+				t.Errorf("ProrateBundlePrice() quantities = %v, want %v", quantities, tt.quantities)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package internal
+
+// BuildCategoryTree 将分类列表组装为树，保持传入顺序
+// parentId 为空或父分类不存在的分类作为根节点；成环的分类不会出现在结果中
+func BuildCategoryTree(categories []Category) []Category {
+	exists := make(map[string]bool, len(categories))
+	for _, c := range categories {
+		exists[c.ID] = true
+	}
+
+	children := make(map[string][]Category)
+	var roots []Category
+	for _, c := range categories {
+		if c.ParentID == "" || c.ParentID == c.ID || !exists[c.ParentID] {
+			roots = append(roots, c)
+		} else {
+			children[c.ParentID] = append(children[c.ParentID], c)
+		}
+	}
+
+	visited := make(map[string]bool, len(categories))
+	var attach func(nodes []Category) []Category
+	attach = func(nodes []Category) []Category {
+		out := make([]Category, 0, len(nodes))
+		for _, n := range nodes {
+			if visited[n.ID] {
+				continue
+			}
+			visited[n.ID] = true
+			n.Children = attach(children[n.ID])
+			out = append(out, n)
+		}
+		return out
+	}
+	return attach(roots)
+}
+
+// CategoryDescendantIDs 返回分类及其全部子孙分类的ID
+func CategoryDescendantIDs(categories []Category, rootID string) []string {
+	children := make(map[string][]string)
+	for _, c := range categories {
+		children[c.ParentID] = append(children[c.ParentID], c.ID)
+	}
+
+	ids := []string{rootID}
+	visited := map[string]bool{rootID: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range children[ids[i]] {
+			if !visited[child] {
+				visited[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids
+}
+
+// CategoryPath 返回从根分类到指定分类的路径（面包屑），分类不存在时返回空
+func CategoryPath(categories []Category, id string) []Category {
+	byID := make(map[string]Category, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = c
+	}
+
+	var path []Category
+	visited := make(map[string]bool)
+	for id != "" && !visited[id] {
+		c, ok := byID[id]
+		if !ok {
+			break
+		}
+		visited[id] = true
+		c.Children = nil
+		path = append([]Category{c}, path...)
+		id = c.ParentID
+	}
+	return path
+}
@@ -0,0 +1,39 @@
+package internal
+
+import "testing"
+
+func TestCategoryTree(t *testing.T) {
+	categories := []Category{
+		{ID: "a"},
+		{ID: "a1", ParentID: "a"},
+		{ID: "a1x", ParentID: "a1"},
+		{ID: "b"},
+		{ID: "orphan", ParentID: "missing"},
+		{ID: "c1", ParentID: "c2"},
+		{ID: "c2", ParentID: "c1"},
+	}
+
+	tree := BuildCategoryTree(categories)
+	if len(tree) != 3 {
+		t.Fatalf("BuildCategoryTree() roots = %d, want 3", len(tree))
+	}
+	if len(tree[0].Children) != 1 || len(tree[0].Children[0].Children) != 1 {
+		t.Errorf("BuildCategoryTree() did not nest a > a1 > a1x")
+	}
+
+	ids := CategoryDescendantIDs(categories, "a")
+	if len(ids) != 3 || ids[0] != "a" {
+		t.Errorf("CategoryDescendantIDs() = %v, want [a a1 a1x]", ids)
+	}
+	if ids := CategoryDescendantIDs(categories, "c1"); len(ids) != 2 {
+		t.Errorf("CategoryDescendantIDs() on cycle = %v, want 2 ids", ids)
+	}
+
+	path := CategoryPath(categories, "a1x")
+	if len(path) != 3 || path[0].ID != "a" || path[2].ID != "a1x" {
+		t.Errorf("CategoryPath() = %v, want a > a1 > a1x", path)
+	}
+	if path := CategoryPath(categories, "c1"); len(path) != 2 {
+		t.Errorf("CategoryPath() on cycle = %d nodes, want 2", len(path))
+	}
+}
@@ -47,6 +47,10 @@ func InitDB() *gorm.DB {
 	// 跳过自动迁移 - 使用 cmd/initdb 手动初始化数据库
 	// 如果需要自动迁移，设置环境变量 AUTO_MIGRATE=true
 	if os.Getenv("AUTO_MIGRATE") == "true" {
+		// 推荐码为空字符串的旧数据改为 NULL，否则无法创建唯一索引
+		if db.Migrator().HasColumn(&User{}, "referral_code") {
+			db.Model(&User{}).Where("referral_code = ?", "").Update("referral_code", nil)
+		}
 		err = db.AutoMigrate(
 			&Admin{},
 			&User{},
@@ -55,6 +59,11 @@ func InitDB() *gorm.DB {
 			&SPU{},
 			&SKU{},
 			&SPUTag{},
+			&ProductRevision{},
+			&Bundle{},
+			&BundleItem{},
+			&SearchDocument{},
+			&SearchLog{},
 			&Address{},
 			&Order{},
 			&OrderItem{},
@@ -65,6 +74,25 @@ func InitDB() *gorm.DB {
 			&Swiper{},
 			&RecommendedProduct{},
 			&HomeContent{},
+			&CommissionRule{},
+			&CommissionRecord{},
+			&Wallet{},
+			&WalletTransaction{},
+			&WalletEntry{},
+			&StockMovement{},
+			&StockAlert{},
+			&Warehouse{},
+			&WarehouseStock{},
+			&OrderShipment{},
+			&Favorite{},
+			&BrowseHistory{},
+			&ProductSimilarity{},
+			&ProductQuestion{},
+			&ProductAnswer{},
+			&AnswerVote{},
+			&SKUPriceHistory{},
+			&SensitiveWord{},
+			&ReviewMedia{},
 		)
 
 		if err != nil {
@@ -94,12 +122,13 @@ func EnsureTestUser(db *gorm.DB) {
 	if err := db.First(&user, "open_id = ?", testOpenID).Error; err != nil {
 		// 用户不存在，创建测试用户
 		user = User{
-			ID:        GenerateUUID(),
-			OpenID:    testOpenID,
-			NickName:  "测试用户",
-			Avatar:    "",
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
+			ID:           GenerateUUID(),
+			OpenID:       testOpenID,
+			NickName:     "测试用户",
+			Avatar:       "",
+			ReferralCode: GenerateReferralCode(),
+			CreatedAt:    time.Now(),
+			UpdatedAt:    time.Now(),
 		}
 		if err := db.Create(&user).Error; err != nil {
 			log.Printf("Failed to create test user: %v", err)
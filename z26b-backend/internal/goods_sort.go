@@ -0,0 +1,56 @@
+package internal
+
+import "gorm.io/gorm"
+
+// 商品列表排序方式
+const (
+	GoodsSortDefault   = "default"    // 综合：优先级高、上架新的在前
+	GoodsSortPriceAsc  = "price_asc"  // 价格从低到高（按最低价）
+	GoodsSortPriceDesc = "price_desc" // 价格从高到低（按最高价）
+	GoodsSortSales     = "sales"      // 销量
+	GoodsSortNewest    = "newest"     // 新品
+	GoodsSortRating    = "rating"     // 评分
+)
+
+// GoodsSortOrder 返回排序方式对应的 ORDER BY 子句，末尾按商品 ID 排序保证分页稳定；
+// needStats 为 true 时需关联 product_stats（别名 ps）；未知排序方式按综合排序
+func GoodsSortOrder(sort string) (order string, needStats bool) {
+	switch sort {
+	case GoodsSortPriceAsc:
+		return "spu.min_price ASC, spu.id ASC", false
+	case GoodsSortPriceDesc:
+		return "spu.max_price DESC, spu.id ASC", false
+	case GoodsSortSales:
+		return "COALESCE(ps.total_sales, 0) DESC, spu.priority DESC, spu.id ASC", true
+	case GoodsSortNewest:
+		return "spu.created_at DESC, spu.id ASC", false
+	case GoodsSortRating:
+		return "COALESCE(ps.avg_score, 0) DESC, COALESCE(ps.total_comments, 0) DESC, spu.id ASC", true
+	default:
+		return "spu.priority DESC, spu.created_at DESC, spu.id ASC", false
+	}
+}
+
+// SPUSortBy 按排序方式排序商品查询，需要统计数据时左关联 product_stats
+func SPUSortBy(sort string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		order, needStats := GoodsSortOrder(sort)
+		if needStats {
+			db = db.Joins("LEFT JOIN product_stats ps ON ps.spu_id = spu.id")
+		}
+		return db.Order(order)
+	}
+}
+
+// SPUPriceBetween 按价格区间筛选，区间与商品价格范围有交集即命中，nil 表示不限
+func SPUPriceBetween(minPrice, maxPrice *float64) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if minPrice != nil {
+			db = db.Where("spu.max_price >= ?", *minPrice)
+		}
+		if maxPrice != nil {
+			db = db.Where("spu.min_price <= ?", *maxPrice)
+		}
+		return db
+	}
+}
@@ -0,0 +1,33 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGoodsSortOrder(t *testing.T) {
+	tests := []struct {
+		sort      string
+		prefix    string
+		needStats bool
+	}{
+		{"", "spu.priority DESC", false},
+		{"unknown", "spu.priority DESC", false},
+		{GoodsSortPriceAsc, "spu.min_price ASC", false},
+		{GoodsSortPriceDesc, "spu.max_price DESC", false},
+		{GoodsSortSales, "COALESCE(ps.total_sales, 0) DESC", true},
+		{GoodsSortNewest, "spu.created_at DESC", false},
+		{GoodsSortRating, "COALESCE(ps.avg_score, 0) DESC", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			order, needStats := GoodsSortOrder(tt.sort)
+			if !strings.HasPrefix(order, tt.prefix) || needStats != tt.needStats {
+				t.Errorf("GoodsSortOrder(%q) = %q, %v", tt.sort, order, needStats)
+			}
+			if !strings.HasSuffix(order, "spu.id ASC") {
+				t.Errorf("GoodsSortOrder(%q) = %q, want stable tie-breaker", tt.sort, order)
+			}
+		})
+	}
+}
@@ -104,6 +104,9 @@ func CreateAllTables(db *gorm.DB) error {
 			open_id TEXT UNIQUE,
 			nick_name TEXT,
 			avatar TEXT,
+			referral_code TEXT UNIQUE,
+			referrer_id TEXT,
+			referred_at TIMESTAMP,
 			created_at TIMESTAMP,
 			updated_at TIMESTAMP
 		)`,
@@ -117,8 +120,11 @@ func CreateAllTables(db *gorm.DB) error {
 			parent_id TEXT,
 			sort INTEGER,
 			created_at TIMESTAMP,
-			updated_at TIMESTAMP
+			updated_at TIMESTAMP,
+			deleted_at TIMESTAMP
 		)`,
+		`CREATE INDEX IF NOT EXISTS idx_category_parent_id ON category(parent_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_category_deleted_at ON category(deleted_at)`,
 
 		// Tag
 		`CREATE TABLE IF NOT EXISTS tag (
@@ -129,22 +135,31 @@ func CreateAllTables(db *gorm.DB) error {
 			sort_order INTEGER,
 			status TEXT DEFAULT 'active',
 			created_at TIMESTAMP,
-			updated_at TIMESTAMP
+			updated_at TIMESTAMP,
+			deleted_at TIMESTAMP
 		)`,
+		`CREATE INDEX IF NOT EXISTS idx_tag_deleted_at ON tag(deleted_at)`,
 
 		// SPU (商品)
 		`CREATE TABLE IF NOT EXISTS spu (
 			id TEXT PRIMARY KEY,
+			code TEXT,
 			name TEXT,
 			detail TEXT,
 			cover_image TEXT,
 			swipe_images JSONB,
+			specs JSONB,
 			category_id TEXT REFERENCES category(id),
+			min_price DECIMAL(10,2) DEFAULT 0,
+			max_price DECIMAL(10,2) DEFAULT 0,
 			status TEXT,
+			publish_at BIGINT,
+			unpublish_at BIGINT,
 			priority INTEGER,
 			owner TEXT,
 			created_at BIGINT,
 			updated_at BIGINT,
+			deleted_at TIMESTAMP,
 			created_by TEXT,
 			updated_by TEXT,
 			"_openid" TEXT
@@ -154,17 +169,33 @@ func CreateAllTables(db *gorm.DB) error {
 		`CREATE TABLE IF NOT EXISTS sku (
 			id TEXT PRIMARY KEY,
 			"SPUID" TEXT REFERENCES spu(id),
+			code TEXT,
 			description TEXT,
+			spec_values JSONB,
+			spec_key TEXT,
 			image TEXT,
 			price DECIMAL(10,2),
 			count INTEGER,
+			low_stock_threshold INTEGER DEFAULT 10,
 			owner TEXT,
 			created_at BIGINT,
 			updated_at BIGINT,
+			deleted_at TIMESTAMP,
 			created_by TEXT,
 			updated_by TEXT,
 			"_openid" TEXT
 		)`,
+		`CREATE INDEX IF NOT EXISTS idx_spu_code ON spu(code)`,
+		`CREATE INDEX IF NOT EXISTS idx_spu_publish_at ON spu(publish_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_spu_unpublish_at ON spu(unpublish_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_spu_min_price ON spu(min_price)`,
+		`CREATE INDEX IF NOT EXISTS idx_spu_max_price ON spu(max_price)`,
+		`CREATE INDEX IF NOT EXISTS idx_spu_created_at ON spu(created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_spu_priority_created_at ON spu(priority DESC, created_at DESC)`,
+		`CREATE INDEX IF NOT EXISTS idx_sku_code ON sku(code)`,
+		`CREATE INDEX IF NOT EXISTS idx_sku_spec_key ON sku(spec_key)`,
+		`CREATE INDEX IF NOT EXISTS idx_spu_deleted_at ON spu(deleted_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_sku_deleted_at ON sku(deleted_at)`,
 
 		// SPU Tag (商品标签关联)
 		`CREATE TABLE IF NOT EXISTS spu_tag (
@@ -208,7 +239,10 @@ func CreateAllTables(db *gorm.DB) error {
 			total_price DECIMAL(10,2),
 			discount_price DECIMAL(10,2),
 			final_price DECIMAL(10,2),
+			balance_paid DECIMAL(10,2) DEFAULT 0,
+			payment_method TEXT,
 			remarks TEXT,
+			reviewed_at BIGINT,
 			created_at BIGINT,
 			updated_at BIGINT
 		)`,
@@ -218,6 +252,10 @@ func CreateAllTables(db *gorm.DB) error {
 			id TEXT PRIMARY KEY,
 			order_id TEXT REFERENCES "order"(id),
 			sku_id TEXT REFERENCES sku(id),
+			bundle_id TEXT,
+			warehouse_id TEXT,
+			shipment_id TEXT,
+			comment_id TEXT,
 			quantity INTEGER,
 			price DECIMAL(10,2),
 			created_at TIMESTAMP,
@@ -240,17 +278,32 @@ func CreateAllTables(db *gorm.DB) error {
 			id TEXT PRIMARY KEY,
 			spu_id TEXT,
 			sku_id TEXT,
+			order_id TEXT,
+			order_item_id TEXT,
+			verified_purchase BOOLEAN DEFAULT false,
 			user_id TEXT,
 			user_name TEXT,
 			user_head_url TEXT,
 			comment_content TEXT,
 			comment_score INTEGER,
 			comment_resources JSONB,
+			has_media BOOLEAN DEFAULT false,
 			is_anonymity BOOLEAN DEFAULT false,
 			seller_reply TEXT,
+			seller_reply_at BIGINT,
+			seller_reply_by TEXT,
+			status TEXT DEFAULT 'APPROVED',
+			sensitive_words TEXT,
+			reject_reason TEXT,
+			reviewed_by TEXT,
+			reviewed_at BIGINT,
 			created_at BIGINT,
 			updated_at TIMESTAMP
 		)`,
+		`CREATE INDEX IF NOT EXISTS idx_comment_status ON comment(status)`,
+		`CREATE INDEX IF NOT EXISTS idx_comment_order_id ON comment(order_id)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_comment_order_item_id ON comment(order_item_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_comment_has_media ON comment(has_media)`,
 
 		// Coupon
 		`CREATE TABLE IF NOT EXISTS coupon (
@@ -379,13 +432,368 @@ func CreateAllTables(db *gorm.DB) error {
 			total_comments INTEGER DEFAULT 0,
 			avg_score DECIMAL(3,2) DEFAULT 0,
 			total_shares INTEGER DEFAULT 0,
+			total_favorites INTEGER DEFAULT 0,
 			conversion_rate DECIMAL(5,4) DEFAULT 0,
 			created_at TIMESTAMP,
 			updated_at TIMESTAMP
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_product_stats_spu_id ON product_stats(spu_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_product_stats_total_sales ON product_stats(total_sales)`,
+		`CREATE INDEX IF NOT EXISTS idx_product_stats_avg_score ON product_stats(avg_score)`,
 		`CREATE INDEX IF NOT EXISTS idx_product_stats_total_revenue ON product_stats(total_revenue)`,
+
+		// Commission Rule (佣金规则)
+		`CREATE TABLE IF NOT EXISTS commission_rule (
+			id TEXT PRIMARY KEY,
+			scope TEXT,
+			target_id TEXT,
+			rate DECIMAL(5,4),
+			status TEXT DEFAULT 'active',
+			created_by TEXT,
+			created_at TIMESTAMP,
+			updated_at TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_commission_rule_target ON commission_rule(scope, target_id)`,
+
+		// Commission Record (佣金记录)
+		`CREATE TABLE IF NOT EXISTS commission_record (
+			id TEXT PRIMARY KEY,
+			referrer_id TEXT,
+			user_id TEXT,
+			order_id TEXT,
+			order_item_id TEXT UNIQUE,
+			spu_id TEXT,
+			sku_id TEXT,
+			rule_id TEXT,
+			base_amount DECIMAL(10,2),
+			rate DECIMAL(5,4),
+			amount DECIMAL(10,2),
+			status TEXT,
+			settled_at BIGINT,
+			reversed_at BIGINT,
+			created_at BIGINT,
+			updated_at BIGINT
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_commission_record_referrer_id ON commission_record(referrer_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_commission_record_order_id ON commission_record(order_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_commission_record_status ON commission_record(status)`,
+
+		// Wallet (储值钱包)
+		`CREATE TABLE IF NOT EXISTS wallet (
+			id TEXT PRIMARY KEY,
+			user_id TEXT UNIQUE,
+			balance DECIMAL(10,2) DEFAULT 0,
+			total_recharged DECIMAL(10,2) DEFAULT 0,
+			total_spent DECIMAL(10,2) DEFAULT 0,
+			created_at TIMESTAMP,
+			updated_at TIMESTAMP
+		)`,
+
+		// Wallet Transaction (钱包流水)
+		`CREATE TABLE IF NOT EXISTS wallet_transaction (
+			id TEXT PRIMARY KEY,
+			user_id TEXT,
+			type TEXT,
+			amount DECIMAL(10,2),
+			balance_after DECIMAL(10,2),
+			order_id TEXT,
+			operator_id TEXT,
+			remark TEXT,
+			created_at BIGINT
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_wallet_transaction_user_id ON wallet_transaction(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_wallet_transaction_type ON wallet_transaction(type)`,
+		`CREATE INDEX IF NOT EXISTS idx_wallet_transaction_order_id ON wallet_transaction(order_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_wallet_transaction_created_at ON wallet_transaction(created_at)`,
+
+		// Wallet Entry (复式记账分录)
+		`CREATE TABLE IF NOT EXISTS wallet_entry (
+			id TEXT PRIMARY KEY,
+			transaction_id TEXT REFERENCES wallet_transaction(id),
+			account TEXT,
+			direction TEXT,
+			amount DECIMAL(10,2),
+			created_at BIGINT
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_wallet_entry_transaction_id ON wallet_entry(transaction_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_wallet_entry_account ON wallet_entry(account)`,
+
+		// Bundle (组合商品)
+		`CREATE TABLE IF NOT EXISTS bundle (
+			id TEXT PRIMARY KEY,
+			name TEXT,
+			description TEXT,
+			cover_image TEXT,
+			price DECIMAL(10,2),
+			status TEXT,
+			priority INTEGER,
+			created_at BIGINT,
+			updated_at BIGINT,
+			created_by TEXT,
+			updated_by TEXT
+		)`,
+
+		// Bundle Item (组合商品组成项)
+		`CREATE TABLE IF NOT EXISTS bundle_item (
+			id TEXT PRIMARY KEY,
+			bundle_id TEXT REFERENCES bundle(id),
+			sku_id TEXT REFERENCES sku(id),
+			quantity INTEGER
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_bundle_item_bundle_id ON bundle_item(bundle_id)`,
+
+		// Search Document (商品搜索索引)
+		`CREATE TABLE IF NOT EXISTS search_document (
+			spu_id TEXT PRIMARY KEY,
+			name TEXT,
+			keywords TEXT,
+			content TEXT,
+			name_terms TEXT,
+			key_terms TEXT,
+			body_terms TEXT,
+			pinyin TEXT,
+			initials TEXT,
+			category_id TEXT,
+			category_name TEXT,
+			tag_ids TEXT,
+			tag_names TEXT,
+			min_price DECIMAL(10,2),
+			max_price DECIMAL(10,2),
+			priority INTEGER,
+			status TEXT,
+			publish_at BIGINT,
+			unpublish_at BIGINT,
+			updated_at BIGINT,
+			tsv TSVECTOR
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_search_document_tsv ON search_document USING GIN(tsv)`,
+		`CREATE INDEX IF NOT EXISTS idx_search_document_category_id ON search_document(category_id)`,
+
+		`CREATE TABLE IF NOT EXISTS search_log (
+			id TEXT PRIMARY KEY,
+			keyword TEXT NOT NULL,
+			user_id TEXT,
+			result_count BIGINT DEFAULT 0,
+			created_at BIGINT
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_search_log_keyword ON search_log(keyword)`,
+		`CREATE INDEX IF NOT EXISTS idx_search_log_user_id ON search_log(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_search_log_created_at ON search_log(created_at)`,
+
+		`CREATE TABLE IF NOT EXISTS product_revision (
+			id TEXT PRIMARY KEY,
+			spu_id TEXT NOT NULL,
+			version INTEGER NOT NULL,
+			action TEXT,
+			snapshot JSONB,
+			changes JSONB,
+			restored_from INTEGER DEFAULT 0,
+			admin_id TEXT,
+			created_at BIGINT
+		)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_product_revision_version ON product_revision(spu_id, version)`,
+
+		// Stock Movement (库存流水)
+		`CREATE TABLE IF NOT EXISTS stock_movement (
+			id TEXT PRIMARY KEY,
+			sku_id TEXT,
+			spu_id TEXT,
+			reason TEXT,
+			quantity INTEGER,
+			count_after INTEGER,
+			warehouse_id TEXT,
+			ref_id TEXT,
+			operator_id TEXT,
+			remark TEXT,
+			created_at BIGINT
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_stock_movement_sku_id ON stock_movement(sku_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_stock_movement_spu_id ON stock_movement(spu_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_stock_movement_reason ON stock_movement(reason)`,
+		`CREATE INDEX IF NOT EXISTS idx_stock_movement_warehouse_id ON stock_movement(warehouse_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_stock_movement_ref_id ON stock_movement(ref_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_stock_movement_created_at ON stock_movement(created_at)`,
+
+		// Stock Alert (低库存预警)
+		`CREATE TABLE IF NOT EXISTS stock_alert (
+			id TEXT PRIMARY KEY,
+			sku_id TEXT,
+			spu_id TEXT,
+			count INTEGER,
+			threshold INTEGER,
+			acknowledged_at BIGINT,
+			acknowledged_by TEXT,
+			created_at BIGINT
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_stock_alert_sku_id ON stock_alert(sku_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_stock_alert_spu_id ON stock_alert(spu_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_stock_alert_acknowledged_at ON stock_alert(acknowledged_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_stock_alert_created_at ON stock_alert(created_at)`,
+
+		// Warehouse (发货仓库)
+		`CREATE TABLE IF NOT EXISTS warehouse (
+			id TEXT PRIMARY KEY,
+			code TEXT UNIQUE,
+			name TEXT,
+			address TEXT,
+			regions JSONB,
+			priority INTEGER DEFAULT 0,
+			status TEXT DEFAULT 'ENABLED',
+			created_at BIGINT,
+			updated_at BIGINT
+		)`,
+
+		// Warehouse Stock (仓库库存)
+		`CREATE TABLE IF NOT EXISTS warehouse_stock (
+			id TEXT PRIMARY KEY,
+			warehouse_id TEXT REFERENCES warehouse(id),
+			sku_id TEXT REFERENCES sku(id),
+			count INTEGER DEFAULT 0,
+			updated_at BIGINT
+		)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_warehouse_stock_sku ON warehouse_stock(warehouse_id, sku_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_warehouse_stock_sku_id ON warehouse_stock(sku_id)`,
+
+		// Order Shipment (按仓库拆分的发货单)
+		`CREATE TABLE IF NOT EXISTS order_shipment (
+			id TEXT PRIMARY KEY,
+			order_id TEXT REFERENCES "order"(id),
+			warehouse_id TEXT REFERENCES warehouse(id),
+			status TEXT,
+			tracking_no TEXT,
+			shipped_at BIGINT,
+			shipped_by TEXT,
+			created_at BIGINT,
+			updated_at BIGINT
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_order_shipment_order_id ON order_shipment(order_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_order_shipment_warehouse_id ON order_shipment(warehouse_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_order_shipment_status ON order_shipment(status)`,
+		`CREATE INDEX IF NOT EXISTS idx_order_shipment_created_at ON order_shipment(created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_order_item_warehouse_id ON order_item(warehouse_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_order_item_shipment_id ON order_item(shipment_id)`,
+
+		// Favorite (商品收藏)
+		`CREATE TABLE IF NOT EXISTS favorite (
+			id TEXT PRIMARY KEY,
+			user_id TEXT,
+			spu_id TEXT,
+			name TEXT,
+			cover_image TEXT,
+			price DECIMAL(10,2),
+			created_at BIGINT
+		)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_favorite_user_spu ON favorite(user_id, spu_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_favorite_spu_id ON favorite(spu_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_favorite_created_at ON favorite(created_at)`,
+
+		// Browse History (浏览足迹)
+		`CREATE TABLE IF NOT EXISTS browse_history (
+			id TEXT PRIMARY KEY,
+			user_id TEXT,
+			spu_id TEXT,
+			viewed_at BIGINT
+		)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_browse_history_user_spu ON browse_history(user_id, spu_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_browse_history_viewed_at ON browse_history(viewed_at)`,
+
+		// Product Similarity (商品相似度，推荐用)
+		`CREATE TABLE IF NOT EXISTS product_similarity (
+			id TEXT PRIMARY KEY,
+			spu_id TEXT,
+			related_spu_id TEXT,
+			score DOUBLE PRECISION,
+			purchase_score DOUBLE PRECISION,
+			view_score DOUBLE PRECISION,
+			computed_at BIGINT
+		)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_product_similarity_pair ON product_similarity(spu_id, related_spu_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_product_similarity_score ON product_similarity(score)`,
+
+		// Product Question (商品问答)
+		`CREATE TABLE IF NOT EXISTS product_question (
+			id TEXT PRIMARY KEY,
+			spu_id TEXT,
+			user_id TEXT,
+			user_name TEXT,
+			user_head_url TEXT,
+			content TEXT,
+			status TEXT DEFAULT 'PENDING',
+			reject_reason TEXT,
+			answer_count INTEGER DEFAULT 0,
+			helpful_count INTEGER DEFAULT 0,
+			created_at BIGINT,
+			updated_at BIGINT
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_product_question_spu_id ON product_question(spu_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_product_question_user_id ON product_question(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_product_question_status ON product_question(status)`,
+		`CREATE TABLE IF NOT EXISTS product_answer (
+			id TEXT PRIMARY KEY,
+			question_id TEXT,
+			spu_id TEXT,
+			user_id TEXT,
+			admin_id TEXT,
+			user_name TEXT,
+			user_head_url TEXT,
+			content TEXT,
+			is_official BOOLEAN DEFAULT FALSE,
+			is_buyer BOOLEAN DEFAULT FALSE,
+			status TEXT DEFAULT 'PENDING',
+			reject_reason TEXT,
+			helpful_count INTEGER DEFAULT 0,
+			created_at BIGINT,
+			updated_at BIGINT
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_product_answer_question_id ON product_answer(question_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_product_answer_spu_id ON product_answer(spu_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_product_answer_user_id ON product_answer(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_product_answer_status ON product_answer(status)`,
+		`CREATE TABLE IF NOT EXISTS answer_vote (
+			id TEXT PRIMARY KEY,
+			answer_id TEXT,
+			user_id TEXT,
+			created_at BIGINT
+		)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_answer_vote_answer_user ON answer_vote(answer_id, user_id)`,
+
+		// SKU Price History (SKU 调价记录)
+		`CREATE TABLE IF NOT EXISTS sku_price_history (
+			id TEXT PRIMARY KEY,
+			sku_id TEXT,
+			spu_id TEXT,
+			old_price DECIMAL(10,2),
+			new_price DECIMAL(10,2),
+			source TEXT,
+			admin_id TEXT,
+			effective_at BIGINT
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_sku_price_history_sku_effective ON sku_price_history(sku_id, effective_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_sku_price_history_spu_effective ON sku_price_history(spu_id, effective_at)`,
+
+		// Sensitive Word (敏感词词典)
+		`CREATE TABLE IF NOT EXISTS sensitive_word (
+			id TEXT PRIMARY KEY,
+			word TEXT,
+			level TEXT,
+			created_by TEXT,
+			created_at BIGINT
+		)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_sensitive_word_word ON sensitive_word(word)`,
+
+		// Review Media (评价图片/视频)
+		`CREATE TABLE IF NOT EXISTS review_media (
+			id TEXT PRIMARY KEY,
+			user_id TEXT,
+			comment_id TEXT,
+			type TEXT,
+			url TEXT,
+			content_type TEXT,
+			size BIGINT,
+			created_at BIGINT
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_review_media_user_created ON review_media(user_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_review_media_comment_id ON review_media(comment_id)`,
 	}
 
 	for _, sql := range sqlStatements {
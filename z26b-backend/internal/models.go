@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"gorm.io/datatypes"
+	"gorm.io/gorm"
 )
 
 // ============================================
@@ -31,12 +32,15 @@ func (Admin) TableName() string { return "admin" }
 // ============================================
 
 type User struct {
-	ID        string    `gorm:"primaryKey" json:"_id"`
-	OpenID    string    `gorm:"uniqueIndex" json:"openid"`
-	NickName  string    `json:"nickName"`
-	Avatar    string    `json:"avatar"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID           string     `gorm:"primaryKey" json:"_id"`
+	OpenID       string     `gorm:"uniqueIndex" json:"openid"`
+	NickName     string     `json:"nickName"`
+	Avatar       string     `json:"avatar"`
+	ReferralCode *string    `gorm:"column:referral_code;uniqueIndex" json:"referralCode"` // 推荐码，未生成时为 NULL（唯一索引忽略 NULL）
+	ReferrerID   string     `gorm:"column:referrer_id;index" json:"referrerId,omitempty"` // 推荐人ID（首次登录时绑定）
+	ReferredAt   *time.Time `gorm:"column:referred_at" json:"referredAt,omitempty"`       // 绑定推荐人时间
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
 }
 
 func (User) TableName() string { return "user" }
@@ -47,6 +51,7 @@ func (User) TableName() string { return "user" }
 
 type SPU struct {
 	ID          string         `gorm:"primaryKey" json:"_id"`
+	Code        string         `gorm:"column:code;index" json:"code"` // 外部商品编码，批量导入时按编码更新
 	Name        string         `gorm:"column:name" json:"name"`
 	Detail      string         `gorm:"column:detail" json:"detail"`
 	CoverImage  string         `gorm:"column:cover_image" json:"cover_image"`
@@ -54,60 +59,81 @@ type SPU struct {
 	CategoryID  string         `gorm:"column:category_id" json:"categoryId"`
 	Category    *Category      `gorm:"foreignKey:CategoryID;references:ID" json:"category,omitempty"`
 	Tags        []Tag          `gorm:"-" json:"tags,omitempty"`
-	MinPrice    float64        `gorm:"column:min_price" json:"minPrice"`
-	MaxPrice    float64        `gorm:"column:max_price" json:"maxPrice"`
+	Specs       datatypes.JSON `gorm:"column:specs;type:json" json:"specs"` // 规格维度 []SpecDimension
+	MinPrice    float64        `gorm:"column:min_price;index" json:"minPrice"`
+	MaxPrice    float64        `gorm:"column:max_price;index" json:"maxPrice"`
 	Status      string         `gorm:"column:status" json:"status"`
-	Priority    int            `gorm:"column:priority" json:"priority"`
+	PublishAt   *int64         `gorm:"column:publish_at;index" json:"publishAt"`     // 定时上架时间，到期后由定时任务清空
+	UnpublishAt *int64         `gorm:"column:unpublish_at;index" json:"unpublishAt"` // 定时下架时间，到期后由定时任务清空
+	Priority    int            `gorm:"column:priority;index:idx_spu_priority_created_at,priority:1" json:"priority"`
 	Owner       string         `gorm:"column:owner" json:"owner"`
-	CreatedAt   int64          `gorm:"column:created_at" json:"createdAt"`
+	CreatedAt   int64          `gorm:"column:created_at;index;index:idx_spu_priority_created_at,priority:2" json:"createdAt"`
 	UpdatedAt   int64          `gorm:"column:updated_at" json:"updatedAt"`
+	DeletedAt   gorm.DeletedAt `gorm:"column:deleted_at;index" json:"-"` // 软删除时间，进入回收站
 	CreatedBy   string         `gorm:"column:created_by" json:"createBy"`
 	UpdatedBy   string         `gorm:"column:updated_by" json:"updateBy"`
 	OpenID      string         `gorm:"column:_openid" json:"_openid"`
+
+	EffectiveStatus string `gorm:"-" json:"effectiveStatus,omitempty"` // 考虑定时上下架后的实际状态，仅管理后台列表返回
 }
 
 func (SPU) TableName() string { return "spu" }
 
 type SKU struct {
-	ID          string  `gorm:"primaryKey" json:"_id"`
-	SPUID       string  `gorm:"column:SPUID" json:"spuId"`
-	SPU         *SPU    `gorm:"foreignKey:SPUID;references:ID" json:"spu,omitempty"`
-	Description string  `gorm:"column:description" json:"description"`
-	Image       string  `gorm:"column:image" json:"image"`
-	Price       float64 `gorm:"column:price" json:"price"`
-	Count       int     `gorm:"column:count" json:"count"`
-	Owner       string  `gorm:"column:owner" json:"owner"`
-	CreatedAt   int64   `gorm:"column:created_at" json:"createdAt"`
-	UpdatedAt   int64   `gorm:"column:updated_at" json:"updatedAt"`
-	CreatedBy   string  `gorm:"column:created_by" json:"createBy"`
-	UpdatedBy   string  `gorm:"column:updated_by" json:"updateBy"`
-	OpenID      string  `gorm:"column:_openid" json:"_openid"`
+	ID                string         `gorm:"primaryKey" json:"_id"`
+	SPUID             string         `gorm:"column:SPUID" json:"spuId"`
+	SPU               *SPU           `gorm:"foreignKey:SPUID;references:ID" json:"spu,omitempty"`
+	Code              string         `gorm:"column:code;index" json:"code"` // 外部 SKU 编码，批量导入时按编码更新
+	Description       string         `gorm:"column:description" json:"description"`
+	SpecValues        datatypes.JSON `gorm:"column:spec_values;type:json" json:"specValues"` // 规格值组合，与 SPU.Specs 维度顺序一致
+	SpecKey           string         `gorm:"column:spec_key;index" json:"specKey"`           // 规格组合键，用于按组合查找 SKU
+	Image             string         `gorm:"column:image" json:"image"`
+	Price             float64        `gorm:"column:price" json:"price"`
+	Count             int            `gorm:"column:count" json:"count"`
+	LowStockThreshold int            `gorm:"column:low_stock_threshold;default:10" json:"lowStockThreshold"` // 低库存预警值，0 表示不预警
+	Owner             string         `gorm:"column:owner" json:"owner"`
+	CreatedAt         int64          `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt         int64          `gorm:"column:updated_at" json:"updatedAt"`
+	DeletedAt         gorm.DeletedAt `gorm:"column:deleted_at;index" json:"-"` // 软删除时间，历史订单仍可关联
+	CreatedBy         string         `gorm:"column:created_by" json:"createBy"`
+	UpdatedBy         string         `gorm:"column:updated_by" json:"updateBy"`
+	OpenID            string         `gorm:"column:_openid" json:"_openid"`
 }
 
 func (SKU) TableName() string { return "sku" }
 
+// SpecDimension 商品规格维度，如 颜色: 红/蓝
+type SpecDimension struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
 type Category struct {
-	ID        string    `gorm:"primaryKey" json:"_id"`
-	Name      string    `json:"name"`
-	Icon      string    `json:"icon"`
-	Image     string    `json:"image"`
-	ParentID  string    `json:"parentId"`
-	Sort      int       `json:"sort"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID        string         `gorm:"primaryKey" json:"_id"`
+	Name      string         `json:"name"`
+	Icon      string         `json:"icon"`
+	Image     string         `json:"image"`
+	ParentID  string         `json:"parentId"`
+	Sort      int            `json:"sort"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	Children []Category `gorm:"-" json:"children,omitempty"` // 子分类，仅树形接口返回
 }
 
 func (Category) TableName() string { return "category" }
 
 type Tag struct {
-	ID          string    `gorm:"primaryKey" json:"_id"`
-	Name        string    `gorm:"uniqueIndex" json:"name"`
-	Description string    `json:"description"`
-	Color       string    `json:"color"`
-	SortOrder   int       `json:"sortOrder"`
-	Status      string    `gorm:"default:active" json:"status"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID          string         `gorm:"primaryKey" json:"_id"`
+	Name        string         `gorm:"uniqueIndex" json:"name"`
+	Description string         `json:"description"`
+	Color       string         `json:"color"`
+	SortOrder   int            `json:"sortOrder"`
+	Status      string         `gorm:"default:active" json:"status"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 func (Tag) TableName() string { return "tag" }
@@ -122,6 +148,92 @@ type SPUTag struct {
 
 func (SPUTag) TableName() string { return "spu_tag" }
 
+// ============================================
+// 商品变更历史
+// ============================================
+
+// ProductRevision 操作类型
+const (
+	RevisionActionBaseline   = "baseline"    // 首次记录变更前的原始版本
+	RevisionActionUpdate     = "update"      // 编辑商品（含标签）
+	RevisionActionStatus     = "status"      // 上下架
+	RevisionActionSpecs      = "specs"       // 规格维度/规格矩阵
+	RevisionActionSKUCreate  = "sku_create"  // 新增 SKU
+	RevisionActionSKUUpdate  = "sku_update"  // 编辑 SKU
+	RevisionActionSKUDelete  = "sku_delete"  // 删除 SKU
+	RevisionActionTagRemoved = "tag_removed" // 标签被删除
+	RevisionActionRestore    = "restore"     // 恢复到历史版本
+	RevisionActionRecycle    = "recycle"     // 从回收站恢复 SKU/标签
+)
+
+// ProductRevision 商品版本快照，每次变更保存变更后的完整快照和字段级差异
+type ProductRevision struct {
+	ID           string         `gorm:"primaryKey" json:"_id"`
+	SPUID        string         `gorm:"column:spu_id;uniqueIndex:idx_product_revision_version" json:"spuId"`
+	Version      int            `gorm:"column:version;uniqueIndex:idx_product_revision_version" json:"version"`
+	Action       string         `gorm:"column:action" json:"action"`
+	Snapshot     datatypes.JSON `gorm:"column:snapshot;type:json" json:"snapshot,omitempty"` // 变更后的商品快照
+	Changes      datatypes.JSON `gorm:"column:changes;type:json" json:"changes"`             // 与上一状态的字段级差异
+	RestoredFrom int            `gorm:"column:restored_from" json:"restoredFrom,omitempty"`  // 恢复操作的来源版本
+	AdminID      string         `gorm:"column:admin_id" json:"adminId"`
+	CreatedAt    int64          `gorm:"column:created_at" json:"createdAt"`
+}
+
+func (ProductRevision) TableName() string { return "product_revision" }
+
+// ============================================
+// 组合商品
+// ============================================
+
+// Bundle 组合商品（套装），由已有 SKU 组成，按组合价销售
+type Bundle struct {
+	ID          string       `gorm:"primaryKey" json:"_id"`
+	Name        string       `gorm:"column:name" json:"name"`
+	Description string       `gorm:"column:description" json:"description"`
+	CoverImage  string       `gorm:"column:cover_image" json:"cover_image"`
+	Price       float64      `gorm:"column:price" json:"price"` // 组合价
+	Status      string       `gorm:"column:status" json:"status"`
+	Priority    int          `gorm:"column:priority" json:"priority"`
+	Items       []BundleItem `gorm:"foreignKey:BundleID;references:ID" json:"items,omitempty"`
+	// 以下字段不入库，查询时根据组成项计算
+	OriginalPrice float64 `gorm:"-" json:"originalPrice"` // 组成项原价合计
+	Stock         int     `gorm:"-" json:"stock"`         // 可售套数
+	CreatedAt     int64   `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt     int64   `gorm:"column:updated_at" json:"updatedAt"`
+	CreatedBy     string  `gorm:"column:created_by" json:"createBy"`
+	UpdatedBy     string  `gorm:"column:updated_by" json:"updateBy"`
+}
+
+func (Bundle) TableName() string { return "bundle" }
+
+// BundleItem 组合商品组成项
+type BundleItem struct {
+	ID       string `gorm:"primaryKey" json:"_id"`
+	BundleID string `gorm:"column:bundle_id;index" json:"bundleId"`
+	SKUID    string `gorm:"column:sku_id" json:"skuId"`
+	SKU      *SKU   `gorm:"foreignKey:SKUID;references:ID" json:"sku,omitempty"`
+	Quantity int    `gorm:"column:quantity" json:"quantity"`
+}
+
+func (BundleItem) TableName() string { return "bundle_item" }
+
+// FillSummary 根据已加载的组成项计算原价合计和可售套数
+func (b *Bundle) FillSummary() {
+	b.OriginalPrice = 0
+	b.Stock = 0
+	for i, item := range b.Items {
+		if item.SKU == nil || item.Quantity <= 0 {
+			continue
+		}
+		b.OriginalPrice += item.SKU.Price * float64(item.Quantity)
+		sets := item.SKU.Count / item.Quantity
+		if i == 0 || sets < b.Stock {
+			b.Stock = sets
+		}
+	}
+	b.OriginalPrice = RoundMoney(b.OriginalPrice)
+}
+
 // ============================================
 // 购物车
 // ============================================
@@ -139,6 +251,42 @@ type CartItem struct {
 
 func (CartItem) TableName() string { return "cart_item" }
 
+// ============================================
+// 收藏
+// ============================================
+
+// Favorite 商品收藏，保存收藏时的商品快照，商品删除后仍可展示
+type Favorite struct {
+	ID         string  `gorm:"primaryKey" json:"_id"`
+	UserID     string  `gorm:"column:user_id;uniqueIndex:idx_favorite_user_spu" json:"userId"`
+	SPUID      string  `gorm:"column:spu_id;uniqueIndex:idx_favorite_user_spu;index" json:"spuId"`
+	SPU        *SPU    `gorm:"foreignKey:SPUID;references:ID" json:"-"`
+	Name       string  `gorm:"column:name" json:"name"`              // 收藏时的商品名称
+	CoverImage string  `gorm:"column:cover_image" json:"coverImage"` // 收藏时的封面
+	Price      float64 `gorm:"column:price" json:"price"`            // 收藏时的最低价
+	CreatedAt  int64   `gorm:"column:created_at;index" json:"createdAt"`
+}
+
+func (Favorite) TableName() string { return "favorite" }
+
+// ============================================
+// 浏览足迹
+// ============================================
+
+// MaxBrowseHistory 每个用户保留的足迹条数
+const MaxBrowseHistory = 200
+
+// BrowseHistory 浏览足迹，同一商品只保留最近一次浏览
+type BrowseHistory struct {
+	ID       string `gorm:"primaryKey" json:"_id"`
+	UserID   string `gorm:"column:user_id;uniqueIndex:idx_browse_history_user_spu" json:"userId"`
+	SPUID    string `gorm:"column:spu_id;uniqueIndex:idx_browse_history_user_spu" json:"spuId"`
+	SPU      *SPU   `gorm:"foreignKey:SPUID;references:ID" json:"spu,omitempty"`
+	ViewedAt int64  `gorm:"column:viewed_at;index" json:"viewedAt"`
+}
+
+func (BrowseHistory) TableName() string { return "browse_history" }
+
 // ============================================
 // 地址
 // ============================================
@@ -182,31 +330,46 @@ const (
 	OrderStatusReturnFinish  = "RETURN_FINISH"
 )
 
+// 支付方式
+const (
+	PaymentMethodDirect  = "DIRECT"  // 直接支付
+	PaymentMethodBalance = "BALANCE" // 储值余额全额支付
+	PaymentMethodMixed   = "MIXED"   // 储值余额 + 直接支付
+)
+
 type Order struct {
-	ID            string         `gorm:"primaryKey" json:"_id"`
-	UserID        string         `json:"userId"`
-	Status        string         `json:"status"`
-	DeliveryInfo  datatypes.JSON `gorm:"type:json" json:"delivery_info"`
-	Items         []OrderItem    `gorm:"foreignKey:OrderID;references:ID" json:"items,omitempty"`
-	TotalPrice    float64        `json:"totalPrice"`
-	DiscountPrice float64        `json:"discountPrice"`
-	FinalPrice    float64        `json:"finalPrice"`
-	Remarks       string         `json:"remarks"`
-	CreatedAt     int64          `json:"createdAt"`
-	UpdatedAt     int64          `json:"updatedAt"`
+	ID            string          `gorm:"primaryKey" json:"_id"`
+	UserID        string          `json:"userId"`
+	Status        string          `json:"status"`
+	DeliveryInfo  datatypes.JSON  `gorm:"type:json" json:"delivery_info"`
+	Items         []OrderItem     `gorm:"foreignKey:OrderID;references:ID" json:"items,omitempty"`
+	TotalPrice    float64         `json:"totalPrice"`
+	DiscountPrice float64         `json:"discountPrice"`
+	FinalPrice    float64         `json:"finalPrice"`
+	BalancePaid   float64         `gorm:"column:balance_paid;default:0" json:"balancePaid"` // 储值余额支付部分
+	PaymentMethod string          `gorm:"column:payment_method" json:"paymentMethod"`
+	Remarks       string          `json:"remarks"`
+	Shipments     []OrderShipment `gorm:"foreignKey:OrderID;references:ID" json:"shipments,omitempty"` // 按仓库拆分的发货单
+	ReviewedAt    *int64          `gorm:"column:reviewed_at" json:"reviewedAt"`                        // 全部商品评价完成的时间
+	CreatedAt     int64           `json:"createdAt"`
+	UpdatedAt     int64           `json:"updatedAt"`
 }
 
 func (Order) TableName() string { return "order" }
 
 type OrderItem struct {
-	ID        string    `gorm:"primaryKey" json:"_id"`
-	OrderID   string    `gorm:"column:order_id" json:"orderId"`
-	SKUID     string    `gorm:"column:sku_id" json:"skuId"`
-	SKU       *SKU      `gorm:"foreignKey:SKUID;references:ID" json:"sku,omitempty"`
-	Quantity  int       `json:"quantity"`
-	Price     float64   `json:"price"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID          string    `gorm:"primaryKey" json:"_id"`
+	OrderID     string    `gorm:"column:order_id" json:"orderId"`
+	SKUID       string    `gorm:"column:sku_id" json:"skuId"`
+	SKU         *SKU      `gorm:"foreignKey:SKUID;references:ID" json:"sku,omitempty"`
+	BundleID    string    `gorm:"column:bundle_id" json:"bundleId,omitempty"`             // 来自组合商品时的组合ID
+	WarehouseID string    `gorm:"column:warehouse_id;index" json:"warehouseId,omitempty"` // 分配的发货仓库
+	ShipmentID  string    `gorm:"column:shipment_id;index" json:"shipmentId,omitempty"`   // 所属发货单
+	CommentID   string    `gorm:"column:comment_id" json:"commentId,omitempty"`           // 评价ID，未评价时为空
+	Quantity    int       `json:"quantity"`
+	Price       float64   `json:"price"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
 }
 
 func (OrderItem) TableName() string { return "order_item" }
@@ -215,24 +378,151 @@ func (OrderItem) TableName() string { return "order_item" }
 // 评论
 // ============================================
 
+// 评论审核状态
+const (
+	CommentStatusPending  = "PENDING"  // 待审核
+	CommentStatusApproved = "APPROVED" // 已通过
+	CommentStatusRejected = "REJECTED" // 已驳回
+	CommentStatusHidden   = "HIDDEN"   // 已隐藏（通过后下架）
+)
+
 type Comment struct {
 	ID               string         `gorm:"primaryKey" json:"_id"`
 	SPUID            string         `gorm:"column:spu_id" json:"spuId"`
+	SPU              *SPU           `gorm:"foreignKey:SPUID;references:ID" json:"spu,omitempty"`
 	SKUID            string         `gorm:"column:sku_id" json:"skuId"`
+	OrderID          string         `gorm:"column:order_id;index" json:"orderId,omitempty"`
+	OrderItemID      string         `gorm:"column:order_item_id;uniqueIndex" json:"orderItemId,omitempty"` // 每个订单商品只能评价一次
+	VerifiedPurchase bool           `gorm:"column:verified_purchase" json:"verifiedPurchase"`              // 基于已完成订单的评价
 	UserID           string         `gorm:"column:user_id" json:"userId"`
 	UserName         string         `json:"userName"`
 	UserHeadURL      string         `json:"userHeadUrl"`
 	CommentContent   string         `json:"commentContent"`
 	CommentScore     int            `json:"commentScore"`
-	CommentResources datatypes.JSON `gorm:"type:json" json:"commentResources"`
+	CommentResources datatypes.JSON `gorm:"type:json" json:"commentResources"`      // 图片/视频：[{"src", "type"}]
+	HasMedia         bool           `gorm:"column:has_media;index" json:"hasMedia"` // 是否带图片/视频
 	IsAnonymity      bool           `json:"isAnonymity"`
 	SellerReply      string         `json:"sellerReply"`
+	SellerReplyAt    *int64         `gorm:"column:seller_reply_at" json:"sellerReplyAt,omitempty"`
+	SellerReplyBy    string         `gorm:"column:seller_reply_by" json:"sellerReplyBy,omitempty"`  // 回复的管理员ID
+	Status           string         `gorm:"column:status;default:APPROVED;index" json:"status"`     // 存量评论视为已通过
+	SensitiveWords   string         `gorm:"column:sensitive_words" json:"sensitiveWords,omitempty"` // 命中的敏感词，逗号分隔
+	RejectReason     string         `gorm:"column:reject_reason" json:"rejectReason,omitempty"`
+	ReviewedBy       string         `gorm:"column:reviewed_by" json:"reviewedBy,omitempty"`
+	ReviewedAt       *int64         `gorm:"column:reviewed_at" json:"reviewedAt,omitempty"`
 	CreatedAt        int64          `json:"commentTime"`
 	UpdatedAt        time.Time      `json:"updatedAt"`
 }
 
 func (Comment) TableName() string { return "comment" }
 
+// CommentResource 评价中的图片/视频
+type CommentResource struct {
+	Src  string `json:"src"`
+	Type string `json:"type"` // image / video
+}
+
+// 评价图片/视频类型
+const (
+	MediaTypeImage = "image"
+	MediaTypeVideo = "video"
+)
+
+// ReviewMedia 用户上传的评价图片/视频，用于上传配额统计和评价时校验归属
+type ReviewMedia struct {
+	ID          string `gorm:"primaryKey" json:"_id"`
+	UserID      string `gorm:"column:user_id;index:idx_review_media_user_created,priority:1" json:"userId"`
+	CommentID   string `gorm:"column:comment_id;index" json:"commentId,omitempty"` // 所属评价，未使用时为空
+	Type        string `gorm:"column:type" json:"type"`
+	URL         string `gorm:"column:url" json:"url"`
+	ContentType string `gorm:"column:content_type" json:"contentType"`
+	Size        int64  `gorm:"column:size" json:"size"`
+	CreatedAt   int64  `gorm:"column:created_at;index:idx_review_media_user_created,priority:2" json:"createdAt"`
+}
+
+func (ReviewMedia) TableName() string { return "review_media" }
+
+// 敏感词处理级别
+const (
+	SensitiveLevelMask   = "MASK"   // 替换为 * 后直接发布
+	SensitiveLevelReview = "REVIEW" // 转人工审核
+	SensitiveLevelBlock  = "BLOCK"  // 直接驳回
+)
+
+// SensitiveWord 敏感词词典
+type SensitiveWord struct {
+	ID        string `gorm:"primaryKey" json:"_id"`
+	Word      string `gorm:"column:word;uniqueIndex" json:"word"` // 小写保存
+	Level     string `gorm:"column:level" json:"level"`
+	CreatedBy string `gorm:"column:created_by" json:"createdBy,omitempty"`
+	CreatedAt int64  `gorm:"column:created_at" json:"createdAt"`
+}
+
+func (SensitiveWord) TableName() string { return "sensitive_word" }
+
+// ============================================
+// 商品问答
+// ============================================
+
+// 问答审核状态
+const (
+	QAStatusPending  = "PENDING"
+	QAStatusApproved = "APPROVED"
+	QAStatusRejected = "REJECTED"
+)
+
+// ProductQuestion 商品提问（问大家），审核通过后在商品详情展示
+type ProductQuestion struct {
+	ID           string          `gorm:"primaryKey" json:"_id"`
+	SPUID        string          `gorm:"column:spu_id;index" json:"spuId"`
+	SPU          *SPU            `gorm:"foreignKey:SPUID;references:ID" json:"spu,omitempty"`
+	UserID       string          `gorm:"column:user_id;index" json:"userId"`
+	UserName     string          `gorm:"column:user_name" json:"userName"`
+	UserHeadURL  string          `gorm:"column:user_head_url" json:"userHeadUrl"`
+	Content      string          `gorm:"column:content" json:"content"`
+	Status       string          `gorm:"column:status;index;default:PENDING" json:"status"`
+	RejectReason string          `gorm:"column:reject_reason" json:"rejectReason,omitempty"`
+	AnswerCount  int             `gorm:"column:answer_count;default:0" json:"answerCount"`   // 审核通过的回答数
+	HelpfulCount int             `gorm:"column:helpful_count;default:0" json:"helpfulCount"` // 审核通过的回答获得的“有用”数合计
+	Answers      []ProductAnswer `gorm:"foreignKey:QuestionID;references:ID" json:"answers,omitempty"`
+	CreatedAt    int64           `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt    int64           `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+func (ProductQuestion) TableName() string { return "product_question" }
+
+// ProductAnswer 商品提问的回答：已购买该商品的用户或商家可以回答
+type ProductAnswer struct {
+	ID           string           `gorm:"primaryKey" json:"_id"`
+	QuestionID   string           `gorm:"column:question_id;index" json:"questionId"`
+	Question     *ProductQuestion `gorm:"foreignKey:QuestionID;references:ID" json:"question,omitempty"`
+	SPUID        string           `gorm:"column:spu_id;index" json:"spuId"`
+	UserID       string           `gorm:"column:user_id;index" json:"userId,omitempty"` // 用户回答
+	AdminID      string           `gorm:"column:admin_id" json:"adminId,omitempty"`     // 商家回答
+	UserName     string           `gorm:"column:user_name" json:"userName"`
+	UserHeadURL  string           `gorm:"column:user_head_url" json:"userHeadUrl"`
+	Content      string           `gorm:"column:content" json:"content"`
+	IsOfficial   bool             `gorm:"column:is_official;default:false" json:"isOfficial"` // 商家回答
+	IsBuyer      bool             `gorm:"column:is_buyer;default:false" json:"isBuyer"`       // 已购买用户回答
+	Status       string           `gorm:"column:status;index;default:PENDING" json:"status"`
+	RejectReason string           `gorm:"column:reject_reason" json:"rejectReason,omitempty"`
+	HelpfulCount int              `gorm:"column:helpful_count;default:0" json:"helpfulCount"`
+	CreatedAt    int64            `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt    int64            `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+func (ProductAnswer) TableName() string { return "product_answer" }
+
+// AnswerVote 用户对回答的“有用”投票，每个用户对每个回答只能投一次
+type AnswerVote struct {
+	ID        string `gorm:"primaryKey" json:"_id"`
+	AnswerID  string `gorm:"column:answer_id;uniqueIndex:idx_answer_vote_answer_user" json:"answerId"`
+	UserID    string `gorm:"column:user_id;uniqueIndex:idx_answer_vote_answer_user" json:"userId"`
+	CreatedAt int64  `gorm:"column:created_at" json:"createdAt"`
+}
+
+func (AnswerVote) TableName() string { return "answer_vote" }
+
 // ============================================
 // 优惠券 & 促销
 // ============================================
@@ -305,19 +595,33 @@ type RecommendedProduct struct {
 
 func (RecommendedProduct) TableName() string { return "recommended_product" }
 
+// ProductSimilarity 商品相似度，由离线任务根据共同购买和共同浏览计算，每个商品保存前 N 个相似商品
+type ProductSimilarity struct {
+	ID            string  `gorm:"primaryKey" json:"_id"`
+	SPUID         string  `gorm:"column:spu_id;uniqueIndex:idx_product_similarity_pair" json:"spuId"`
+	RelatedSPUID  string  `gorm:"column:related_spu_id;uniqueIndex:idx_product_similarity_pair" json:"relatedSpuId"`
+	Score         float64 `gorm:"column:score;index" json:"score"`            // 加权相似度
+	PurchaseScore float64 `gorm:"column:purchase_score" json:"purchaseScore"` // 共同购买相似度
+	ViewScore     float64 `gorm:"column:view_score" json:"viewScore"`         // 共同浏览相似度
+	ComputedAt    int64   `gorm:"column:computed_at" json:"computedAt"`
+}
+
+func (ProductSimilarity) TableName() string { return "product_similarity" }
+
 // ============================================
 // 首页内容（富文本）
 // ============================================
 
 type HomeContent struct {
-	ID        string    `gorm:"primaryKey" json:"_id"`
-	Key       string    `gorm:"uniqueIndex" json:"key"`   // 内容标识，如 "main", "promotion", "notice"
-	Title     string    `json:"title"`                    // 标题
-	Content   string    `gorm:"type:text" json:"content"` // 富文本内容
-	Enabled   bool      `gorm:"default:true" json:"enabled"`
-	Priority  int       `gorm:"default:0" json:"priority"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID        string         `gorm:"primaryKey" json:"_id"`
+	Key       string         `gorm:"uniqueIndex" json:"key"`   // 内容标识，如 "main", "promotion", "notice"
+	Title     string         `json:"title"`                    // 标题
+	Content   string         `gorm:"type:text" json:"content"` // 富文本内容
+	Nodes     []RichTextNode `gorm:"-" json:"nodes,omitempty"` // rich-text 节点树，小程序端按需返回
+	Enabled   bool           `gorm:"default:true" json:"enabled"`
+	Priority  int            `gorm:"default:0" json:"priority"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
 }
 
 func (HomeContent) TableName() string { return "home_content" }
@@ -396,13 +700,14 @@ type ProductStats struct {
 	SPU            *SPU      `gorm:"foreignKey:SPUID;references:ID" json:"spu,omitempty"`
 	TotalViews     int       `gorm:"column:total_views;default:0" json:"totalViews"`         // 总浏览量
 	TotalCarts     int       `gorm:"column:total_carts;default:0" json:"totalCarts"`         // 总加购数
-	TotalSales     int       `gorm:"column:total_sales;default:0" json:"totalSales"`         // 总销量
+	TotalSales     int       `gorm:"column:total_sales;default:0;index" json:"totalSales"`   // 总销量
 	TotalRevenue   float64   `gorm:"column:total_revenue;default:0" json:"totalRevenue"`     // 总营收
 	TotalRefunds   int       `gorm:"column:total_refunds;default:0" json:"totalRefunds"`     // 总退款数
 	RefundAmount   float64   `gorm:"column:refund_amount;default:0" json:"refundAmount"`     // 总退款金额
 	TotalComments  int       `gorm:"column:total_comments;default:0" json:"totalComments"`   // 总评论数
-	AvgScore       float64   `gorm:"column:avg_score;default:0" json:"avgScore"`             // 平均评分
+	AvgScore       float64   `gorm:"column:avg_score;default:0;index" json:"avgScore"`       // 平均评分
 	TotalShares    int       `gorm:"column:total_shares;default:0" json:"totalShares"`       // 总分享数
+	TotalFavorites int       `gorm:"column:total_favorites;default:0" json:"totalFavorites"` // 当前收藏数
 	ConversionRate float64   `gorm:"column:conversion_rate;default:0" json:"conversionRate"` // 转化率
 	CreatedAt      time.Time `json:"createdAt"`
 	UpdatedAt      time.Time `json:"updatedAt"`
@@ -410,6 +715,301 @@ type ProductStats struct {
 
 func (ProductStats) TableName() string { return "product_stats" }
 
+// ============================================
+// 分销佣金
+// ============================================
+
+// CommissionRule 适用范围
+const (
+	CommissionScopeSPU      = "spu"      // 指定商品
+	CommissionScopeCategory = "category" // 指定分类
+)
+
+// CommissionRecord 状态
+const (
+	CommissionStatusPending  = "pending"  // 待结算
+	CommissionStatusSettled  = "settled"  // 已结算
+	CommissionStatusReversed = "reversed" // 已冲正（退款）
+)
+
+// CommissionRule 佣金规则，商品规则优先于分类规则
+type CommissionRule struct {
+	ID        string    `gorm:"primaryKey" json:"_id"`
+	Scope     string    `gorm:"column:scope;index:idx_commission_rule_target" json:"scope"`        // spu / category
+	TargetID  string    `gorm:"column:target_id;index:idx_commission_rule_target" json:"targetId"` // SPU ID 或分类ID
+	Rate      float64   `gorm:"column:rate" json:"rate"`                                           // 佣金比例，如 0.05 表示 5%
+	Status    string    `gorm:"column:status;default:active" json:"status"`
+	CreatedBy string    `gorm:"column:created_by" json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (CommissionRule) TableName() string { return "commission_rule" }
+
+// CommissionRecord 佣金记录，每个订单项最多一条
+type CommissionRecord struct {
+	ID          string  `gorm:"primaryKey" json:"_id"`
+	ReferrerID  string  `gorm:"column:referrer_id;index" json:"referrerId"` // 获得佣金的推荐人
+	Referrer    *User   `gorm:"foreignKey:ReferrerID;references:ID" json:"referrer,omitempty"`
+	UserID      string  `gorm:"column:user_id;index" json:"userId"` // 下单用户
+	OrderID     string  `gorm:"column:order_id;index;uniqueIndex:idx_commission_order_item" json:"orderId"`
+	OrderItemID string  `gorm:"column:order_item_id;uniqueIndex:idx_commission_order_item" json:"orderItemId"` // 每个订单明细只生成一条佣金
+	SPUID       string  `gorm:"column:spu_id" json:"spuId"`
+	SKUID       string  `gorm:"column:sku_id" json:"skuId"`
+	RuleID      string  `gorm:"column:rule_id" json:"ruleId"`
+	BaseAmount  float64 `gorm:"column:base_amount" json:"baseAmount"` // 计佣金额
+	Rate        float64 `gorm:"column:rate" json:"rate"`
+	Amount      float64 `gorm:"column:amount" json:"amount"` // 佣金金额
+	Status      string  `gorm:"column:status;index" json:"status"`
+	SettledAt   *int64  `gorm:"column:settled_at" json:"settledAt,omitempty"`
+	ReversedAt  *int64  `gorm:"column:reversed_at" json:"reversedAt,omitempty"`
+	CreatedAt   int64   `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt   int64   `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+func (CommissionRecord) TableName() string { return "commission_record" }
+
+// ============================================
+// 储值钱包
+// ============================================
+
+// WalletTransaction 类型
+const (
+	WalletTxnTopUp  = "topup"  // 充值
+	WalletTxnAdjust = "adjust" // 人工调整
+	WalletTxnPay    = "pay"    // 订单支付
+	WalletTxnRefund = "refund" // 订单退款
+)
+
+// 复式记账科目，用户账户为 "user:<userID>"
+const (
+	WalletAccountStoreCash       = "store:cash"       // 门店收款（充值卡）
+	WalletAccountStoreSales      = "store:sales"      // 销售收入
+	WalletAccountStoreAdjustment = "store:adjustment" // 人工调整
+)
+
+// WalletEntry 记账方向
+const (
+	WalletEntryDebit  = "debit"
+	WalletEntryCredit = "credit"
+)
+
+// Wallet 用户储值余额
+type Wallet struct {
+	ID             string    `gorm:"primaryKey" json:"_id"`
+	UserID         string    `gorm:"column:user_id;uniqueIndex" json:"userId"`
+	User           *User     `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+	Balance        float64   `gorm:"column:balance;default:0" json:"balance"`
+	TotalRecharged float64   `gorm:"column:total_recharged;default:0" json:"totalRecharged"` // 累计充值
+	TotalSpent     float64   `gorm:"column:total_spent;default:0" json:"totalSpent"`         // 累计消费
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+func (Wallet) TableName() string { return "wallet" }
+
+// WalletTransaction 钱包流水（一笔业务），明细见 WalletEntry
+type WalletTransaction struct {
+	ID           string        `gorm:"primaryKey" json:"_id"`
+	UserID       string        `gorm:"column:user_id;index" json:"userId"`
+	Type         string        `gorm:"column:type;index" json:"type"`
+	Amount       float64       `gorm:"column:amount" json:"amount"`              // 余额变动，正数为增加
+	BalanceAfter float64       `gorm:"column:balance_after" json:"balanceAfter"` // 变动后余额
+	OrderID      string        `gorm:"column:order_id;index" json:"orderId,omitempty"`
+	OperatorID   string        `gorm:"column:operator_id" json:"operatorId,omitempty"` // 操作管理员
+	Remark       string        `gorm:"column:remark" json:"remark"`
+	Entries      []WalletEntry `gorm:"foreignKey:TransactionID;references:ID" json:"entries,omitempty"`
+	CreatedAt    int64         `gorm:"column:created_at;index" json:"createdAt"`
+}
+
+func (WalletTransaction) TableName() string { return "wallet_transaction" }
+
+// WalletEntry 复式记账分录，每笔流水借贷金额相等
+type WalletEntry struct {
+	ID            string  `gorm:"primaryKey" json:"_id"`
+	TransactionID string  `gorm:"column:transaction_id;index" json:"transactionId"`
+	Account       string  `gorm:"column:account;index" json:"account"`
+	Direction     string  `gorm:"column:direction" json:"direction"` // debit / credit
+	Amount        float64 `gorm:"column:amount" json:"amount"`
+	CreatedAt     int64   `gorm:"column:created_at" json:"createdAt"`
+}
+
+func (WalletEntry) TableName() string { return "wallet_entry" }
+
+// ============================================
+// 库存流水
+// ============================================
+
+// StockMovement 变动原因
+const (
+	StockReasonInitial = "initial" // 新建 SKU 初始库存
+	StockReasonAdjust  = "adjust"  // 人工调整
+	StockReasonOrder   = "order"   // 下单扣减
+	StockReasonCancel  = "cancel"  // 取消订单归还
+	StockReasonRefund  = "refund"  // 退款归还
+	StockReasonImport  = "import"  // 批量导入
+)
+
+// DefaultLowStockThreshold 新建 SKU 的默认低库存预警值
+const DefaultLowStockThreshold = 10
+
+// StockMovement 库存流水，SKU 库存每次变动记录一条
+type StockMovement struct {
+	ID          string `gorm:"primaryKey" json:"_id"`
+	SKUID       string `gorm:"column:sku_id;index" json:"skuId"`
+	SPUID       string `gorm:"column:spu_id;index" json:"spuId"`
+	Reason      string `gorm:"column:reason;index" json:"reason"`
+	Quantity    int    `gorm:"column:quantity" json:"quantity"`                        // 变动数量，正数为入库，负数为出库
+	CountAfter  int    `gorm:"column:count_after" json:"countAfter"`                   // 变动后库存
+	WarehouseID string `gorm:"column:warehouse_id;index" json:"warehouseId,omitempty"` // 仓库库存变动时的仓库
+	RefID       string `gorm:"column:ref_id;index" json:"refId,omitempty"`             // 关联单据ID（订单ID等）
+	OperatorID  string `gorm:"column:operator_id" json:"operatorId,omitempty"`
+	Remark      string `gorm:"column:remark" json:"remark"`
+	CreatedAt   int64  `gorm:"column:created_at;index" json:"createdAt"`
+}
+
+func (StockMovement) TableName() string { return "stock_movement" }
+
+// StockAlert 低库存预警，库存由高于预警值降到预警值及以下时生成
+type StockAlert struct {
+	ID             string `gorm:"primaryKey" json:"_id"`
+	SKUID          string `gorm:"column:sku_id;index" json:"skuId"`
+	SKU            *SKU   `gorm:"foreignKey:SKUID;references:ID" json:"sku,omitempty"`
+	SPUID          string `gorm:"column:spu_id;index" json:"spuId"`
+	Count          int    `gorm:"column:count" json:"count"`         // 触发时库存
+	Threshold      int    `gorm:"column:threshold" json:"threshold"` // 触发时预警值
+	AcknowledgedAt *int64 `gorm:"column:acknowledged_at;index" json:"acknowledgedAt"`
+	AcknowledgedBy string `gorm:"column:acknowledged_by" json:"acknowledgedBy,omitempty"`
+	CreatedAt      int64  `gorm:"column:created_at;index" json:"createdAt"`
+}
+
+func (StockAlert) TableName() string { return "stock_alert" }
+
+// ============================================
+// SKU 调价记录
+// ============================================
+
+// SKU 调价来源
+const (
+	PriceSourceCreate  = "create"  // 新建 SKU
+	PriceSourceUpdate  = "update"  // 编辑 SKU
+	PriceSourceMatrix  = "matrix"  // 按规格组合生成
+	PriceSourceImport  = "import"  // 批量导入
+	PriceSourceRestore = "restore" // 恢复历史版本
+)
+
+// SKUPriceHistory SKU 调价记录，SKU 价格每次变动记录一条
+type SKUPriceHistory struct {
+	ID          string   `gorm:"primaryKey" json:"_id"`
+	SKUID       string   `gorm:"column:sku_id;index:idx_sku_price_history_sku_effective,priority:1" json:"skuId"`
+	SPUID       string   `gorm:"column:spu_id;index:idx_sku_price_history_spu_effective,priority:1" json:"spuId"`
+	OldPrice    *float64 `gorm:"column:old_price" json:"oldPrice"` // 新建 SKU 时为空
+	NewPrice    float64  `gorm:"column:new_price" json:"newPrice"`
+	Source      string   `gorm:"column:source" json:"source"`
+	AdminID     string   `gorm:"column:admin_id" json:"adminId,omitempty"`
+	EffectiveAt int64    `gorm:"column:effective_at;index:idx_sku_price_history_sku_effective,priority:2;index:idx_sku_price_history_spu_effective,priority:2" json:"effectiveAt"`
+}
+
+func (SKUPriceHistory) TableName() string { return "sku_price_history" }
+
+// ============================================
+// 仓库
+// ============================================
+
+// OrderShipment 状态
+const (
+	ShipmentStatusPending = "PENDING" // 待发货
+	ShipmentStatusShipped = "SHIPPED" // 已发货
+)
+
+// Warehouse 发货仓库；存在启用的仓库时，下单按收货地区为每个明细分配仓库并扣减仓库库存
+// 最早创建的仓库为默认仓库：创建时按现有总库存初始化，未指定仓库的库存变动记到默认仓库
+type Warehouse struct {
+	ID        string         `gorm:"primaryKey" json:"_id"`
+	Code      string         `gorm:"column:code;uniqueIndex" json:"code"`
+	Name      string         `gorm:"column:name" json:"name"`
+	Address   string         `gorm:"column:address" json:"address"`
+	Regions   datatypes.JSON `gorm:"column:regions;type:json" json:"regions"`   // 优先发货的省份（名称或编码）
+	Priority  int            `gorm:"column:priority;default:0" json:"priority"` // 地区相同时优先级高的仓库先分配
+	Status    string         `gorm:"column:status;default:ENABLED" json:"status"`
+	CreatedAt int64          `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt int64          `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+func (Warehouse) TableName() string { return "warehouse" }
+
+// WarehouseStock 仓库库存；存在仓库时 SKU.Count 为各仓库库存之和，随仓库库存同步变动
+type WarehouseStock struct {
+	ID          string `gorm:"primaryKey" json:"_id"`
+	WarehouseID string `gorm:"column:warehouse_id;uniqueIndex:idx_warehouse_stock_sku" json:"warehouseId"`
+	SKUID       string `gorm:"column:sku_id;uniqueIndex:idx_warehouse_stock_sku;index" json:"skuId"`
+	SKU         *SKU   `gorm:"foreignKey:SKUID;references:ID" json:"sku,omitempty"`
+	Count       int    `gorm:"column:count" json:"count"`
+	UpdatedAt   int64  `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+func (WarehouseStock) TableName() string { return "warehouse_stock" }
+
+// OrderShipment 发货单，订单按分配的仓库拆分，每个仓库一张
+type OrderShipment struct {
+	ID          string      `gorm:"primaryKey" json:"_id"`
+	OrderID     string      `gorm:"column:order_id;index" json:"orderId"`
+	Order       *Order      `gorm:"foreignKey:OrderID;references:ID" json:"order,omitempty"`
+	WarehouseID string      `gorm:"column:warehouse_id;index" json:"warehouseId"`
+	Warehouse   *Warehouse  `gorm:"foreignKey:WarehouseID;references:ID" json:"warehouse,omitempty"`
+	Items       []OrderItem `gorm:"foreignKey:ShipmentID;references:ID" json:"items,omitempty"`
+	Status      string      `gorm:"column:status;index" json:"status"`
+	TrackingNo  string      `gorm:"column:tracking_no" json:"trackingNo"`
+	ShippedAt   *int64      `gorm:"column:shipped_at" json:"shippedAt"`
+	ShippedBy   string      `gorm:"column:shipped_by" json:"shippedBy,omitempty"`
+	CreatedAt   int64       `gorm:"column:created_at;index" json:"createdAt"`
+	UpdatedAt   int64       `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+func (OrderShipment) TableName() string { return "order_shipment" }
+
+// ============================================
+// 商品搜索索引
+// ============================================
+
+// SearchDocument 商品搜索文档（PostgreSQL 全文检索索引表，由搜索服务维护）
+type SearchDocument struct {
+	SPUID       string  `gorm:"column:spu_id;primaryKey" json:"spuId"`
+	Name        string  `gorm:"column:name" json:"name"`
+	Keywords    string  `gorm:"column:keywords" json:"keywords"`
+	Content     string  `gorm:"column:content" json:"content"`
+	NameTerms   string  `gorm:"column:name_terms" json:"-"` // 分词结果，空格分隔
+	KeyTerms    string  `gorm:"column:key_terms" json:"-"`
+	BodyTerms   string  `gorm:"column:body_terms" json:"-"`
+	Pinyin      string  `gorm:"column:pinyin" json:"pinyin"`
+	Initials    string  `gorm:"column:initials" json:"initials"`
+	CategoryID  string  `gorm:"column:category_id;index" json:"categoryId"`
+	Category    string  `gorm:"column:category_name" json:"categoryName"`
+	TagIDs      string  `gorm:"column:tag_ids" json:"tagIds"` // 逗号分隔
+	TagNames    string  `gorm:"column:tag_names" json:"tagNames"`
+	MinPrice    float64 `gorm:"column:min_price" json:"minPrice"`
+	MaxPrice    float64 `gorm:"column:max_price" json:"maxPrice"`
+	Priority    int     `gorm:"column:priority" json:"priority"`
+	Status      string  `gorm:"column:status" json:"status"`
+	PublishAt   *int64  `gorm:"column:publish_at" json:"publishAt"`
+	UnpublishAt *int64  `gorm:"column:unpublish_at" json:"unpublishAt"`
+	UpdatedAt   int64   `gorm:"column:updated_at" json:"updatedAt"`
+	TSV         string  `gorm:"column:tsv;type:tsvector;->" json:"-"` // 由 name_terms/key_terms/body_terms 生成
+}
+
+func (SearchDocument) TableName() string { return "search_document" }
+
+// SearchLog 商品搜索日志
+type SearchLog struct {
+	ID          string `gorm:"primaryKey" json:"_id"`
+	Keyword     string `gorm:"column:keyword;index" json:"keyword"` // 规范化后的关键词
+	UserID      string `gorm:"column:user_id;index" json:"userId,omitempty"`
+	ResultCount int64  `gorm:"column:result_count" json:"resultCount"`
+	CreatedAt   int64  `gorm:"column:created_at;index" json:"createdAt"`
+}
+
+func (SearchLog) TableName() string { return "search_log" }
+
 // ============================================
 // 工具类型
 // ============================================
@@ -0,0 +1,8 @@
+package internal
+
+import "math"
+
+// RoundMoney 金额四舍五入到分
+func RoundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
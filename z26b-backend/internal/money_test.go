@@ -0,0 +1,25 @@
+package internal
+
+import "testing"
+
+func TestRoundMoney(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount float64
+		want   float64
+	}{
+		{"already rounded", 12.5, 12.5},
+		{"round down", 3.333, 3.33},
+		{"round up", 2.675001, 2.68},
+		{"commission", 99.99 * 0.05, 5},
+		{"zero", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RoundMoney(tt.amount); got != tt.want {
+				t.Errorf("RoundMoney() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
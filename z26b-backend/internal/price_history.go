@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RecordSKUPriceChanges SKU 调价钩子：记录调价历史（价格未变的跳过）并同步 SPU 价格范围，
+// 所有修改 SKU 价格的地方都应在同一事务中调用
+func RecordSKUPriceChanges(tx *gorm.DB, spuID string, changes []SKUPriceHistory, source, adminID string) error {
+	now := time.Now().UnixMilli()
+	rows := make([]SKUPriceHistory, 0, len(changes))
+	for _, change := range changes {
+		if change.OldPrice != nil && *change.OldPrice == change.NewPrice {
+			continue
+		}
+		change.ID = GenerateUUID()
+		change.SPUID = spuID
+		change.Source = source
+		change.AdminID = adminID
+		if change.EffectiveAt == 0 {
+			change.EffectiveAt = now
+		}
+		rows = append(rows, change)
+	}
+	if len(rows) > 0 {
+		if err := tx.Create(&rows).Error; err != nil {
+			return err
+		}
+	}
+	return UpdateSPUPriceRange(tx, spuID)
+}
+
+// PriceChange 构造一条 SKU 调价记录，oldPrice 为空表示新建
+func PriceChange(skuID string, oldPrice *float64, newPrice float64) SKUPriceHistory {
+	return SKUPriceHistory{SKUID: skuID, OldPrice: oldPrice, NewPrice: newPrice}
+}
+
+// LowestPriceWindow 价格展示合规使用的最低价统计周期
+const LowestPriceWindow = 30 * 24 * time.Hour
+
+// LowestPriceSince 统计期内出现过的最低价：当前价格、统计期内每次调价前后的价格，
+// 统计期内第一次调价的原价即统计期开始时的价格；没有任何价格时返回 0
+func LowestPriceSince(current []float64, changes []SKUPriceHistory, since int64) float64 {
+	lowest := -1.0
+	take := func(price float64) {
+		if lowest < 0 || price < lowest {
+			lowest = price
+		}
+	}
+	for _, price := range current {
+		take(price)
+	}
+	for _, change := range changes {
+		if change.EffectiveAt < since {
+			continue
+		}
+		take(change.NewPrice)
+		if change.OldPrice != nil {
+			take(*change.OldPrice)
+		}
+	}
+	if lowest < 0 {
+		return 0
+	}
+	return lowest
+}
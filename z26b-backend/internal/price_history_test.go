@@ -0,0 +1,39 @@
+package internal
+
+import "testing"
+
+func TestLowestPriceSince(t *testing.T) {
+	price := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name    string
+		current []float64
+		changes []SKUPriceHistory
+		want    float64
+	}{
+		{"no history", []float64{99, 89}, nil, 89},
+		{"price raised in window", []float64{120}, []SKUPriceHistory{
+			{OldPrice: price(80), NewPrice: 120, EffectiveAt: 200},
+		}, 80},
+		{"temporary discount", []float64{100}, []SKUPriceHistory{
+			{OldPrice: price(100), NewPrice: 60, EffectiveAt: 150},
+			{OldPrice: price(60), NewPrice: 100, EffectiveAt: 180},
+		}, 60},
+		{"change before window ignored", []float64{100}, []SKUPriceHistory{
+			{OldPrice: price(50), NewPrice: 100, EffectiveAt: 50},
+		}, 100},
+		{"new sku", []float64{30}, []SKUPriceHistory{
+			{NewPrice: 30, EffectiveAt: 120},
+		}, 30},
+		{"deleted sku still counts", []float64{100}, []SKUPriceHistory{
+			{NewPrice: 70, EffectiveAt: 120},
+		}, 70},
+		{"nothing", nil, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LowestPriceSince(tt.current, tt.changes, 100); got != tt.want {
+				t.Errorf("LowestPriceSince() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
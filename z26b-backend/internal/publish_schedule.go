@@ -0,0 +1,31 @@
+package internal
+
+import "gorm.io/gorm"
+
+// SPUEffectiveStatus 计算商品在 now 时刻的实际状态：按时间顺序应用已到期的定时上架/下架，
+// 同一时刻同时到期时下架优先；定时任务尚未执行时也能得到准确结果
+func SPUEffectiveStatus(status string, publishAt, unpublishAt *int64, now int64) string {
+	publishDue := publishAt != nil && *publishAt <= now
+	unpublishDue := unpublishAt != nil && *unpublishAt <= now
+	switch {
+	case publishDue && unpublishDue:
+		if *publishAt > *unpublishAt {
+			return "ENABLED"
+		}
+		return "DISABLED"
+	case publishDue:
+		return "ENABLED"
+	case unpublishDue:
+		return "DISABLED"
+	}
+	return status
+}
+
+// SPUVisibleAt 查询在 now 时刻处于上架状态的商品，与 SPUEffectiveStatus 规则一致
+func SPUVisibleAt(now int64) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(`((publish_at <= ? AND (unpublish_at IS NULL OR unpublish_at > ? OR unpublish_at < publish_at))
+			OR (status = ? AND (unpublish_at IS NULL OR unpublish_at > ?)))`,
+			now, now, "ENABLED", now)
+	}
+}
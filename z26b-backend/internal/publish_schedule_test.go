@@ -0,0 +1,30 @@
+package internal
+
+import "testing"
+
+func TestSPUEffectiveStatus(t *testing.T) {
+	at := func(v int64) *int64 { return &v }
+	tests := []struct {
+		name        string
+		status      string
+		publishAt   *int64
+		unpublishAt *int64
+		want        string
+	}{
+		{"no schedule", "DISABLED", nil, nil, "DISABLED"},
+		{"publish pending", "DISABLED", at(200), nil, "DISABLED"},
+		{"publish due", "DISABLED", at(100), nil, "ENABLED"},
+		{"unpublish due", "ENABLED", nil, at(100), "DISABLED"},
+		{"window open", "DISABLED", at(50), at(200), "ENABLED"},
+		{"window closed", "DISABLED", at(50), at(100), "DISABLED"},
+		{"relisted after takedown", "ENABLED", at(100), at(50), "ENABLED"},
+		{"same time unpublish wins", "ENABLED", at(100), at(100), "DISABLED"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SPUEffectiveStatus(tt.status, tt.publishAt, tt.unpublishAt, 150); got != tt.want {
+				t.Errorf("SPUEffectiveStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
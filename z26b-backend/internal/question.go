@@ -0,0 +1,23 @@
+package internal
+
+import "gorm.io/gorm"
+
+// HasPurchasedSPU 用户是否有已完成的订单包含该商品
+func HasPurchasedSPU(db *gorm.DB, userID, spuID string) (bool, error) {
+	var count int64
+	err := db.Table("order_item").
+		Joins(`JOIN "order" ON "order".id = order_item.order_id`).
+		Joins(`JOIN sku ON sku.id = order_item.sku_id`).
+		Where(`"order".user_id = ? AND "order".status = ? AND sku."SPUID" = ?`, userID, OrderStatusFinished, spuID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// RefreshQuestionCounts 根据审核通过的回答重新统计提问的回答数和“有用”数
+func RefreshQuestionCounts(db *gorm.DB, questionID string) error {
+	return db.Exec(`UPDATE product_question SET
+			answer_count = (SELECT COUNT(*) FROM product_answer WHERE question_id = ? AND status = ?),
+			helpful_count = (SELECT COALESCE(SUM(helpful_count), 0) FROM product_answer WHERE question_id = ? AND status = ?)
+		WHERE id = ?`,
+		questionID, QAStatusApproved, questionID, QAStatusApproved, questionID).Error
+}
@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newQuestionTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&Order{}, &OrderItem{}, &SKU{}, &ProductQuestion{}, &ProductAnswer{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestHasPurchasedSPU(t *testing.T) {
+	db := newQuestionTestDB(t)
+	db.Create(&SKU{ID: "k1", SPUID: "p1"})
+	db.Create(&SKU{ID: "k2", SPUID: "p2"})
+	db.Create(&Order{ID: "o1", UserID: "u1", Status: OrderStatusFinished})
+	db.Create(&OrderItem{ID: "i1", OrderID: "o1", SKUID: "k1"})
+	db.Create(&Order{ID: "o2", UserID: "u1", Status: "PENDING"})
+	db.Create(&OrderItem{ID: "i2", OrderID: "o2", SKUID: "k2"})
+
+	tests := []struct {
+		name   string
+		userID string
+		spuID  string
+		want   bool
+	}{
+		{"已完成订单", "u1", "p1", true},
+		{"未完成订单", "u1", "p2", false},
+		{"其他用户", "u2", "p1", false},
+		{"未购买商品", "u1", "p3", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := HasPurchasedSPU(db, tt.userID, tt.spuID)
+			if err != nil {
+				t.Fatalf("HasPurchasedSPU() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("HasPurchasedSPU(%q, %q) = %v, want %v", tt.userID, tt.spuID, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefreshQuestionCounts(t *testing.T) {
+	tests := []struct {
+		name        string
+		answers     []ProductAnswer
+		wantAnswers int
+		wantHelpful int
+	}{
+		{"没有回答", nil, 0, 0},
+		{"只统计审核通过的回答", []ProductAnswer{
+			{ID: "a1", QuestionID: "q1", Status: QAStatusApproved, HelpfulCount: 3},
+			{ID: "a2", QuestionID: "q1", Status: QAStatusApproved, HelpfulCount: 2},
+			{ID: "a3", QuestionID: "q1", Status: QAStatusPending, HelpfulCount: 5},
+			{ID: "a4", QuestionID: "q1", Status: QAStatusRejected, HelpfulCount: 7},
+			{ID: "a5", QuestionID: "q2", Status: QAStatusApproved, HelpfulCount: 1},
+		}, 2, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newQuestionTestDB(t)
+			db.Create(&ProductQuestion{ID: "q1", AnswerCount: 9, HelpfulCount: 9})
+			for _, answer := range tt.answers {
+				db.Create(&answer)
+			}
+
+			if err := RefreshQuestionCounts(db, "q1"); err != nil {
+				t.Fatalf("RefreshQuestionCounts() error = %v", err)
+			}
+			var question ProductQuestion
+			db.First(&question, "id = ?", "q1")
+			if question.AnswerCount != tt.wantAnswers || question.HelpfulCount != tt.wantHelpful {
+				t.Errorf("counts = %d/%d, want %d/%d", question.AnswerCount, question.HelpfulCount, tt.wantAnswers, tt.wantHelpful)
+			}
+		})
+	}
+}
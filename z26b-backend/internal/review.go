@@ -0,0 +1,19 @@
+package internal
+
+import "strings"
+
+// AnonymousUserName 匿名评价且用户没有昵称时展示的名称
+const AnonymousUserName = "匿名用户"
+
+// MaskUserName 匿名评价时隐藏昵称，只保留首尾字符，如“张三丰”显示为“张***丰”
+func MaskUserName(name string) string {
+	runes := []rune(strings.TrimSpace(name))
+	switch len(runes) {
+	case 0:
+		return AnonymousUserName
+	case 1, 2:
+		return string(runes[0]) + "***"
+	default:
+		return string(runes[0]) + "***" + string(runes[len(runes)-1])
+	}
+}
@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+)
+
+// 评价图片/视频限制
+const (
+	MaxReviewImageSize    = 5 << 20   // 单张图片大小
+	MaxReviewVideoSize    = 50 << 20  // 单个视频大小
+	MaxReviewMedia        = 9         // 每条评价的图片/视频数
+	MaxReviewVideos       = 1         // 每条评价的视频数
+	ReviewMediaDailyCount = 50        // 每个用户每天的上传次数
+	ReviewMediaDailyBytes = 300 << 20 // 每个用户每天的上传总大小
+)
+
+// reviewMediaFormats 允许的扩展名及对应的文件类型
+var reviewMediaFormats = map[string]struct{ kind, mime string }{
+	".jpg":  {MediaTypeImage, "image/jpeg"},
+	".jpeg": {MediaTypeImage, "image/jpeg"},
+	".png":  {MediaTypeImage, "image/png"},
+	".gif":  {MediaTypeImage, "image/gif"},
+	".webp": {MediaTypeImage, "image/webp"},
+	".mp4":  {MediaTypeVideo, "video/mp4"},
+}
+
+// ReviewMediaType 校验评价图片/视频的扩展名、按文件内容识别的类型和大小，返回 image 或 video 及 MIME 类型
+func ReviewMediaType(filename, detected string, size int64) (string, string, error) {
+	format, ok := reviewMediaFormats[strings.ToLower(filepath.Ext(filename))]
+	if !ok {
+		return "", "", errors.New("only jpg, png, gif, webp images and mp4 videos are allowed")
+	}
+	if detected != format.mime {
+		return "", "", errors.New("file content does not match its type")
+	}
+	if size <= 0 {
+		return "", "", errors.New("file is empty")
+	}
+	if format.kind == MediaTypeImage && size > MaxReviewImageSize {
+		return "", "", errors.New("image must not exceed 5MB")
+	}
+	if format.kind == MediaTypeVideo && size > MaxReviewVideoSize {
+		return "", "", errors.New("video must not exceed 50MB")
+	}
+	return format.kind, format.mime, nil
+}
@@ -0,0 +1,35 @@
+package internal
+
+import "testing"
+
+func TestReviewMediaType(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		detected string
+		size     int64
+		kind     string
+		wantErr  bool
+	}{
+		{"jpeg", "a.JPG", "image/jpeg", 1024, MediaTypeImage, false},
+		{"webp", "a.webp", "image/webp", 1024, MediaTypeImage, false},
+		{"mp4", "clip.mp4", "video/mp4", 10 << 20, MediaTypeVideo, false},
+		{"unsupported extension", "a.bmp", "image/bmp", 1024, "", true},
+		{"renamed file", "a.png", "text/html; charset=utf-8", 1024, "", true},
+		{"image too large", "a.png", "image/png", MaxReviewImageSize + 1, "", true},
+		{"video at limit", "a.mp4", "video/mp4", MaxReviewVideoSize, MediaTypeVideo, false},
+		{"video too large", "a.mp4", "video/mp4", MaxReviewVideoSize + 1, "", true},
+		{"empty", "a.png", "image/png", 0, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, _, err := ReviewMediaType(tt.filename, tt.detected, tt.size)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReviewMediaType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if kind != tt.kind {
+				t.Errorf("ReviewMediaType() kind = %q, want %q", kind, tt.kind)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package internal
+
+import "testing"
+
+func TestMaskUserName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"", AnonymousUserName},
+		{"  ", AnonymousUserName},
+		{"李", "李***"},
+		{"李四", "李***"},
+		{"张三丰", "张***丰"},
+		{"Alice", "A***e"},
+	}
+	for _, tt := range tests {
+		if got := MaskUserName(tt.name); got != tt.want {
+			t.Errorf("MaskUserName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package internal
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// RichTextNode 富文本节点，结构与小程序 rich-text 组件的 nodes 一致
+type RichTextNode struct {
+	Name     string            `json:"name,omitempty"`
+	Attrs    map[string]string `json:"attrs,omitempty"`
+	Children []RichTextNode    `json:"children,omitempty"`
+	Type     string            `json:"type,omitempty"` // 文本节点为 text
+	Text     string            `json:"text,omitempty"`
+}
+
+// richTextTags 允许的标签及各自允许的属性（class、style 所有标签均允许）
+var richTextTags = map[string]map[string]bool{
+	"p": nil, "div": nil, "span": nil, "br": nil, "hr": nil, "section": nil,
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+	"strong": nil, "b": nil, "em": nil, "i": nil, "u": nil, "s": nil, "del": nil, "ins": nil,
+	"sub": nil, "sup": nil, "blockquote": nil, "pre": nil, "code": nil,
+	"ul": nil, "ol": {"start": true}, "li": nil,
+	"a":      {"href": true, "title": true, "target": true},
+	"img":    {"src": true, "alt": true, "title": true, "width": true, "height": true},
+	"figure": nil, "figcaption": nil,
+	"table": nil, "thead": nil, "tbody": nil, "tfoot": nil, "tr": nil,
+	"th": {"colspan": true, "rowspan": true}, "td": {"colspan": true, "rowspan": true},
+}
+
+// richTextDropContent 连同内容一起删除的标签
+var richTextDropContent = map[string]bool{
+	"script": true, "style": true, "iframe": true, "frame": true, "frameset": true,
+	"object": true, "embed": true, "applet": true, "noscript": true, "noembed": true,
+	"template": true, "textarea": true, "select": true, "head": true, "title": true,
+	"svg": true, "math": true, "xmp": true, "plaintext": true,
+}
+
+var richTextVoidTags = map[string]bool{"br": true, "hr": true, "img": true}
+
+// richTextStyleProps 允许的内联样式属性
+var richTextStyleProps = map[string]bool{
+	"color": true, "background-color": true, "font-size": true, "font-weight": true, "font-style": true,
+	"font-family": true, "text-align": true, "text-decoration": true, "text-indent": true,
+	"line-height": true, "letter-spacing": true, "vertical-align": true, "white-space": true,
+	"margin": true, "margin-top": true, "margin-right": true, "margin-bottom": true, "margin-left": true,
+	"padding": true, "padding-top": true, "padding-right": true, "padding-bottom": true, "padding-left": true,
+	"width": true, "height": true, "max-width": true, "border": true, "border-collapse": true,
+	"list-style-type": true,
+}
+
+// ParseRichText 按白名单清洗 HTML 并解析为节点树：删除脚本等危险标签及其内容、事件属性、
+// 不安全的链接和样式，其他不在白名单的标签只保留文本内容
+func ParseRichText(input string) []RichTextNode {
+	root := &RichTextNode{}
+	stack := []*RichTextNode{root}
+	skipTag, skipDepth := "", 0
+
+	z := html.NewTokenizer(strings.NewReader(input))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return root.Children
+		}
+		tok := z.Token()
+		name := strings.ToLower(tok.Data)
+
+		if skipDepth > 0 {
+			switch {
+			case tt == html.StartTagToken && name == skipTag:
+				skipDepth++
+			case tt == html.EndTagToken && name == skipTag:
+				skipDepth--
+			}
+			continue
+		}
+
+		switch tt {
+		case html.TextToken:
+			parent := stack[len(stack)-1]
+			if n := len(parent.Children); n > 0 && parent.Children[n-1].Type == "text" {
+				parent.Children[n-1].Text += tok.Data
+			} else {
+				parent.Children = append(parent.Children, RichTextNode{Type: "text", Text: tok.Data})
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if richTextDropContent[name] {
+				if tt == html.StartTagToken {
+					skipTag, skipDepth = name, 1
+				}
+				continue
+			}
+			if _, ok := richTextTags[name]; !ok {
+				continue
+			}
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, RichTextNode{Name: name, Attrs: richTextAttrs(name, tok.Attr)})
+			if tt == html.StartTagToken && !richTextVoidTags[name] {
+				stack = append(stack, &parent.Children[len(parent.Children)-1])
+			}
+		case html.EndTagToken:
+			// 关闭最近的同名标签，未闭合的内层标签一并关闭；没有匹配的开始标签时忽略
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].Name == name {
+					stack = stack[:i]
+					break
+				}
+			}
+		}
+	}
+}
+
+// richTextAttrs 过滤标签属性
+func richTextAttrs(tag string, attrs []html.Attribute) map[string]string {
+	out := make(map[string]string)
+	for _, a := range attrs {
+		key := strings.ToLower(a.Key)
+		if a.Namespace != "" || (key != "class" && key != "style" && !richTextTags[tag][key]) {
+			continue
+		}
+		val := strings.TrimSpace(a.Val)
+		switch key {
+		case "href":
+			if !safeRichTextURL(val, "http", "https", "mailto", "tel") {
+				continue
+			}
+		case "src":
+			if !safeRichTextURL(val, "http", "https") {
+				continue
+			}
+		case "target":
+			if val != "_blank" {
+				continue
+			}
+		case "style":
+			if val = sanitizeStyle(val); val == "" {
+				continue
+			}
+		}
+		out[key] = val
+	}
+	if out["target"] != "" {
+		out["rel"] = "noopener noreferrer"
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// safeRichTextURL 链接为相对地址或使用允许的协议
+func safeRichTextURL(raw string, schemes ...string) bool {
+	if raw == "" {
+		return false
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "" {
+		return !strings.Contains(u.Path, ":")
+	}
+	for _, s := range schemes {
+		if u.Scheme == s {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeStyle 过滤内联样式，只保留白名单属性且值中不含 url()、expression() 等
+func sanitizeStyle(style string) string {
+	var kept []string
+	for _, decl := range strings.Split(style, ";") {
+		prop, value, ok := strings.Cut(decl, ":")
+		if !ok {
+			continue
+		}
+		prop = strings.ToLower(strings.TrimSpace(prop))
+		value = strings.TrimSpace(value)
+		lower := strings.ToLower(value)
+		if !richTextStyleProps[prop] || value == "" || strings.ContainsAny(value, `<>\"'`) ||
+			strings.Contains(lower, "url(") || strings.Contains(lower, "expression") || strings.Contains(lower, "javascript:") {
+			continue
+		}
+		kept = append(kept, prop+": "+value)
+	}
+	return strings.Join(kept, "; ")
+}
+
+// RenderRichText 将节点树输出为 HTML
+func RenderRichText(nodes []RichTextNode) string {
+	var b strings.Builder
+	renderRichText(&b, nodes)
+	return b.String()
+}
+
+func renderRichText(b *strings.Builder, nodes []RichTextNode) {
+	for _, n := range nodes {
+		if n.Type == "text" {
+			b.WriteString(html.EscapeString(n.Text))
+			continue
+		}
+		b.WriteString("<" + n.Name)
+		keys := make([]string, 0, len(n.Attrs))
+		for k := range n.Attrs {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			b.WriteString(" " + k + `="` + html.EscapeString(n.Attrs[k]) + `"`)
+		}
+		b.WriteString(">")
+		if richTextVoidTags[n.Name] {
+			continue
+		}
+		renderRichText(b, n.Children)
+		b.WriteString("</" + n.Name + ">")
+	}
+}
+
+// SanitizeHTML 按白名单清洗富文本 HTML，用于保存管理后台编辑的商品详情、首页内容等
+func SanitizeHTML(input string) string {
+	return RenderRichText(ParseRichText(input))
+}
+
+// miniProgramTagAliases rich-text 组件不支持的标签替换为等效标签
+var miniProgramTagAliases = map[string]string{
+	"section": "div", "figure": "div", "figcaption": "div", "blockquote": "div", "pre": "div",
+	"u": "ins", "s": "del",
+}
+
+// MiniProgramRichText 清洗 HTML 并转换为小程序 rich-text 组件可用的节点树：
+// 替换不支持的标签，图片限制最大宽度
+func MiniProgramRichText(input string) []RichTextNode {
+	nodes := ParseRichText(input)
+	adaptMiniProgramNodes(nodes)
+	return nodes
+}
+
+func adaptMiniProgramNodes(nodes []RichTextNode) {
+	for i := range nodes {
+		n := &nodes[i]
+		if alias, ok := miniProgramTagAliases[n.Name]; ok {
+			n.Name = alias
+		}
+		if n.Name == "img" {
+			if n.Attrs == nil {
+				n.Attrs = make(map[string]string)
+			}
+			if n.Attrs["style"] == "" {
+				n.Attrs["style"] = "max-width: 100%; height: auto"
+			}
+		}
+		adaptMiniProgramNodes(n.Children)
+	}
+}
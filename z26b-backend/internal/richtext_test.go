@@ -0,0 +1,49 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain text", "hello & <b>world</b>", "hello &amp; <b>world</b>"},
+		{"script removed with content", `<p>a<script>alert(1)</script>b</p>`, "<p>ab</p>"},
+		{"nested svg", `<svg><svg>x</svg>y</svg>z`, "z"},
+		{"event handler", `<img src="https://a.com/1.png" onerror="alert(1)">`, `<img src="https://a.com/1.png">`},
+		{"javascript url", `<a href="JavaScript:alert(1)">x</a>`, "<a>x</a>"},
+		{"entity-encoded url", `<a href="&#106;avascript:alert(1)">x</a>`, "<a>x</a>"},
+		{"data image", `<img src="data:image/png;base64,AAAA">`, "<img>"},
+		{"relative url", `<a href="/pages/goods?id=1">x</a>`, `<a href="/pages/goods?id=1">x</a>`},
+		{"target blank", `<a href="https://a.com" target="_blank">x</a>`,
+			`<a href="https://a.com" rel="noopener noreferrer" target="_blank">x</a>`},
+		{"unknown tag unwrapped", `<font color="red"><span>x</span></font>`, "<span>x</span>"},
+		{"style filtered", `<p style="color: red; background: url(x); position: fixed">x</p>`, `<p style="color: red">x</p>`},
+		{"unclosed tags closed", `<p><strong>x`, "<p><strong>x</strong></p>"},
+		{"stray end tag", `x</div>y`, "xy"},
+		{"comment removed", `a<!-- <script> -->b`, "ab"},
+		{"void tags", `a<br/>b<hr>`, "a<br>b<hr>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeHTML(tt.input); got != tt.want {
+				t.Errorf("SanitizeHTML() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMiniProgramRichText(t *testing.T) {
+	nodes := MiniProgramRichText(`<section><img src="https://a.com/1.png"><u>x</u></section>`)
+	want := []RichTextNode{{Name: "div", Children: []RichTextNode{
+		{Name: "img", Attrs: map[string]string{"src": "https://a.com/1.png", "style": "max-width: 100%; height: auto"}},
+		{Name: "ins", Children: []RichTextNode{{Type: "text", Text: "x"}}},
+	}}}
+	if !reflect.DeepEqual(nodes, want) {
+		t.Errorf("MiniProgramRichText() = %+v", nodes)
+	}
+}
@@ -0,0 +1,129 @@
+package internal
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SensitiveHit 敏感词命中，Start/End 为字符（rune）下标，区间左闭右开
+type SensitiveHit struct {
+	Word  string
+	Start int
+	End   int
+}
+
+// SensitiveMatcher 基于 Aho-Corasick 自动机的多模式匹配器，一次扫描找出全部敏感词，忽略大小写
+type SensitiveMatcher struct {
+	words []string
+	nodes []acNode
+}
+
+type acNode struct {
+	next map[rune]int
+	fail int
+	out  []int // 以该节点结尾的敏感词（含后缀链上的）
+}
+
+// NewSensitiveMatcher 根据词典构建匹配器，空白词和重复词忽略
+func NewSensitiveMatcher(words []string) *SensitiveMatcher {
+	m := &SensitiveMatcher{nodes: []acNode{{next: map[rune]int{}}}}
+	seen := make(map[string]bool, len(words))
+	for _, word := range words {
+		key := strings.Map(unicode.ToLower, strings.TrimSpace(word))
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		state := 0
+		for _, r := range key {
+			next, ok := m.nodes[state].next[r]
+			if !ok {
+				next = len(m.nodes)
+				m.nodes = append(m.nodes, acNode{next: map[rune]int{}})
+				m.nodes[state].next[r] = next
+			}
+			state = next
+		}
+		m.nodes[state].out = append(m.nodes[state].out, len(m.words))
+		m.words = append(m.words, key)
+	}
+
+	// 按层构建失败指针，浅层节点先于深层节点处理
+	queue := make([]int, 0, len(m.nodes))
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for r, child := range m.nodes[state].next {
+			fail := m.nodes[state].fail
+			for fail > 0 {
+				if _, ok := m.nodes[fail].next[r]; ok {
+					break
+				}
+				fail = m.nodes[fail].fail
+			}
+			if next, ok := m.nodes[fail].next[r]; ok && next != child {
+				m.nodes[child].fail = next
+			}
+			m.nodes[child].out = append(m.nodes[child].out, m.nodes[m.nodes[child].fail].out...)
+			queue = append(queue, child)
+		}
+	}
+	return m
+}
+
+// Match 找出文本中的全部敏感词（可重叠），按结束位置排序
+func (m *SensitiveMatcher) Match(text string) []SensitiveHit {
+	var hits []SensitiveHit
+	if m == nil || len(m.words) == 0 {
+		return hits
+	}
+	state := 0
+	for i, r := range []rune(text) {
+		r = unicode.ToLower(r)
+		for state > 0 {
+			if _, ok := m.nodes[state].next[r]; ok {
+				break
+			}
+			state = m.nodes[state].fail
+		}
+		state = m.nodes[state].next[r] // 不存在时为 0，回到根节点
+		for _, idx := range m.nodes[state].out {
+			word := m.words[idx]
+			end := i + 1
+			hits = append(hits, SensitiveHit{Word: word, Start: end - utf8.RuneCountInString(word), End: end})
+		}
+	}
+	return hits
+}
+
+// MaskSensitive 将命中的敏感词替换为 *
+func MaskSensitive(text string, hits []SensitiveHit) string {
+	if len(hits) == 0 {
+		return text
+	}
+	runes := []rune(text)
+	for _, hit := range hits {
+		for i := hit.Start; i < hit.End && i < len(runes); i++ {
+			runes[i] = '*'
+		}
+	}
+	return string(runes)
+}
+
+// HitWords 命中的敏感词（去重，保持首次出现顺序）
+func HitWords(hits []SensitiveHit) []string {
+	words := make([]string, 0, len(hits))
+	seen := make(map[string]bool, len(hits))
+	for _, hit := range hits {
+		if !seen[hit.Word] {
+			seen[hit.Word] = true
+			words = append(words, hit.Word)
+		}
+	}
+	return words
+}
@@ -0,0 +1,55 @@
+package internal
+
+import "testing"
+
+func TestSensitiveMatcher(t *testing.T) {
+	tests := []struct {
+		name  string
+		words []string
+		text  string
+		want  []SensitiveHit
+	}{
+		{"empty dictionary", nil, "anything", nil},
+		{"overlapping words", []string{"he", "she", "his", "hers"}, "ushers", []SensitiveHit{
+			{"she", 1, 4}, {"he", 2, 4}, {"hers", 2, 6},
+		}},
+		{"chinese", []string{"假货", "骗子"}, "这是假货，卖家是骗子", []SensitiveHit{
+			{"假货", 2, 4}, {"骗子", 8, 10},
+		}},
+		{"ignore case", []string{"Fake"}, "so FAKE", []SensitiveHit{{"fake", 3, 7}}},
+		{"repeated", []string{"aa"}, "aaa", []SensitiveHit{{"aa", 0, 2}, {"aa", 1, 3}}},
+		{"fail link", []string{"abcd", "bc"}, "abcx", []SensitiveHit{{"bc", 1, 3}}},
+		{"blank and duplicate words", []string{" ", "ab", "AB"}, "ab", []SensitiveHit{{"ab", 0, 2}}},
+		{"no hit", []string{"bad"}, "good", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewSensitiveMatcher(tt.words).Match(tt.text)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Match() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Match()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMaskSensitive(t *testing.T) {
+	m := NewSensitiveMatcher([]string{"假货", "sb"})
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"质量很好", "质量很好"},
+		{"绝对是假货", "绝对是**"},
+		{"SB卖家，假货假货", "**卖家，****"},
+	}
+	for _, tt := range tests {
+		if got := MaskSensitive(tt.text, m.Match(tt.text)); got != tt.want {
+			t.Errorf("MaskSensitive(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package internal
+
+import "math"
+
+// ItemSimilarity 按共现计算商品两两相似度（余弦）：groups 为订单或浏览会话中的商品集合，
+// 相似度 = 共同出现的集合数 / sqrt(a 出现的集合数 * b 出现的集合数)，返回 a -> b -> 相似度
+func ItemSimilarity(groups [][]string) map[string]map[string]float64 {
+	occurrences := make(map[string]int)
+	pairs := make(map[string]map[string]int)
+	for _, group := range groups {
+		seen := make(map[string]bool, len(group))
+		items := make([]string, 0, len(group))
+		for _, id := range group {
+			if id != "" && !seen[id] {
+				seen[id] = true
+				items = append(items, id)
+			}
+		}
+		for _, a := range items {
+			occurrences[a]++
+			for _, b := range items {
+				if a == b {
+					continue
+				}
+				if pairs[a] == nil {
+					pairs[a] = make(map[string]int)
+				}
+				pairs[a][b]++
+			}
+		}
+	}
+
+	similarity := make(map[string]map[string]float64, len(pairs))
+	for a, neighbors := range pairs {
+		similarity[a] = make(map[string]float64, len(neighbors))
+		for b, count := range neighbors {
+			similarity[a][b] = float64(count) / math.Sqrt(float64(occurrences[a]*occurrences[b]))
+		}
+	}
+	return similarity
+}
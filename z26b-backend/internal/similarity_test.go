@@ -0,0 +1,32 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestItemSimilarity(t *testing.T) {
+	sim := ItemSimilarity([][]string{
+		{"a", "b"},
+		{"a", "b", "b"},
+		{"a", "c"},
+		{"d"},
+	})
+	approx := func(got, want float64) bool { return math.Abs(got-want) < 1e-9 }
+
+	if got := sim["a"]["b"]; !approx(got, 2/math.Sqrt(3*2)) {
+		t.Errorf("sim[a][b] = %v", got)
+	}
+	if sim["a"]["b"] != sim["b"]["a"] {
+		t.Errorf("similarity should be symmetric")
+	}
+	if got := sim["a"]["c"]; !approx(got, 1/math.Sqrt(3)) {
+		t.Errorf("sim[a][c] = %v", got)
+	}
+	if _, ok := sim["b"]["c"]; ok {
+		t.Errorf("b and c never co-occur")
+	}
+	if _, ok := sim["d"]; ok {
+		t.Errorf("single-item groups should produce no pairs")
+	}
+}
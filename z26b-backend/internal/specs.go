@@ -0,0 +1,108 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gorm.io/datatypes"
+)
+
+// specKeySeparator 规格组合键分隔符，规格值中不允许出现
+const specKeySeparator = ";"
+
+// SpecKey 生成规格组合键，如 红;S
+func SpecKey(values []string) string {
+	return strings.Join(values, specKeySeparator)
+}
+
+// ParseSpecs 解析 SPU 规格维度，数据为空或格式错误时返回空
+func ParseSpecs(data datatypes.JSON) []SpecDimension {
+	var specs []SpecDimension
+	if len(data) > 0 {
+		_ = json.Unmarshal(data, &specs)
+	}
+	return specs
+}
+
+// ParseSpecValues 解析 SKU 规格值组合
+func ParseSpecValues(data datatypes.JSON) []string {
+	var values []string
+	if len(data) > 0 {
+		_ = json.Unmarshal(data, &values)
+	}
+	return values
+}
+
+// ValidateSpecs 校验规格维度：名称不为空且不重复，每个维度至少一个不重复的规格值
+func ValidateSpecs(specs []SpecDimension) error {
+	names := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		name := strings.TrimSpace(spec.Name)
+		if name == "" {
+			return fmt.Errorf("规格名称不能为空")
+		}
+		if names[name] {
+			return fmt.Errorf("规格名称重复: %s", name)
+		}
+		names[name] = true
+
+		if len(spec.Values) == 0 {
+			return fmt.Errorf("规格 %s 至少需要一个规格值", name)
+		}
+		values := make(map[string]bool, len(spec.Values))
+		for _, v := range spec.Values {
+			if strings.TrimSpace(v) == "" {
+				return fmt.Errorf("规格 %s 的规格值不能为空", name)
+			}
+			if strings.Contains(v, specKeySeparator) {
+				return fmt.Errorf("规格值不能包含 %s", specKeySeparator)
+			}
+			if values[v] {
+				return fmt.Errorf("规格 %s 的规格值重复: %s", name, v)
+			}
+			values[v] = true
+		}
+	}
+	return nil
+}
+
+// ValidateSpecValues 校验规格值组合与规格维度一一对应
+func ValidateSpecValues(specs []SpecDimension, values []string) error {
+	if len(values) != len(specs) {
+		return fmt.Errorf("规格值数量与规格维度不一致")
+	}
+	for i, spec := range specs {
+		found := false
+		for _, v := range spec.Values {
+			if v == values[i] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("规格 %s 不包含规格值 %s", spec.Name, values[i])
+		}
+	}
+	return nil
+}
+
+// SpecCombinations 生成规格维度的全部组合（笛卡尔积），按维度顺序排列
+func SpecCombinations(specs []SpecDimension) [][]string {
+	if len(specs) == 0 {
+		return nil
+	}
+	combos := [][]string{{}}
+	for _, spec := range specs {
+		next := make([][]string, 0, len(combos)*len(spec.Values))
+		for _, combo := range combos {
+			for _, v := range spec.Values {
+				c := make([]string, len(combo), len(combo)+1)
+				copy(c, combo)
+				next = append(next, append(c, v))
+			}
+		}
+		combos = next
+	}
+	return combos
+}
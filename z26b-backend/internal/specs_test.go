@@ -0,0 +1,31 @@
+package internal
+
+import "testing"
+
+func TestSpecCombinations(t *testing.T) {
+	specs := []SpecDimension{
+		{Name: "颜色", Values: []string{"红", "蓝"}},
+		{Name: "尺码", Values: []string{"S", "M", "L"}},
+	}
+	if err := ValidateSpecs(specs); err != nil {
+		t.Fatalf("ValidateSpecs() error = %v", err)
+	}
+
+	combos := SpecCombinations(specs)
+	if len(combos) != 6 {
+		t.Fatalf("SpecCombinations() = %d combos, want 6", len(combos))
+	}
+	if got := SpecKey(combos[0]); got != "红;S" {
+		t.Errorf("first combo = %q, want %q", got, "红;S")
+	}
+	if got := SpecKey(combos[5]); got != "蓝;L" {
+		t.Errorf("last combo = %q, want %q", got, "蓝;L")
+	}
+
+	if err := ValidateSpecValues(specs, []string{"红", "XL"}); err == nil {
+		t.Error("ValidateSpecValues() accepted unknown value")
+	}
+	if err := ValidateSpecs([]SpecDimension{{Name: "颜色", Values: []string{"红", "红"}}}); err == nil {
+		t.Error("ValidateSpecs() accepted duplicate values")
+	}
+}
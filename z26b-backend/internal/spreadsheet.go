@@ -0,0 +1,291 @@
+package internal
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ============================================
+// 表格读写（CSV / XLSX）
+// ============================================
+
+// utf8BOM Excel 打开 UTF-8 CSV 时依赖 BOM 识别编码
+const utf8BOM = "\ufeff"
+
+// 读取 XLSX 的上限，防止恶意文件在解析时占用过多内存
+const (
+	MaxSpreadsheetRows    = 10001    // 行数上限（含表头）
+	maxSpreadsheetColumns = 256      // 列数上限
+	maxXLSXPartSize       = 64 << 20 // 单个 XML 部件解压后的大小上限
+)
+
+// ReadCSV 读取 CSV，自动去除 UTF-8 BOM，允许各行列数不一致
+func ReadCSV(r io.Reader) ([][]string, error) {
+	br := bufio.NewReader(r)
+	if b, err := br.Peek(len(utf8BOM)); err == nil && string(b) == utf8BOM {
+		br.Discard(len(utf8BOM))
+	}
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+	return reader.ReadAll()
+}
+
+// WriteCSV 写出带 UTF-8 BOM 的 CSV
+func WriteCSV(w io.Writer, rows [][]string) error {
+	if _, err := io.WriteString(w, utf8BOM); err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// xlsxRels 关系文件
+type xlsxRels struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxText 文本节点，富文本由多个 r/t 组成
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	for _, r := range t.Runs {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+// ReadXLSX 读取 XLSX 第一个工作表的全部单元格文本
+func ReadXLSX(r io.ReaderAt, size int64) ([][]string, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("无效的 XLSX 文件: %w", err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	decode := func(name string, v interface{}) error {
+		f, ok := files[name]
+		if !ok {
+			return fmt.Errorf("XLSX 缺少 %s", name)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		lr := &io.LimitedReader{R: rc, N: maxXLSXPartSize}
+		if err := xml.NewDecoder(lr).Decode(v); err != nil {
+			if lr.N <= 0 {
+				return fmt.Errorf("XLSX %s 过大", name)
+			}
+			return err
+		}
+		return nil
+	}
+
+	// 定位第一个工作表
+	var workbook struct {
+		Sheets []struct {
+			RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decode("xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+	if len(workbook.Sheets) == 0 {
+		return nil, errors.New("XLSX 没有工作表")
+	}
+	var rels xlsxRels
+	if err := decode("xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	sheetPath := ""
+	for _, rel := range rels.Relationships {
+		if rel.ID == workbook.Sheets[0].RID {
+			if strings.HasPrefix(rel.Target, "/") {
+				sheetPath = strings.TrimPrefix(rel.Target, "/")
+			} else {
+				sheetPath = path.Join("xl", rel.Target)
+			}
+		}
+	}
+	if sheetPath == "" {
+		return nil, errors.New("XLSX 工作表关系缺失")
+	}
+
+	// 共享字符串
+	var sst struct {
+		Items []xlsxText `xml:"si"`
+	}
+	if _, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decode("xl/sharedStrings.xml", &sst); err != nil {
+			return nil, err
+		}
+	}
+
+	var sheet struct {
+		Rows []struct {
+			R     int `xml:"r,attr"`
+			Cells []struct {
+				R  string   `xml:"r,attr"`
+				T  string   `xml:"t,attr"`
+				V  string   `xml:"v"`
+				IS xlsxText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := decode(sheetPath, &sheet); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for i, row := range sheet.Rows {
+		rowNum := row.R
+		if rowNum == 0 {
+			rowNum = i + 1
+		}
+		if rowNum < 0 {
+			return nil, fmt.Errorf("XLSX 行号 %d 无效", row.R)
+		}
+		if rowNum > MaxSpreadsheetRows {
+			return nil, fmt.Errorf("XLSX 行数超过 %d 行", MaxSpreadsheetRows)
+		}
+		for len(rows) < rowNum {
+			rows = append(rows, nil)
+		}
+		var cells []string
+		for j, cell := range row.Cells {
+			col := j
+			if cell.R != "" {
+				c, ok := xlsxColumnIndex(cell.R)
+				if !ok {
+					return nil, fmt.Errorf("XLSX 单元格引用 %s 无效", cell.R)
+				}
+				col = c
+			}
+			if col >= maxSpreadsheetColumns {
+				return nil, fmt.Errorf("XLSX 列数超过 %d 列", maxSpreadsheetColumns)
+			}
+			value := cell.V
+			switch cell.T {
+			case "s":
+				idx, err := strconv.Atoi(cell.V)
+				if err != nil || idx < 0 || idx >= len(sst.Items) {
+					return nil, fmt.Errorf("XLSX 单元格 %s 共享字符串索引无效", cell.R)
+				}
+				value = sst.Items[idx].String()
+			case "inlineStr":
+				value = cell.IS.String()
+			}
+			for len(cells) <= col {
+				cells = append(cells, "")
+			}
+			cells[col] = value
+		}
+		rows[rowNum-1] = cells
+	}
+	return rows, nil
+}
+
+// xlsxColumnIndex 由单元格引用（如 AB12）得到从 0 开始的列号，列名最多 3 个字母（XFD）
+func xlsxColumnIndex(ref string) (int, bool) {
+	col := 0
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+		n++
+	}
+	return col - 1, n > 0 && n <= 3
+}
+
+// xlsxColumnName 由从 0 开始的列号得到列名（如 0 → A，27 → AB）
+func xlsxColumnName(col int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return name
+}
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+
+const xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+
+// WriteXLSX 写出只有一个工作表的 XLSX，所有单元格按文本写入
+func WriteXLSX(w io.Writer, sheetName string, rows [][]string) error {
+	var name bytes.Buffer
+	xml.EscapeText(&name, []byte(sheetName))
+	workbook := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="` + name.String() + `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+	var sheet bytes.Buffer
+	sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for i, row := range rows {
+		fmt.Fprintf(&sheet, `<row r="%d">`, i+1)
+		for j, value := range row {
+			if value == "" {
+				continue
+			}
+			fmt.Fprintf(&sheet, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">`, xlsxColumnName(j), i+1)
+			xml.EscapeText(&sheet, []byte(value))
+			sheet.WriteString(`</t></is></c>`)
+		}
+		sheet.WriteString(`</row>`)
+	}
+	sheet.WriteString(`</sheetData></worksheet>`)
+
+	zw := zip.NewWriter(w)
+	for _, part := range []struct {
+		name    string
+		content []byte
+	}{
+		{"[Content_Types].xml", []byte(xlsxContentTypes)},
+		{"_rels/.rels", []byte(xlsxRootRels)},
+		{"xl/workbook.xml", []byte(workbook)},
+		{"xl/_rels/workbook.xml.rels", []byte(xlsxWorkbookRels)},
+		{"xl/worksheets/sheet1.xml", sheet.Bytes()},
+	} {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(part.content); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestSpreadsheetRoundTrip(t *testing.T) {
+	rows := [][]string{
+		{"spu_code", "name", "detail"},
+		{"P001", "连衣裙 <夏季> & 新品", ""},
+		{"P002", "", "line1\nline2"},
+	}
+	for i := 0; i < 30; i++ {
+		rows[0] = append(rows[0], "c")
+	}
+
+	var buf bytes.Buffer
+	if err := WriteXLSX(&buf, "商品", rows); err != nil {
+		t.Fatalf("WriteXLSX() error = %v", err)
+	}
+	got, err := ReadXLSX(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("ReadXLSX() error = %v", err)
+	}
+	if len(got) != 3 || len(got[0]) != 33 || got[1][1] != rows[1][1] || got[2][2] != rows[2][2] || got[2][1] != "" {
+		t.Errorf("ReadXLSX() = %q, want %q", got, rows)
+	}
+
+	buf.Reset()
+	if err := WriteCSV(&buf, rows); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	got, err = ReadCSV(&buf)
+	if err != nil {
+		t.Fatalf("ReadCSV() error = %v", err)
+	}
+	if got[0][0] != "spu_code" || got[2][2] != rows[2][2] {
+		t.Errorf("ReadCSV() = %q, want %q", got, rows)
+	}
+}
+
+// testXLSX 构造工作表内容为 sheetData 的 XLSX 文件
+func testXLSX(t *testing.T, sheetData string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"[Content_Types].xml":        xlsxContentTypes,
+		"_rels/.rels":                xlsxRootRels,
+		"xl/_rels/workbook.xml.rels": xlsxWorkbookRels,
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="s" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+			sheetData + `</sheetData></worksheet>`,
+	} {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadXLSXMalformed(t *testing.T) {
+	cell := `<c r="%s" t="inlineStr"><is><t>x</t></is></c>`
+	tests := []struct {
+		name    string
+		sheet   string
+		wantErr bool
+	}{
+		{"valid", `<row r="2">` + fmt.Sprintf(cell, "B2") + `</row>`, false},
+		{"row without r", `<row>` + fmt.Sprintf(cell, "A1") + `</row>`, false},
+		{"negative row", `<row r="-1">` + fmt.Sprintf(cell, "A1") + `</row>`, true},
+		{"row past limit", `<row r="99999999">` + fmt.Sprintf(cell, "A1") + `</row>`, true},
+		{"column past limit", `<row r="1">` + fmt.Sprintf(cell, "XFD1") + `</row>`, true},
+		{"column ref too long", `<row r="1">` + fmt.Sprintf(cell, "ZZZZZZZ1") + `</row>`, true},
+		{"column ref without letters", `<row r="1">` + fmt.Sprintf(cell, "1") + `</row>`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := testXLSX(t, tt.sheet)
+			rows, err := ReadXLSX(bytes.NewReader(data), int64(len(data)))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadXLSX() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && len(rows) == 0 {
+				t.Errorf("ReadXLSX() returned no rows")
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package internal
+
+import "gorm.io/gorm"
+
+// MatchTags 判断商品标签是否满足筛选：matchAll 为 true 时需包含全部 want，否则命中任一即可；want 为空时总是满足
+func MatchTags(tagIDs, want []string, matchAll bool) bool {
+	if len(want) == 0 {
+		return true
+	}
+	have := make(map[string]bool, len(tagIDs))
+	for _, id := range tagIDs {
+		have[id] = true
+	}
+	for _, id := range want {
+		if have[id] && !matchAll {
+			return true
+		}
+		if !have[id] && matchAll {
+			return false
+		}
+	}
+	return matchAll
+}
+
+// SPUWithTags 按标签筛选商品，规则与 MatchTags 一致
+func SPUWithTags(tagIDs []string, matchAll bool) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(tagIDs) == 0 {
+			return db
+		}
+		if !matchAll {
+			return db.Where("spu.id IN (SELECT spu_id FROM spu_tag WHERE tag_id IN ?)", tagIDs)
+		}
+		unique := make(map[string]bool, len(tagIDs))
+		for _, id := range tagIDs {
+			unique[id] = true
+		}
+		return db.Where("spu.id IN (SELECT spu_id FROM spu_tag WHERE tag_id IN ? GROUP BY spu_id HAVING COUNT(DISTINCT tag_id) = ?)",
+			tagIDs, len(unique))
+	}
+}
+
+// LoadSPUTags 一次查询为商品列表填充启用中的标签，按标签排序值排列
+func LoadSPUTags(db *gorm.DB, spus []SPU) error {
+	if len(spus) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(spus))
+	for _, spu := range spus {
+		ids = append(ids, spu.ID)
+	}
+
+	var rows []struct {
+		SPUID string `gorm:"column:spu_id"`
+		Tag
+	}
+	err := db.Table("spu_tag").
+		Select("spu_tag.spu_id, tag.*").
+		Joins("JOIN tag ON tag.id = spu_tag.tag_id AND tag.deleted_at IS NULL").
+		Where("spu_tag.spu_id IN ? AND tag.status = ?", ids, "active").
+		Order("tag.sort_order ASC, tag.name ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	tagsBySPU := make(map[string][]Tag, len(spus))
+	for _, row := range rows {
+		tagsBySPU[row.SPUID] = append(tagsBySPU[row.SPUID], row.Tag)
+	}
+	for i := range spus {
+		spus[i].Tags = tagsBySPU[spus[i].ID]
+		if spus[i].Tags == nil {
+			spus[i].Tags = []Tag{}
+		}
+	}
+	return nil
+}
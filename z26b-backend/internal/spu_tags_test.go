@@ -0,0 +1,28 @@
+package internal
+
+import "testing"
+
+func TestMatchTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		tagIDs   []string
+		want     []string
+		matchAll bool
+		expected bool
+	}{
+		{"no filter", []string{"a"}, nil, true, true},
+		{"any hit", []string{"a", "b"}, []string{"b", "c"}, false, true},
+		{"any miss", []string{"a"}, []string{"b", "c"}, false, false},
+		{"all hit", []string{"a", "b", "c"}, []string{"a", "c"}, true, true},
+		{"all partial", []string{"a", "b"}, []string{"a", "c"}, true, false},
+		{"all duplicate filter", []string{"a"}, []string{"a", "a"}, true, true},
+		{"untagged product", nil, []string{"a"}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchTags(tt.tagIDs, tt.want, tt.matchAll); got != tt.expected {
+				t.Errorf("MatchTags() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,6 @@
+package internal
+
+// CrossedLowStock 库存是否由高于预警值降到预警值及以下，threshold 为 0 表示不预警
+func CrossedLowStock(before, after, threshold int) bool {
+	return threshold > 0 && before > threshold && after <= threshold
+}
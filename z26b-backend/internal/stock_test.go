@@ -0,0 +1,27 @@
+package internal
+
+import "testing"
+
+func TestCrossedLowStock(t *testing.T) {
+	tests := []struct {
+		name      string
+		before    int
+		after     int
+		threshold int
+		want      bool
+	}{
+		{"drops to threshold", 11, 10, 10, true},
+		{"drops below threshold", 20, 3, 10, true},
+		{"already low", 8, 5, 10, false},
+		{"still above", 30, 11, 10, false},
+		{"restock", 5, 20, 10, false},
+		{"alerts disabled", 5, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CrossedLowStock(tt.before, tt.after, tt.threshold); got != tt.want {
+				t.Errorf("CrossedLowStock() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/datatypes"
+	"gorm.io/gorm"
 )
 
 // ============================================
@@ -149,6 +150,20 @@ func GenerateUUID() string {
 	return uuid.New().String()
 }
 
+// referralCodeAlphabet 推荐码字符集（去掉易混淆的 0/O/1/I）
+const referralCodeAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+// GenerateReferralCode 生成8位推荐码，返回指针以便直接赋值给 User.ReferralCode
+func GenerateReferralCode() *string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	for i := range b {
+		b[i] = referralCodeAlphabet[int(b[i])%len(referralCodeAlphabet)]
+	}
+	code := string(b)
+	return &code
+}
+
 func HashString(s string) string {
 	hashedBytes, _ := bcrypt.GenerateFromPassword([]byte(s), bcrypt.DefaultCost)
 	return string(hashedBytes)
@@ -328,21 +343,28 @@ func SanitizeString(input string) string {
 	return input
 }
 
+// ============================================
+// 回收站（软删除）
+// ============================================
+
+// WithDeleted 预加载时包含已删除的记录，用于历史订单关联已删除的 SKU/商品
+func WithDeleted(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
 // ============================================
 // SPU 价格同步
 // ============================================
 
-// UpdateSPUPriceRange 根据 SKU 更新 SPU 的价格范围
-func UpdateSPUPriceRange(db interface {
-	Exec(sql string, values ...interface{}) interface{ Error() error }
-}, spuID string) error {
-	sql := `
-		UPDATE spu SET 
-			min_price = COALESCE((SELECT MIN(price) FROM sku WHERE "SPUID" = $1), 0),
-			max_price = COALESCE((SELECT MAX(price) FROM sku WHERE "SPUID" = $1), 0)
-		WHERE id = $1
-	`
-	if err := db.Exec(sql, spuID).Error(); err != nil {
+// UpdateSPUPriceRange 根据未删除的 SKU 更新 SPU 的价格范围
+func UpdateSPUPriceRange(db *gorm.DB, spuID string) error {
+	err := db.Exec(`
+		UPDATE spu SET
+			min_price = COALESCE((SELECT MIN(price) FROM sku WHERE "SPUID" = ? AND deleted_at IS NULL), 0),
+			max_price = COALESCE((SELECT MAX(price) FROM sku WHERE "SPUID" = ? AND deleted_at IS NULL), 0)
+		WHERE id = ?
+	`, spuID, spuID, spuID).Error
+	if err != nil {
 		return fmt.Errorf("failed to update SPU price range: %w", err)
 	}
 	return nil
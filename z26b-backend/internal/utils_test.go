@@ -68,4 +68,4 @@ func TestSanitizeString(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// StockAllocation 订单明细的仓库分配结果，一个明细可能拆分到多个仓库
+type StockAllocation struct {
+	Line        int // 明细下标
+	WarehouseID string
+	Quantity    int
+}
+
+// RankWarehouses 按收货省份排序仓库：优先发货地区包含该省份的仓库在前，其余按优先级从高到低
+func RankWarehouses(warehouses []Warehouse, provinceName, provinceCode string) []string {
+	serves := func(w Warehouse) bool {
+		var regions []string
+		_ = json.Unmarshal(w.Regions, &regions)
+		for _, r := range regions {
+			if r == "" {
+				continue
+			}
+			if r == provinceCode || r == provinceName || strings.HasPrefix(provinceName, r) {
+				return true
+			}
+		}
+		return false
+	}
+
+	ranked := make([]Warehouse, len(warehouses))
+	copy(ranked, warehouses)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		si, sj := serves(ranked[i]), serves(ranked[j])
+		if si != sj {
+			return si
+		}
+		return ranked[i].Priority > ranked[j].Priority
+	})
+
+	ids := make([]string, len(ranked))
+	for i, w := range ranked {
+		ids[i] = w.ID
+	}
+	return ids
+}
+
+// AllocateStock 按仓库顺序为订单明细分配库存：优先由一个仓库整单发货；否则逐行分配到第一个能满足的仓库，
+// 没有单个仓库能满足时按顺序拆分到多个仓库。stock 为各仓库的 SKU 库存，库存不足时返回 false
+func AllocateStock(skuIDs []string, quantities []int, warehouses []string, stock map[string]map[string]int) ([]StockAllocation, bool) {
+	need := make(map[string]int)
+	for i, id := range skuIDs {
+		need[id] += quantities[i]
+	}
+	for _, w := range warehouses {
+		fits := true
+		for id, q := range need {
+			if stock[w][id] < q {
+				fits = false
+				break
+			}
+		}
+		if fits {
+			allocations := make([]StockAllocation, 0, len(skuIDs))
+			for i := range skuIDs {
+				allocations = append(allocations, StockAllocation{Line: i, WarehouseID: w, Quantity: quantities[i]})
+			}
+			return allocations, true
+		}
+	}
+
+	remaining := make(map[string]map[string]int, len(warehouses))
+	for _, w := range warehouses {
+		remaining[w] = make(map[string]int, len(stock[w]))
+		for id, count := range stock[w] {
+			remaining[w][id] = count
+		}
+	}
+
+	var allocations []StockAllocation
+	for i, id := range skuIDs {
+		q := quantities[i]
+		placed := false
+		for _, w := range warehouses {
+			if remaining[w][id] >= q {
+				remaining[w][id] -= q
+				allocations = append(allocations, StockAllocation{Line: i, WarehouseID: w, Quantity: q})
+				placed = true
+				break
+			}
+		}
+		if placed {
+			continue
+		}
+
+		// 拆分到多个仓库
+		for _, w := range warehouses {
+			take := min(q, remaining[w][id])
+			if take <= 0 {
+				continue
+			}
+			remaining[w][id] -= take
+			q -= take
+			allocations = append(allocations, StockAllocation{Line: i, WarehouseID: w, Quantity: take})
+			if q == 0 {
+				break
+			}
+		}
+		if q > 0 {
+			return nil, false
+		}
+	}
+	return allocations, true
+}
@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+
+	"gorm.io/datatypes"
+)
+
+func TestRankWarehouses(t *testing.T) {
+	warehouses := []Warehouse{
+		{ID: "north", Priority: 1, Regions: datatypes.JSON(`["北京","110000"]`)},
+		{ID: "south", Priority: 2, Regions: datatypes.JSON(`["广东","广西"]`)},
+		{ID: "west", Priority: 0},
+	}
+	tests := []struct {
+		name     string
+		province string
+		code     string
+		want     []string
+	}{
+		{"province name prefix", "广东省", "440000", []string{"south", "north", "west"}},
+		{"province code", "", "110000", []string{"north", "south", "west"}},
+		{"no match uses priority", "四川省", "510000", []string{"south", "north", "west"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RankWarehouses(warehouses, tt.province, tt.code); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RankWarehouses() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllocateStock(t *testing.T) {
+	stock := map[string]map[string]int{
+		"a": {"sku1": 5, "sku2": 1},
+		"b": {"sku1": 3, "sku2": 10},
+	}
+	tests := []struct {
+		name       string
+		skuIDs     []string
+		quantities []int
+		warehouses []string
+		want       []StockAllocation
+		ok         bool
+	}{
+		{"single warehouse fills order", []string{"sku1", "sku2"}, []int{2, 1}, []string{"a", "b"},
+			[]StockAllocation{{0, "a", 2}, {1, "a", 1}}, true},
+		{"falls back to second warehouse", []string{"sku1", "sku2"}, []int{3, 5}, []string{"a", "b"},
+			[]StockAllocation{{0, "b", 3}, {1, "b", 5}}, true},
+		{"lines go to different warehouses", []string{"sku1", "sku2"}, []int{5, 5}, []string{"a", "b"},
+			[]StockAllocation{{0, "a", 5}, {1, "b", 5}}, true},
+		{"line split across warehouses", []string{"sku1"}, []int{7}, []string{"a", "b"},
+			[]StockAllocation{{0, "a", 5}, {0, "b", 2}}, true},
+		{"same sku on two lines", []string{"sku1", "sku1"}, []int{4, 3}, []string{"a", "b"},
+			[]StockAllocation{{0, "a", 4}, {1, "b", 3}}, true},
+		{"insufficient stock", []string{"sku1"}, []int{9}, []string{"a", "b"}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := AllocateStock(tt.skuIDs, tt.quantities, tt.warehouses, stock)
+			if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AllocateStock() = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"time"
 
 	"z26b-backend/handlers"
@@ -13,7 +14,15 @@ import (
 	"z26b-backend/middleware"
 	admin_services "z26b-backend/services/admin_services"
 	"z26b-backend/services/crm"
+	"z26b-backend/services/distribution"
+	"z26b-backend/services/history"
+	"z26b-backend/services/inventory"
 	miniprogram_services "z26b-backend/services/miniprogram"
+	"z26b-backend/services/moderation"
+	"z26b-backend/services/recommend"
+	"z26b-backend/services/schedule"
+	"z26b-backend/services/search"
+	"z26b-backend/services/wallet"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -59,24 +68,68 @@ func main() {
 	router.Use(middleware.RequestTimeoutMiddleware(30 * time.Second))
 
 	// Initialize services
+	walletService := wallet.NewWalletService(db)
+	inventoryService := inventory.NewInventoryService(db)
+	moderationService := moderation.NewModerationService(db)
 	goodsService := miniprogram_services.NewGoodsService(db)
 	adminGoodsService := admin_services.NewAdminGoodsService(db)
 	userService := miniprogram_services.NewUserService(db)
 	addressService := miniprogram_services.NewAddressService(db)
 	cartService := miniprogram_services.NewCartService(db)
-	orderService := miniprogram_services.NewOrderService(db)
-	commentService := miniprogram_services.NewCommentService(db)
+	commissionService := distribution.NewCommissionService(db)
+	orderService := miniprogram_services.NewOrderService(db, walletService, inventoryService, commissionService)
+	commentService := miniprogram_services.NewCommentService(db, moderationService)
+	bundleService := miniprogram_services.NewBundleService(db)
 	wechatService := miniprogram_services.NewWechatService(db)
+	favoriteService := miniprogram_services.NewFavoriteService(db)
+	browseHistoryService := miniprogram_services.NewBrowseHistoryService(db)
+	questionService := miniprogram_services.NewQuestionService(db)
 	adminCategoryService := admin_services.NewAdminCategoryService(db)
+	adminBundleService := admin_services.NewAdminBundleService(db)
+	adminCatalogService := admin_services.NewAdminCatalogService(db)
+
+	// 回收站保留天数，默认 30 天
+	recycleBinRetention := admin_services.DefaultRecycleBinRetention
+	if days, err := strconv.Atoi(os.Getenv("RECYCLE_BIN_RETENTION_DAYS")); err == nil && days > 0 {
+		recycleBinRetention = time.Duration(days) * 24 * time.Hour
+	}
+	adminRecycleBinService := admin_services.NewAdminRecycleBinService(db, recycleBinRetention)
+	adminWarehouseService := admin_services.NewAdminWarehouseService(db)
+	adminQuestionService := admin_services.NewAdminQuestionService(db)
+	adminCommentService := admin_services.NewAdminCommentService(db)
 
 	// Initialize CRM services
 	crmEventService := crm.NewCRMEventService(db)
 	customerStatsService := crm.NewCustomerStatsService(db)
 	productStatsService := crm.NewProductStatsService(db)
 
+	// Initialize search service
+	searchService := search.NewSearchService(db)
+	if err := searchService.Init(); err != nil {
+		internal.GlobalLogger.Warn("Failed to build search index", map[string]interface{}{"error": err.Error()})
+	}
+
+	// Initialize product history service
+	productHistoryService := history.NewProductHistoryService(db)
+
+	// Initialize recommend service
+	recommendService := recommend.NewRecommendService(db)
+
+	// 商品定时上下架任务
+	productScheduler := schedule.NewProductScheduler(db, searchService)
+	go productScheduler.Start(time.Minute)
+
+	// 回收站到期清理任务
+	recycleBinPurger := schedule.NewRecycleBinPurger(adminRecycleBinService)
+	go recycleBinPurger.Start(time.Hour)
+
+	// 商品相似度计算任务
+	similarityJob := schedule.NewSimilarityJob(recommendService)
+	go similarityJob.Start(6 * time.Hour)
+
 	// Initialize handlers
-	mpHandler := miniprogram.NewHandler(goodsService, userService, cartService, orderService, commentService, wechatService, crmEventService, db)
-	adminHandler := admin.NewHandler(adminGoodsService, adminCategoryService, crmEventService, customerStatsService, productStatsService, db)
+	mpHandler := miniprogram.NewHandler(goodsService, userService, cartService, orderService, commentService, bundleService, wechatService, favoriteService, browseHistoryService, questionService, crmEventService, commissionService, walletService, searchService, recommendService, db)
+	adminHandler := admin.NewHandler(adminGoodsService, adminCategoryService, adminBundleService, adminCatalogService, adminRecycleBinService, adminWarehouseService, adminQuestionService, adminCommentService, crmEventService, customerStatsService, productStatsService, commissionService, walletService, searchService, productHistoryService, inventoryService, moderationService, db)
 	addressHandler := handlers.NewAddressHandler(addressService)
 
 	// ====== 小程序端 API ======
@@ -128,6 +181,15 @@ func initMiniProgramRoutes(router *gin.Engine, h *miniprogram.Handler, addressHa
 	{
 		user.GET("/info", h.GetUserInfo)
 		user.PUT("/info", h.UpdateUserInfo)
+		user.GET("/referral", h.GetReferralInfo)
+		user.GET("/referral/commissions", h.GetReferralCommissions)
+	}
+
+	// Wallet routes
+	wallet := api.Group("/wallet")
+	{
+		wallet.GET("", h.GetWallet)
+		wallet.GET("/transactions", h.GetWalletTransactions)
 	}
 
 	// SKU routes
@@ -142,9 +204,21 @@ func initMiniProgramRoutes(router *gin.Engine, h *miniprogram.Handler, addressHa
 	{
 		goods.GET("/list", h.GetGoodsList)
 		goods.GET("/category/list", h.GetCategories)
+		goods.GET("/category/tree", h.GetCategoryTree)
 		goods.GET("/search", h.SearchGoods)
+		goods.GET("/search/suggest", h.SearchSuggest)
+		goods.GET("/search/hot", h.GetHotKeywords)
 		goods.GET("/:id", h.GetGood)
 		goods.GET("/:id/comments", h.GetGoodsComments)
+		goods.GET("/:id/related", h.GetRelatedGoods)
+		goods.GET("/:id/questions", h.GetGoodsQuestions)
+	}
+
+	// Bundle routes
+	bundle := api.Group("/bundle")
+	{
+		bundle.GET("/list", h.GetBundleList)
+		bundle.GET("/:id", h.GetBundle)
 	}
 
 	// Cart routes
@@ -157,6 +231,32 @@ func initMiniProgramRoutes(router *gin.Engine, h *miniprogram.Handler, addressHa
 		cart.POST("/clear", h.ClearCart)
 	}
 
+	// Favorite routes
+	favorite := api.Group("/favorite")
+	{
+		favorite.GET("/list", h.GetFavorites)
+		favorite.POST("/add", h.AddFavorite)
+		favorite.DELETE("/:spuId", h.RemoveFavorite)
+	}
+
+	// Browse history routes
+	browseHistory := api.Group("/history")
+	{
+		browseHistory.GET("/list", h.GetBrowseHistory)
+		browseHistory.POST("/delete", h.DeleteBrowseHistory)
+		browseHistory.POST("/clear", h.ClearBrowseHistory)
+	}
+
+	// Question routes (问大家)
+	question := api.Group("/question")
+	{
+		question.GET("/mine", h.GetMyQuestions)
+		question.POST("/ask", h.AskQuestion)
+		question.GET("/:id", h.GetQuestion)
+		question.POST("/:id/answer", h.AnswerQuestion)
+		question.POST("/answer/:id/helpful", h.MarkAnswerHelpful)
+	}
+
 	// Address routes
 	address := api.Group("/address")
 	{
@@ -176,14 +276,20 @@ func initMiniProgramRoutes(router *gin.Engine, h *miniprogram.Handler, addressHa
 		order.POST("/create", h.CreateOrder)
 		order.PUT("/cancel/:id", h.CancelOrder)
 		order.POST("/confirm/:id", h.ConfirmReceipt)
-		order.POST("/submit-comment/:id", h.SubmitComment)
+		order.POST("/submit-comment/:id", middleware.MiniProgramAuthMiddleware(), h.SubmitComment)
 	}
 
 	// Comment routes
 	comment := api.Group("/comment")
 	{
 		comment.GET("/list/:spuId", h.GetGoodsComments)
-		comment.POST("/submit", h.SubmitComment)
+		comment.POST("/submit", middleware.MiniProgramAuthMiddleware(), h.SubmitComment)
+	}
+
+	// Upload routes
+	upload := api.Group("/upload", middleware.MiniProgramAuthMiddleware())
+	{
+		upload.POST("/comment-media", h.UploadCommentMedia)
 	}
 
 	// Home routes
@@ -193,6 +299,7 @@ func initMiniProgramRoutes(router *gin.Engine, h *miniprogram.Handler, addressHa
 		home.GET("/content", h.GetHomeContent)
 		home.GET("/categories", h.GetHomeCategories)
 		home.GET("/promotions", h.GetPromotions)
+		home.GET("/recommend", h.GetHomeRecommend)
 	}
 }
 
@@ -224,16 +331,75 @@ func initAdminRoutes(router *gin.Engine, h *admin.Handler) {
 			// Products (SPU)
 			protected.GET("/products", h.AdminGetProducts)
 			protected.POST("/products", h.AdminCreateProduct)
+			protected.POST("/products/import", h.AdminImportProducts)
+			protected.GET("/products/export", h.AdminExportProducts)
 			protected.GET("/products/:id", h.AdminGetProduct)
 			protected.PUT("/products/:id", h.AdminUpdateProduct)
 			protected.DELETE("/products/:id", h.AdminDeleteProduct)
 			protected.PUT("/products/:id/toggle-status", h.AdminToggleProductStatus)
+			protected.PUT("/products/:id/schedule", h.AdminScheduleProduct)
+			protected.GET("/products/:id/history", h.AdminGetProductHistory)
+			protected.GET("/products/:id/history/:version", h.AdminGetProductRevision)
+			protected.POST("/products/:id/history/:version/restore", h.AdminRestoreProductRevision)
+
+			// 回收站
+			protected.GET("/recycle-bin", h.AdminGetRecycleBin)
+			protected.POST("/recycle-bin/:type/:id/restore", h.AdminRestoreRecycleBinItem)
+			protected.DELETE("/recycle-bin/:type/:id", h.AdminPurgeRecycleBinItem)
 			protected.GET("/products/:id/skus", h.AdminGetSKUs)
+			protected.PUT("/products/:id/specs", h.AdminSaveProductSpecs)
+			protected.POST("/products/:id/skus/generate", h.AdminGenerateSKUMatrix)
+
+			// Search
+			protected.POST("/search/rebuild", h.AdminRebuildSearchIndex)
+			protected.GET("/search/report", h.AdminGetSearchReport)
 
 			// SKU
 			protected.POST("/skus", h.AdminCreateSKU)
 			protected.PUT("/skus/:id", h.AdminUpdateSKU)
 			protected.DELETE("/skus/:id", h.AdminDeleteSKU)
+			protected.GET("/skus/:id/price-history", h.AdminGetSKUPriceHistory)
+			protected.GET("/skus/:id/stock-movements", h.AdminGetStockMovements)
+			protected.POST("/skus/:id/stock/adjust", h.AdminAdjustStock)
+			protected.GET("/inventory/low-stock", h.AdminGetLowStock)
+			protected.GET("/inventory/alerts", h.AdminGetStockAlerts)
+			protected.POST("/inventory/alerts/ack", h.AdminAcknowledgeStockAlerts)
+			protected.PUT("/inventory/threshold", h.AdminSetStockThreshold)
+			protected.GET("/warehouses", h.AdminGetWarehouses)
+			protected.POST("/warehouses", h.AdminCreateWarehouse)
+			protected.PUT("/warehouses/:id", h.AdminUpdateWarehouse)
+			protected.DELETE("/warehouses/:id", h.AdminDeleteWarehouse)
+			protected.GET("/warehouses/:id/stock", h.AdminGetWarehouseStock)
+			protected.PUT("/warehouses/:id/stock", h.AdminSetWarehouseStock)
+			protected.GET("/warehouses/:id/shipments", h.AdminGetWarehouseShipments)
+			protected.PUT("/shipments/:id/ship", h.AdminShipShipment)
+
+			// 商品问答
+			protected.GET("/questions", h.AdminGetQuestions)
+			protected.PUT("/questions/:id/review", h.AdminReviewQuestion)
+			protected.POST("/questions/:id/answers", h.AdminAnswerQuestion)
+			protected.DELETE("/questions/:id", h.AdminDeleteQuestion)
+			protected.GET("/answers", h.AdminGetAnswers)
+			protected.PUT("/answers/:id/review", h.AdminReviewAnswer)
+			protected.DELETE("/answers/:id", h.AdminDeleteAnswer)
+
+			// 评论审核与商家回复
+			protected.GET("/comments", h.AdminGetComments)
+			protected.POST("/comments/batch", h.AdminBatchComments)
+			protected.PUT("/comments/:id/reply", h.AdminReplyComment)
+			protected.DELETE("/comments/:id/reply", h.AdminDeleteCommentReply)
+			protected.GET("/sensitive-words", h.AdminGetSensitiveWords)
+			protected.POST("/sensitive-words", h.AdminAddSensitiveWords)
+			protected.PUT("/sensitive-words/:id", h.AdminUpdateSensitiveWord)
+			protected.DELETE("/sensitive-words/:id", h.AdminDeleteSensitiveWord)
+
+			// Bundles
+			protected.GET("/bundles", h.AdminGetBundles)
+			protected.POST("/bundles", h.AdminCreateBundle)
+			protected.GET("/bundles/:id", h.AdminGetBundle)
+			protected.PUT("/bundles/:id", h.AdminUpdateBundle)
+			protected.DELETE("/bundles/:id", h.AdminDeleteBundle)
+			protected.PUT("/bundles/:id/toggle-status", h.AdminToggleBundleStatus)
 
 			// Orders
 			protected.GET("/orders", h.AdminGetOrders)
@@ -250,9 +416,11 @@ func initAdminRoutes(router *gin.Engine, h *admin.Handler) {
 
 			// Categories
 			protected.GET("/categories", h.AdminGetCategories)
+			protected.GET("/categories/tree", h.AdminGetCategoryTree)
 			protected.GET("/categories/:id", h.AdminGetCategory)
 			protected.POST("/categories", h.AdminCreateCategory)
 			protected.PUT("/categories/:id", h.AdminUpdateCategory)
+			protected.PUT("/categories/:id/move", h.AdminMoveCategory)
 			protected.DELETE("/categories/:id", h.AdminDeleteCategory)
 
 			// Tags
@@ -306,6 +474,22 @@ func initAdminRoutes(router *gin.Engine, h *admin.Handler) {
 			protected.GET("/crm/events/daily", h.AdminGetDailyEventStats)
 			protected.GET("/crm/events/user/:userId", h.AdminGetCRMEventsByUser)
 			protected.GET("/crm/events/product/:spuId", h.AdminGetCRMEventsBySPU)
+
+			// Distribution - Commission
+			protected.GET("/distribution/rules", h.AdminGetCommissionRules)
+			protected.POST("/distribution/rules", h.AdminCreateCommissionRule)
+			protected.PUT("/distribution/rules/:id", h.AdminUpdateCommissionRule)
+			protected.DELETE("/distribution/rules/:id", h.AdminDeleteCommissionRule)
+			protected.GET("/distribution/commissions", h.AdminGetCommissions)
+			protected.POST("/distribution/commissions/settle", h.AdminSettleCommissions)
+			protected.GET("/distribution/referrers", h.AdminGetReferrerReport)
+
+			// Wallet (储值)
+			protected.GET("/wallets", h.AdminGetWallets)
+			protected.GET("/wallets/:userId", h.AdminGetWallet)
+			protected.GET("/wallets/:userId/transactions", h.AdminGetWalletTransactions)
+			protected.POST("/wallets/:userId/topup", h.AdminTopUpWallet)
+			protected.POST("/wallets/:userId/adjust", h.AdminAdjustWallet)
 		}
 	}
 }
@@ -0,0 +1,167 @@
+package admin_services
+
+import (
+	"errors"
+	"time"
+
+	"z26b-backend/internal"
+
+	"gorm.io/gorm"
+)
+
+type AdminBundleService struct {
+	db *gorm.DB
+}
+
+func NewAdminBundleService(db *gorm.DB) AdminBundleServiceInterface {
+	return &AdminBundleService{db: db}
+}
+
+// GetBundles 获取组合商品列表（管理后台）
+func (s *AdminBundleService) GetBundles(page, pageSize int, keyword, status string) ([]internal.Bundle, int64, error) {
+	var bundles []internal.Bundle
+	var total int64
+
+	query := s.db.Model(&internal.Bundle{})
+	if keyword != "" {
+		query = query.Where("name LIKE ?", "%"+keyword+"%")
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Preload("Items.SKU").Order("priority DESC, created_at DESC").Offset(offset).Limit(pageSize).Find(&bundles).Error; err != nil {
+		return nil, 0, err
+	}
+	for i := range bundles {
+		bundles[i].FillSummary()
+	}
+
+	return bundles, total, nil
+}
+
+// GetBundle 获取组合商品详情
+func (s *AdminBundleService) GetBundle(id string) (*internal.Bundle, error) {
+	var bundle internal.Bundle
+	if err := s.db.Preload("Items.SKU.SPU").Where("id = ?", id).First(&bundle).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("组合商品不存在")
+		}
+		return nil, err
+	}
+	bundle.FillSummary()
+	return &bundle, nil
+}
+
+// CreateBundle 创建组合商品
+func (s *AdminBundleService) CreateBundle(bundle *internal.Bundle) error {
+	if bundle.Price <= 0 {
+		return errors.New("组合价必须大于0")
+	}
+	if err := s.validateItems(bundle.Items); err != nil {
+		return err
+	}
+
+	now := time.Now().UnixMilli()
+	bundle.ID = internal.GenerateUUID()
+	if bundle.Status == "" {
+		bundle.Status = "ENABLED"
+	}
+	bundle.CreatedAt = now
+	bundle.UpdatedAt = now
+	for i := range bundle.Items {
+		bundle.Items[i].ID = internal.GenerateUUID()
+		bundle.Items[i].BundleID = bundle.ID
+		bundle.Items[i].SKU = nil
+	}
+
+	return s.db.Create(bundle).Error
+}
+
+// UpdateBundle 更新组合商品，items 不为 nil 时整体替换组成项
+func (s *AdminBundleService) UpdateBundle(id string, updates map[string]interface{}, items []internal.BundleItem) error {
+	if price, ok := updates["price"].(float64); ok && price <= 0 {
+		return errors.New("组合价必须大于0")
+	}
+	if items != nil {
+		if err := s.validateItems(items); err != nil {
+			return err
+		}
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		updates["updated_at"] = time.Now().UnixMilli()
+		result := tx.Model(&internal.Bundle{}).Where("id = ?", id).Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("组合商品不存在")
+		}
+
+		if items == nil {
+			return nil
+		}
+		if err := tx.Where("bundle_id = ?", id).Delete(&internal.BundleItem{}).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].ID = internal.GenerateUUID()
+			items[i].BundleID = id
+			items[i].SKU = nil
+		}
+		return tx.Create(&items).Error
+	})
+}
+
+// DeleteBundle 删除组合商品（历史订单明细保留组合ID）
+func (s *AdminBundleService) DeleteBundle(id string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("bundle_id = ?", id).Delete(&internal.BundleItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&internal.Bundle{}).Error
+	})
+}
+
+// UpdateBundleStatus 更新组合商品状态
+func (s *AdminBundleService) UpdateBundleStatus(id, status string) error {
+	return s.db.Model(&internal.Bundle{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     status,
+		"updated_at": time.Now().UnixMilli(),
+	}).Error
+}
+
+// validateItems 校验组成项：至少一个、数量大于0、SKU 存在且不重复
+func (s *AdminBundleService) validateItems(items []internal.BundleItem) error {
+	if len(items) == 0 {
+		return errors.New("请选择组合商品的组成SKU")
+	}
+
+	seen := make(map[string]bool, len(items))
+	skuIDs := make([]string, 0, len(items))
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return errors.New("组成项数量必须大于0")
+		}
+		if seen[item.SKUID] {
+			return errors.New("组成SKU不能重复")
+		}
+		seen[item.SKUID] = true
+		skuIDs = append(skuIDs, item.SKUID)
+	}
+
+	var count int64
+	if err := s.db.Model(&internal.SKU{}).Where("id IN ?", skuIDs).Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) != len(skuIDs) {
+		return errors.New("组成SKU不存在")
+	}
+	return nil
+}
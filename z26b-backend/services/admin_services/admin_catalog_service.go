@@ -0,0 +1,845 @@
+package admin_services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"z26b-backend/internal"
+	"z26b-backend/services/inventory"
+
+	"gorm.io/gorm"
+)
+
+// CatalogColumn 商品导入导出列
+type CatalogColumn struct {
+	Key   string // 表头（导出使用）
+	Label string // 中文表头（导入时同样可识别）
+	SPU   bool   // 是否为商品级字段
+}
+
+// CatalogColumns 商品导入导出格式：每行一个 SKU，同一商品编码的多行属于同一商品
+var CatalogColumns = []CatalogColumn{
+	{"spu_code", "商品编码", true},
+	{"name", "商品名称", true},
+	{"category", "分类", true},
+	{"tags", "标签", true},
+	{"cover_image", "封面图", true},
+	{"swiper_images", "轮播图", true},
+	{"detail", "详情", true},
+	{"status", "状态", true},
+	{"priority", "排序", true},
+	{"specs", "规格", true},
+	{"sku_code", "SKU编码", false},
+	{"sku_description", "SKU描述", false}, // 为空时取规格值，无规格时取商品名称
+	{"spec_values", "规格值", false},
+	{"sku_image", "SKU图片", false},
+	{"price", "价格", false},
+	{"count", "库存", false},
+}
+
+// catalogSPUColumns 商品级导入列对应的数据库列
+var catalogSPUColumns = map[string]string{
+	"detail": "detail", "cover_image": "cover_image", "swiper_images": "swipe_images",
+	"specs": "specs", "category": "category_id", "status": "status", "priority": "priority",
+}
+
+// 表格内的分隔符
+const (
+	catalogListSeparator     = "|" // 标签、轮播图、规格值列表
+	catalogSpecSeparator     = ";" // 规格维度
+	catalogSpecNameSeparator = ":" // 规格名与规格值
+	catalogCategorySeparator = "/" // 分类路径
+)
+
+// CatalogImportError 导入校验错误，Row 为表格行号（表头为第 1 行）
+type CatalogImportError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// CatalogImportResult 导入结果
+type CatalogImportResult struct {
+	DryRun      bool                 `json:"dryRun"`
+	Rows        int                  `json:"rows"`
+	SPUCreated  int                  `json:"spuCreated"`
+	SPUUpdated  int                  `json:"spuUpdated"`
+	SKUCreated  int                  `json:"skuCreated"`
+	SKUUpdated  int                  `json:"skuUpdated"`
+	CreatedTags []string             `json:"createdTags"`
+	Errors      []CatalogImportError `json:"errors"`
+	SPUIDs      []string             `json:"-"` // 写入的商品ID，用于同步搜索索引
+}
+
+// AdminCatalogService 商品批量导入导出服务
+type AdminCatalogService struct {
+	db *gorm.DB
+}
+
+// NewAdminCatalogService 创建商品批量导入导出服务
+func NewAdminCatalogService(db *gorm.DB) AdminCatalogServiceInterface {
+	return &AdminCatalogService{db: db}
+}
+
+// importSPU 导入文件中的一个商品
+type importSPU struct {
+	row        int
+	raw        map[string]string // 商品级字段原始值，用于检查同一商品多行是否一致；更新已有商品时只写入非空列
+	code       string
+	name       string
+	categoryID string
+	tagNames   []string
+	coverImage string
+	images     []string
+	detail     string
+	status     string
+	priority   int
+	specs      []internal.SpecDimension
+	skus       []*importSKU
+	existing   *internal.SPU
+}
+
+// importSKU 导入文件中的一个 SKU
+type importSKU struct {
+	row         int
+	raw         map[string]string // SKU 字段原始值，更新已有 SKU 时只写入非空列
+	code        string
+	description string
+	values      []string
+	image       string
+	price       float64
+	count       *int // 库存列为空时为 nil，不修改已有 SKU 的库存
+	existing    *internal.SKU
+}
+
+// catalogRow 按表头读取一行数据
+type catalogRow struct {
+	num    int
+	cells  []string
+	header map[string]int
+}
+
+func (r catalogRow) get(key string) string {
+	idx, ok := r.header[key]
+	if !ok || idx >= len(r.cells) {
+		return ""
+	}
+	return strings.TrimSpace(r.cells[idx])
+}
+
+func (r catalogRow) blank() bool {
+	for _, c := range r.cells {
+		if strings.TrimSpace(c) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// ImportCatalog 导入商品：先校验全部行，有错误时不写入任何数据；dryRun 只校验并返回预计变更
+func (s *AdminCatalogService) ImportCatalog(rows [][]string, dryRun bool, adminID string) (*CatalogImportResult, error) {
+	result := &CatalogImportResult{DryRun: dryRun, CreatedTags: []string{}, Errors: []CatalogImportError{}}
+	addErr := func(row int, column, format string, args ...interface{}) {
+		result.Errors = append(result.Errors, CatalogImportError{Row: row, Column: column, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(rows) == 0 {
+		addErr(1, "", "文件为空")
+		return result, nil
+	}
+
+	// 解析表头
+	columns := make(map[string]string, len(CatalogColumns)*2)
+	for _, col := range CatalogColumns {
+		columns[col.Key] = col.Key
+		columns[col.Label] = col.Key
+	}
+	header := make(map[string]int)
+	for i, name := range rows[0] {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		key, ok := columns[strings.ToLower(name)]
+		if !ok {
+			key, ok = columns[name]
+		}
+		if !ok {
+			addErr(1, name, "未知的列")
+			continue
+		}
+		if _, dup := header[key]; dup {
+			addErr(1, name, "列重复")
+			continue
+		}
+		header[key] = i
+	}
+	for _, key := range []string{"spu_code", "name"} {
+		if _, ok := header[key]; !ok {
+			addErr(1, key, "缺少必填列")
+		}
+	}
+	if len(result.Errors) > 0 {
+		return result, nil
+	}
+
+	// 参考数据
+	var categories []internal.Category
+	if err := s.db.Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	// 标签名唯一，回收站中的标签也占用名称
+	var tags []internal.Tag
+	if err := s.db.Unscoped().Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	tagByName := make(map[string]internal.Tag, len(tags))
+	for _, t := range tags {
+		tagByName[t.Name] = t
+	}
+
+	// 逐行解析
+	var groups []*importSPU
+	groupByCode := make(map[string]*importSPU)
+	skuCodes := make(map[string]int)
+	for i := 1; i < len(rows); i++ {
+		row := catalogRow{num: i + 1, cells: rows[i], header: header}
+		if row.blank() {
+			continue
+		}
+		result.Rows++
+
+		code := row.get("spu_code")
+		if code == "" {
+			addErr(row.num, "spu_code", "商品编码不能为空")
+			continue
+		}
+
+		group := groupByCode[code]
+		if group == nil {
+			group = s.parseSPU(row, categories, addErr)
+			groupByCode[code] = group
+			groups = append(groups, group)
+		} else {
+			for _, col := range CatalogColumns {
+				if !col.SPU || col.Key == "spu_code" {
+					continue
+				}
+				if v := row.get(col.Key); v != "" && v != group.raw[col.Key] {
+					addErr(row.num, col.Key, "与第 %d 行的商品信息不一致", group.row)
+				}
+			}
+		}
+
+		if sku := parseSKU(row, addErr); sku != nil {
+			if first, dup := skuCodes[sku.code]; dup {
+				addErr(row.num, "sku_code", "SKU编码与第 %d 行重复", first)
+				continue
+			}
+			skuCodes[sku.code] = row.num
+			group.skus = append(group.skus, sku)
+		}
+	}
+
+	// 按编码（或导出文件中的ID）匹配已有商品和 SKU
+	if err := s.matchExisting(groups, addErr); err != nil {
+		return nil, err
+	}
+
+	// 规格值校验：已有商品和 SKU 未填写的规格沿用当前值
+	for _, group := range groups {
+		if group.existing != nil && group.raw["specs"] == "" {
+			group.specs = internal.ParseSpecs(group.existing.Specs)
+		}
+		specKeys := make(map[string]int)
+		for _, sku := range group.skus {
+			if sku.existing != nil && sku.raw["spec_values"] == "" {
+				sku.values = internal.ParseSpecValues(sku.existing.SpecValues)
+			}
+			if len(sku.values) == 0 {
+				if sku.description == "" && sku.existing == nil {
+					sku.description = group.name
+				}
+				continue
+			}
+			if len(group.specs) == 0 {
+				addErr(sku.row, "spec_values", "商品未设置规格")
+				continue
+			}
+			if err := internal.ValidateSpecValues(group.specs, sku.values); err != nil {
+				addErr(sku.row, "spec_values", "%s", err.Error())
+				continue
+			}
+			key := internal.SpecKey(sku.values)
+			if first, dup := specKeys[key]; dup {
+				addErr(sku.row, "spec_values", "规格组合与第 %d 行重复", first)
+				continue
+			}
+			specKeys[key] = sku.row
+			if sku.description == "" && sku.existing == nil {
+				sku.description = strings.Join(sku.values, " ")
+			}
+		}
+	}
+
+	// 预计变更
+	newTags := make(map[string]bool)
+	for _, group := range groups {
+		if group.existing != nil {
+			result.SPUUpdated++
+		} else {
+			result.SPUCreated++
+		}
+		for _, sku := range group.skus {
+			if sku.existing != nil {
+				result.SKUUpdated++
+			} else {
+				result.SKUCreated++
+			}
+		}
+		for _, name := range group.tagNames {
+			tag, ok := tagByName[name]
+			if ok && tag.DeletedAt.Valid {
+				addErr(group.row, "tags", "标签 %s 在回收站中，请先恢复", name)
+				continue
+			}
+			if !ok && !newTags[name] {
+				newTags[name] = true
+				result.CreatedTags = append(result.CreatedTags, name)
+			}
+		}
+	}
+
+	if len(result.Errors) > 0 {
+		sort.SliceStable(result.Errors, func(i, j int) bool { return result.Errors[i].Row < result.Errors[j].Row })
+		return result, nil
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		for _, name := range result.CreatedTags {
+			tag := internal.Tag{ID: internal.GenerateUUID(), Name: name, Status: "active", CreatedAt: now, UpdatedAt: now}
+			if err := tx.Create(&tag).Error; err != nil {
+				return err
+			}
+			tagByName[name] = tag
+		}
+		for _, group := range groups {
+			spuID, err := s.saveSPU(tx, group, tagByName, adminID)
+			if err != nil {
+				return fmt.Errorf("第 %d 行: %w", group.row, err)
+			}
+			result.SPUIDs = append(result.SPUIDs, spuID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// parseSPU 解析商品级字段
+func (s *AdminCatalogService) parseSPU(row catalogRow, categories []internal.Category, addErr func(int, string, string, ...interface{})) *importSPU {
+	group := &importSPU{row: row.num, raw: make(map[string]string), code: row.get("spu_code")}
+	for _, col := range CatalogColumns {
+		if col.SPU {
+			group.raw[col.Key] = row.get(col.Key)
+		}
+	}
+
+	group.name = row.get("name")
+	if group.name == "" {
+		addErr(row.num, "name", "商品名称不能为空")
+	}
+
+	if v := row.get("category"); v != "" {
+		id, err := resolveCategory(categories, v)
+		if err != nil {
+			addErr(row.num, "category", "%s", err.Error())
+		}
+		group.categoryID = id
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range splitList(row.get("tags")) {
+		if !seen[name] {
+			seen[name] = true
+			group.tagNames = append(group.tagNames, name)
+		}
+	}
+
+	group.coverImage = row.get("cover_image")
+	if group.coverImage != "" && !isImageURL(group.coverImage) {
+		addErr(row.num, "cover_image", "图片地址必须是 http(s) 链接")
+	}
+	group.images = splitList(row.get("swiper_images"))
+	for _, u := range group.images {
+		if !isImageURL(u) {
+			addErr(row.num, "swiper_images", "图片地址必须是 http(s) 链接: %s", u)
+		}
+	}
+
+	group.detail = internal.SanitizeHTML(row.get("detail"))
+
+	switch v := row.get("status"); strings.ToUpper(v) {
+	case "", "ENABLED", "上架":
+		group.status = "ENABLED"
+	case "DISABLED", "下架":
+		group.status = "DISABLED"
+	default:
+		addErr(row.num, "status", "状态只能是 ENABLED 或 DISABLED")
+	}
+
+	if v := row.get("priority"); v != "" {
+		p, err := strconv.Atoi(v)
+		if err != nil {
+			addErr(row.num, "priority", "排序必须是整数")
+		}
+		group.priority = p
+	}
+
+	if v := row.get("specs"); v != "" {
+		specs, err := parseSpecsCell(v)
+		if err != nil {
+			addErr(row.num, "specs", "%s", err.Error())
+		}
+		group.specs = specs
+	}
+	return group
+}
+
+// parseSKU 解析 SKU 字段，SKU 列全部为空时返回 nil
+func parseSKU(row catalogRow, addErr func(int, string, string, ...interface{})) *importSKU {
+	empty := true
+	for _, col := range CatalogColumns {
+		if !col.SPU && row.get(col.Key) != "" {
+			empty = false
+			break
+		}
+	}
+	if empty {
+		return nil
+	}
+
+	sku := &importSKU{
+		row:         row.num,
+		raw:         make(map[string]string),
+		code:        row.get("sku_code"),
+		description: row.get("sku_description"),
+		image:       row.get("sku_image"),
+	}
+	for _, col := range CatalogColumns {
+		if !col.SPU {
+			sku.raw[col.Key] = row.get(col.Key)
+		}
+	}
+	if sku.code == "" {
+		addErr(row.num, "sku_code", "SKU编码不能为空")
+	}
+	if v := row.get("spec_values"); v != "" {
+		for _, part := range strings.Split(v, catalogSpecSeparator) {
+			sku.values = append(sku.values, strings.TrimSpace(part))
+		}
+	}
+	if sku.image != "" && !isImageURL(sku.image) {
+		addErr(row.num, "sku_image", "图片地址必须是 http(s) 链接")
+	}
+
+	price, err := strconv.ParseFloat(row.get("price"), 64)
+	if err != nil || price < 0 {
+		addErr(row.num, "price", "价格必须是非负数字")
+	}
+	sku.price = internal.RoundMoney(price)
+
+	if v := row.get("count"); v != "" {
+		count, err := strconv.Atoi(v)
+		if err != nil || count < 0 {
+			addErr(row.num, "count", "库存必须是非负整数")
+		}
+		sku.count = &count
+	}
+	if sku.code == "" {
+		return nil
+	}
+	return sku
+}
+
+// matchExisting 匹配已有商品和 SKU：先按编码匹配，未命中时按ID匹配（导出文件中未设置编码的商品以ID作为编码）
+func (s *AdminCatalogService) matchExisting(groups []*importSPU, addErr func(int, string, string, ...interface{})) error {
+	if len(groups) == 0 {
+		return nil
+	}
+	var spuCodes, skuCodes []string
+	for _, group := range groups {
+		spuCodes = append(spuCodes, group.code)
+		for _, sku := range group.skus {
+			skuCodes = append(skuCodes, sku.code)
+		}
+	}
+
+	var spus []internal.SPU
+	if err := s.db.Where("code IN ? OR id IN ?", spuCodes, spuCodes).Find(&spus).Error; err != nil {
+		return err
+	}
+	spuByCode := make(map[string]*internal.SPU)
+	spuByID := make(map[string]*internal.SPU)
+	for i := range spus {
+		if spus[i].Code != "" {
+			spuByCode[spus[i].Code] = &spus[i]
+		}
+		spuByID[spus[i].ID] = &spus[i]
+	}
+
+	var skus []internal.SKU
+	if len(skuCodes) > 0 {
+		if err := s.db.Where("code IN ? OR id IN ?", skuCodes, skuCodes).Find(&skus).Error; err != nil {
+			return err
+		}
+	}
+	skuByCode := make(map[string]*internal.SKU)
+	skuByID := make(map[string]*internal.SKU)
+	for i := range skus {
+		if skus[i].Code != "" {
+			skuByCode[skus[i].Code] = &skus[i]
+		}
+		skuByID[skus[i].ID] = &skus[i]
+	}
+
+	for _, group := range groups {
+		if spu, ok := spuByCode[group.code]; ok {
+			group.existing = spu
+		} else if spu, ok := spuByID[group.code]; ok && spu.Code == "" {
+			group.existing = spu
+		}
+		for _, sku := range group.skus {
+			existing, ok := skuByCode[sku.code]
+			if !ok {
+				if e, found := skuByID[sku.code]; found && e.Code == "" {
+					existing, ok = e, true
+				}
+			}
+			if !ok {
+				continue
+			}
+			if group.existing == nil || existing.SPUID != group.existing.ID {
+				addErr(sku.row, "sku_code", "SKU编码 %s 已属于其他商品", sku.code)
+				continue
+			}
+			sku.existing = existing
+		}
+	}
+	return nil
+}
+
+// saveSPU 写入商品、标签和 SKU，返回商品ID
+func (s *AdminCatalogService) saveSPU(tx *gorm.DB, group *importSPU, tagByName map[string]internal.Tag, adminID string) (string, error) {
+	now := time.Now().UnixMilli()
+
+	var specs, images interface{}
+	if len(group.specs) > 0 {
+		specs = internal.ToJSON(group.specs)
+	}
+	if len(group.images) > 0 {
+		images = internal.ToJSON(group.images)
+	}
+
+	spuID := ""
+	if group.existing != nil {
+		spuID = group.existing.ID
+		// 只更新文件中填写了的列，空单元格保留当前值
+		updates := map[string]interface{}{
+			"code": group.code, "name": group.name, "updated_at": now, "updated_by": adminID,
+		}
+		for key, value := range map[string]interface{}{
+			"detail": group.detail, "cover_image": group.coverImage, "swiper_images": images,
+			"specs": specs, "category": group.categoryID, "status": group.status, "priority": group.priority,
+		} {
+			if group.raw[key] != "" {
+				updates[catalogSPUColumns[key]] = value
+			}
+		}
+		if err := tx.Model(&internal.SPU{}).Where("id = ?", spuID).Updates(updates).Error; err != nil {
+			return "", err
+		}
+	} else {
+		spu := internal.SPU{
+			ID: internal.GenerateUUID(), Code: group.code, Name: group.name, Detail: group.detail,
+			CoverImage: group.coverImage, CategoryID: group.categoryID,
+			Status: group.status, Priority: group.priority,
+			CreatedAt: now, UpdatedAt: now, CreatedBy: adminID, UpdatedBy: adminID,
+		}
+		if len(group.specs) > 0 {
+			spu.Specs = internal.ToJSON(group.specs)
+		}
+		if len(group.images) > 0 {
+			spu.SwipeImages = internal.ToJSON(group.images)
+		}
+		if err := tx.Create(&spu).Error; err != nil {
+			return "", err
+		}
+		spuID = spu.ID
+	}
+
+	// 标签以文件为准，回收站中标签的关联保留；已有商品的标签列为空时不修改
+	if group.existing != nil && group.raw["tags"] == "" {
+		return spuID, s.saveSKUs(tx, spuID, group, adminID)
+	}
+	if err := tx.Where("spu_id = ? AND tag_id IN (SELECT id FROM tag WHERE deleted_at IS NULL)", spuID).Delete(&internal.SPUTag{}).Error; err != nil {
+		return "", err
+	}
+	for _, name := range group.tagNames {
+		spuTag := internal.SPUTag{ID: internal.GenerateUUID(), SPUID: spuID, TagID: tagByName[name].ID, CreatedAt: time.Now()}
+		if err := tx.Create(&spuTag).Error; err != nil {
+			return "", err
+		}
+	}
+	return spuID, s.saveSKUs(tx, spuID, group, adminID)
+}
+
+// saveSKUs 写入商品的 SKU，记录库存流水和调价历史
+func (s *AdminCatalogService) saveSKUs(tx *gorm.DB, spuID string, group *importSPU, adminID string) error {
+	now := time.Now().UnixMilli()
+	var changes []internal.SKUPriceHistory
+	for _, item := range group.skus {
+		var values interface{}
+		specKey := ""
+		if len(item.values) > 0 {
+			values = internal.ToJSON(item.values)
+			specKey = internal.SpecKey(item.values)
+		}
+		if item.existing != nil {
+			updates := map[string]interface{}{
+				"code": item.code, "price": item.price, "updated_at": now, "updated_by": adminID,
+			}
+			if item.raw["sku_description"] != "" {
+				updates["description"] = item.description
+			}
+			if item.raw["sku_image"] != "" {
+				updates["image"] = item.image
+			}
+			if item.raw["spec_values"] != "" {
+				updates["spec_values"] = values
+				updates["spec_key"] = specKey
+			}
+			if err := tx.Model(&internal.SKU{}).Where("id = ?", item.existing.ID).Updates(updates).Error; err != nil {
+				return err
+			}
+			// 库存差额记入库存流水，库存列为空时不修改
+			if item.count != nil {
+				if _, err := inventory.NewInventoryService(tx).SetCount(item.existing.ID, *item.count, internal.StockReasonImport, "", adminID, "批量导入"); err != nil {
+					return err
+				}
+			}
+			oldPrice := item.existing.Price
+			changes = append(changes, internal.PriceChange(item.existing.ID, &oldPrice, item.price))
+			continue
+		}
+		count := 0
+		if item.count != nil {
+			count = *item.count
+		}
+		sku := internal.SKU{
+			ID: internal.GenerateUUID(), SPUID: spuID, Code: item.code, Description: item.description,
+			Image: item.image, Price: item.price, Count: count, SpecKey: specKey,
+			CreatedAt: now, UpdatedAt: now, CreatedBy: adminID, UpdatedBy: adminID,
+		}
+		if len(item.values) > 0 {
+			sku.SpecValues = internal.ToJSON(item.values)
+		}
+		if err := tx.Create(&sku).Error; err != nil {
+			return err
+		}
+		if err := inventory.NewInventoryService(tx).RecordCreated(&sku, internal.StockReasonImport, "", adminID, "批量导入"); err != nil {
+			return err
+		}
+		changes = append(changes, internal.PriceChange(sku.ID, nil, sku.Price))
+	}
+
+	// 记录调价历史并更新 SPU 价格范围
+	return internal.RecordSKUPriceChanges(tx, spuID, changes, internal.PriceSourceImport, adminID)
+}
+
+// ExportCatalog 导出全部商品，格式与导入一致；未设置编码的商品和 SKU 以ID作为编码
+func (s *AdminCatalogService) ExportCatalog() ([][]string, error) {
+	var spus []internal.SPU
+	if err := s.db.Order("created_at ASC, id ASC").Find(&spus).Error; err != nil {
+		return nil, err
+	}
+	var categories []internal.Category
+	if err := s.db.Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	var spuTags []internal.SPUTag
+	if err := s.db.Preload("Tag").Order("created_at ASC").Find(&spuTags).Error; err != nil {
+		return nil, err
+	}
+	tagsBySPU := make(map[string][]string)
+	for _, st := range spuTags {
+		if st.Tag != nil {
+			tagsBySPU[st.SPUID] = append(tagsBySPU[st.SPUID], st.Tag.Name)
+		}
+	}
+	var skus []internal.SKU
+	if err := s.db.Order("created_at ASC, id ASC").Find(&skus).Error; err != nil {
+		return nil, err
+	}
+	skusBySPU := make(map[string][]internal.SKU)
+	for _, sku := range skus {
+		skusBySPU[sku.SPUID] = append(skusBySPU[sku.SPUID], sku)
+	}
+
+	header := make([]string, len(CatalogColumns))
+	for i, col := range CatalogColumns {
+		header[i] = col.Key
+	}
+	rows := [][]string{header}
+
+	for _, spu := range spus {
+		code := spu.Code
+		if code == "" {
+			code = spu.ID
+		}
+		var names []string
+		for _, c := range internal.CategoryPath(categories, spu.CategoryID) {
+			names = append(names, c.Name)
+		}
+		var images []string
+		if len(spu.SwipeImages) > 0 {
+			_ = json.Unmarshal(spu.SwipeImages, &images)
+		}
+		spuCells := map[string]string{
+			"spu_code":      code,
+			"name":          spu.Name,
+			"category":      strings.Join(names, catalogCategorySeparator),
+			"tags":          strings.Join(tagsBySPU[spu.ID], catalogListSeparator),
+			"cover_image":   spu.CoverImage,
+			"swiper_images": strings.Join(images, catalogListSeparator),
+			"detail":        spu.Detail,
+			"status":        spu.Status,
+			"priority":      strconv.Itoa(spu.Priority),
+			"specs":         formatSpecsCell(internal.ParseSpecs(spu.Specs)),
+		}
+
+		items := skusBySPU[spu.ID]
+		if len(items) == 0 {
+			rows = append(rows, catalogExportRow(spuCells))
+			continue
+		}
+		for i, sku := range items {
+			cells := map[string]string{"spu_code": code}
+			if i == 0 {
+				cells = spuCells
+			}
+			skuCode := sku.Code
+			if skuCode == "" {
+				skuCode = sku.ID
+			}
+			cells["sku_code"] = skuCode
+			cells["sku_description"] = sku.Description
+			cells["spec_values"] = internal.SpecKey(internal.ParseSpecValues(sku.SpecValues))
+			cells["sku_image"] = sku.Image
+			cells["price"] = strconv.FormatFloat(sku.Price, 'f', -1, 64)
+			cells["count"] = strconv.Itoa(sku.Count)
+			rows = append(rows, catalogExportRow(cells))
+		}
+	}
+	return rows, nil
+}
+
+func catalogExportRow(cells map[string]string) []string {
+	row := make([]string, len(CatalogColumns))
+	for i, col := range CatalogColumns {
+		row[i] = cells[col.Key]
+	}
+	return row
+}
+
+// resolveCategory 按名称或完整路径（如 服装/女装）查找分类
+func resolveCategory(categories []internal.Category, value string) (string, error) {
+	var matched []internal.Category
+	for _, c := range categories {
+		if c.Name == value {
+			matched = append(matched, c)
+		}
+	}
+	if len(matched) == 1 {
+		return matched[0].ID, nil
+	}
+	if len(matched) > 1 {
+		return "", fmt.Errorf("分类名称 %s 不唯一，请使用完整路径", value)
+	}
+
+	if strings.Contains(value, catalogCategorySeparator) {
+		parentID := ""
+		for _, name := range strings.Split(value, catalogCategorySeparator) {
+			name = strings.TrimSpace(name)
+			found := ""
+			for _, c := range categories {
+				if c.ParentID == parentID && c.Name == name {
+					found = c.ID
+					break
+				}
+			}
+			if found == "" {
+				return "", fmt.Errorf("分类不存在: %s", value)
+			}
+			parentID = found
+		}
+		return parentID, nil
+	}
+	return "", fmt.Errorf("分类不存在: %s", value)
+}
+
+// parseSpecsCell 解析规格列，如 颜色:红|蓝;尺码:S|M
+func parseSpecsCell(value string) ([]internal.SpecDimension, error) {
+	var specs []internal.SpecDimension
+	for _, part := range strings.Split(value, catalogSpecSeparator) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, values, ok := strings.Cut(part, catalogSpecNameSeparator)
+		if !ok {
+			return nil, fmt.Errorf("规格格式错误，应为 规格名:值1|值2")
+		}
+		specs = append(specs, internal.SpecDimension{Name: strings.TrimSpace(name), Values: splitList(values)})
+	}
+	if err := internal.ValidateSpecs(specs); err != nil {
+		return nil, err
+	}
+	return specs, nil
+}
+
+// formatSpecsCell 规格维度格式化为规格列
+func formatSpecsCell(specs []internal.SpecDimension) string {
+	parts := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		parts = append(parts, spec.Name+catalogSpecNameSeparator+strings.Join(spec.Values, catalogListSeparator))
+	}
+	return strings.Join(parts, catalogSpecSeparator)
+}
+
+// splitList 按 | 拆分列表并去除空项
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, catalogListSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func isImageURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
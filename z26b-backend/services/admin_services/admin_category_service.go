@@ -1,6 +1,7 @@
 package admin_services
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -31,20 +32,87 @@ func (s *AdminCategoryService) GetCategory(id string) (*internal.Category, error
 	return &category, err
 }
 
+// GetCategoryTree 获取树形分类
+func (s *AdminCategoryService) GetCategoryTree() ([]internal.Category, error) {
+	categories, err := s.GetCategories()
+	if err != nil {
+		return nil, err
+	}
+	return internal.BuildCategoryTree(categories), nil
+}
+
 // CreateCategory 创建分类
 func (s *AdminCategoryService) CreateCategory(req *internal.Category) error {
+	if req.ParentID != "" {
+		var count int64
+		s.db.Model(&internal.Category{}).Where("id = ?", req.ParentID).Count(&count)
+		if count == 0 {
+			return errors.New("上级分类不存在")
+		}
+	}
 	req.ID = internal.GenerateUUID()
 	return s.db.Create(req).Error
 }
 
-// UpdateCategory 更新分类
+// UpdateCategory 更新分类，修改上级分类时校验不会形成环
 func (s *AdminCategoryService) UpdateCategory(id string, updates map[string]interface{}) error {
+	if parentID, ok := updates["parent_id"].(string); ok {
+		if err := s.checkParent(id, parentID); err != nil {
+			return err
+		}
+	}
 	updates["updated_at"] = time.Now()
 	return s.db.Model(&internal.Category{}).Where("id = ?", id).Updates(updates).Error
 }
 
-// DeleteCategory 删除分类
+// MoveCategory 移动分类（连同子分类）到新的上级分类下，parentID 为空时移动为一级分类
+func (s *AdminCategoryService) MoveCategory(id, parentID string, sort *int) error {
+	updates := map[string]interface{}{"parent_id": parentID}
+	if sort != nil {
+		updates["sort"] = *sort
+	}
+	return s.UpdateCategory(id, updates)
+}
+
+// checkParent 校验上级分类存在，且不是分类自身或其子孙分类
+func (s *AdminCategoryService) checkParent(id, parentID string) error {
+	var categories []internal.Category
+	if err := s.db.Select("id", "parent_id").Find(&categories).Error; err != nil {
+		return err
+	}
+
+	found, parentFound := false, parentID == ""
+	for _, c := range categories {
+		found = found || c.ID == id
+		parentFound = parentFound || c.ID == parentID
+	}
+	if !found {
+		return errors.New("分类不存在")
+	}
+	if !parentFound {
+		return errors.New("上级分类不存在")
+	}
+	if parentID == "" {
+		return nil
+	}
+
+	for _, descendant := range internal.CategoryDescendantIDs(categories, id) {
+		if descendant == parentID {
+			return errors.New("不能将分类移动到自身或其子分类下")
+		}
+	}
+	return nil
+}
+
+// DeleteCategory 删除分类（移入回收站）
 func (s *AdminCategoryService) DeleteCategory(id string) error {
+	// 检查是否有子分类
+	var children int64
+	s.db.Model(&internal.Category{}).Where("parent_id = ?", id).Count(&children)
+	if children > 0 {
+		return fmt.Errorf("分类下有子分类，无法删除")
+	}
+
 	// 检查是否有商品使用此分类
 	var count int64
 	s.db.Model(&internal.SPU{}).Where("category_id = ?", id).Count(&count)
@@ -0,0 +1,186 @@
+package admin_services
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"z26b-backend/internal"
+
+	"gorm.io/gorm"
+)
+
+// MaxSellerReplyLength 商家回复的最大字数
+const MaxSellerReplyLength = 500
+
+var ErrCommentNotFound = errors.New("评论不存在")
+
+// 评论批量操作
+const (
+	CommentActionApprove = "approve"
+	CommentActionReject  = "reject"
+	CommentActionHide    = "hide"
+	CommentActionDelete  = "delete"
+)
+
+// commentActionStatus 批量操作对应的审核状态
+var commentActionStatus = map[string]string{
+	CommentActionApprove: internal.CommentStatusApproved,
+	CommentActionReject:  internal.CommentStatusRejected,
+	CommentActionHide:    internal.CommentStatusHidden,
+}
+
+// AdminCommentQuery 评论列表筛选条件
+type AdminCommentQuery struct {
+	Status    string
+	SPUID     string
+	Keyword   string
+	Score     int   // 评分 1-5，0 表示不限
+	StartTime int64 // 评论时间范围（毫秒），0 表示不限
+	EndTime   int64
+	Replied   *bool // 是否已回复
+}
+
+type AdminCommentService struct {
+	db *gorm.DB
+}
+
+func NewAdminCommentService(db *gorm.DB) AdminCommentServiceInterface {
+	return &AdminCommentService{db: db}
+}
+
+// GetComments 获取评论列表（审核队列），按评论时间倒序
+func (s *AdminCommentService) GetComments(q AdminCommentQuery, page, pageSize int) ([]internal.Comment, int64, error) {
+	comments := []internal.Comment{}
+	var total int64
+
+	query := s.db.Model(&internal.Comment{})
+	if q.Status != "" {
+		query = query.Where("status = ?", q.Status)
+	}
+	if q.SPUID != "" {
+		query = query.Where("spu_id = ?", q.SPUID)
+	}
+	if q.Keyword != "" {
+		query = query.Where("comment_content LIKE ?", "%"+q.Keyword+"%")
+	}
+	if q.Score > 0 {
+		query = query.Where("comment_score = ?", q.Score)
+	}
+	// 评论时间以秒保存
+	if q.StartTime > 0 {
+		query = query.Where("created_at >= ?", q.StartTime/1000)
+	}
+	if q.EndTime > 0 {
+		query = query.Where("created_at <= ?", q.EndTime/1000)
+	}
+	if q.Replied != nil {
+		if *q.Replied {
+			query = query.Where("seller_reply <> ''")
+		} else {
+			query = query.Where("(seller_reply IS NULL OR seller_reply = '')")
+		}
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Preload("SPU", internal.WithDeleted).
+		Order("created_at DESC, id ASC").Offset(offset).Limit(pageSize).Find(&comments).Error
+	return comments, total, err
+}
+
+// BatchComments 批量审核通过、驳回、隐藏或删除评论，返回处理条数
+func (s *AdminCommentService) BatchComments(ids []string, action, reason, adminID string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, errors.New("请选择评论")
+	}
+	if action == CommentActionDelete {
+		return deleteComments(s.db, ids)
+	}
+
+	status, ok := commentActionStatus[action]
+	if !ok {
+		return 0, errors.New("无效的操作")
+	}
+	if status != internal.CommentStatusRejected {
+		reason = ""
+	}
+	now := time.Now().UnixMilli()
+	result := s.db.Model(&internal.Comment{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+		"status":        status,
+		"reject_reason": reason,
+		"reviewed_by":   adminID,
+		"reviewed_at":   now,
+	})
+	return result.RowsAffected, result.Error
+}
+
+// deleteComments 删除评论及其图片记录，并清除订单商品的评价关联，买家可以重新评价
+func deleteComments(db *gorm.DB, ids []string) (int64, error) {
+	var deleted int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		commented := tx.Model(&internal.OrderItem{}).Select("order_id").Where("comment_id IN ?", ids)
+		if err := tx.Model(&internal.Order{}).Where("id IN (?)", commented).
+			Update("reviewed_at", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&internal.OrderItem{}).Where("comment_id IN ?", ids).
+			Update("comment_id", "").Error; err != nil {
+			return err
+		}
+		if err := tx.Where("comment_id IN ?", ids).Delete(&internal.ReviewMedia{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id IN ?", ids).Delete(&internal.Comment{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	return deleted, err
+}
+
+// ReplyComment 发表或修改商家回复，回复时间记为最后一次修改时间
+func (s *AdminCommentService) ReplyComment(id, content, adminID string) (*internal.Comment, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, errors.New("请填写回复内容")
+	}
+	if utf8.RuneCountInString(content) > MaxSellerReplyLength {
+		return nil, errors.New("回复内容过长")
+	}
+
+	now := time.Now().UnixMilli()
+	result := s.db.Model(&internal.Comment{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"seller_reply":    content,
+		"seller_reply_at": now,
+		"seller_reply_by": adminID,
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrCommentNotFound
+	}
+
+	var comment internal.Comment
+	err := s.db.First(&comment, "id = ?", id).Error
+	return &comment, err
+}
+
+// DeleteReply 删除商家回复
+func (s *AdminCommentService) DeleteReply(id string) error {
+	result := s.db.Model(&internal.Comment{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"seller_reply":    "",
+		"seller_reply_at": nil,
+		"seller_reply_by": "",
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package admin_services
+
+import (
+	"testing"
+
+	"z26b-backend/internal"
+)
+
+func TestBatchComments(t *testing.T) {
+	tests := []struct {
+		name       string
+		action     string
+		reason     string
+		wantStatus string
+		wantReason string
+		wantErr    bool
+	}{
+		{"通过", CommentActionApprove, "忽略", internal.CommentStatusApproved, "", false},
+		{"驳回保留原因", CommentActionReject, "含广告", internal.CommentStatusRejected, "含广告", false},
+		{"隐藏", CommentActionHide, "", internal.CommentStatusHidden, "", false},
+		{"无效操作", "publish", "", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t, &internal.Comment{})
+			db.Create(&internal.Comment{ID: "c1", Status: internal.CommentStatusPending})
+			s := NewAdminCommentService(db)
+
+			n, err := s.BatchComments([]string{"c1", "missing"}, tt.action, tt.reason, "admin")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("BatchComments() error = nil, want error")
+				}
+				return
+			}
+			if err != nil || n != 1 {
+				t.Fatalf("BatchComments() = %d, %v, want 1", n, err)
+			}
+			var comment internal.Comment
+			db.First(&comment, "id = ?", "c1")
+			if comment.Status != tt.wantStatus || comment.RejectReason != tt.wantReason || comment.ReviewedBy != "admin" {
+				t.Errorf("comment = %s/%q/%s, want %s/%q/admin", comment.Status, comment.RejectReason, comment.ReviewedBy, tt.wantStatus, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestBatchCommentsDelete(t *testing.T) {
+	db := newTestDB(t, &internal.Comment{}, &internal.ReviewMedia{}, &internal.OrderItem{}, &internal.Order{})
+	reviewedAt := int64(1)
+	db.Create(&internal.Order{ID: "o1", ReviewedAt: &reviewedAt})
+	db.Create(&internal.Order{ID: "o2", ReviewedAt: &reviewedAt})
+	db.Create(&internal.OrderItem{ID: "i1", OrderID: "o1", CommentID: "c1"})
+	db.Create(&internal.OrderItem{ID: "i2", OrderID: "o2", CommentID: "c2"})
+	db.Create(&internal.Comment{ID: "c1", OrderItemID: "i1"})
+	db.Create(&internal.Comment{ID: "c2", OrderItemID: "i2"})
+	db.Create(&internal.ReviewMedia{ID: "m1", UserID: "u1", CommentID: "c1"})
+	db.Create(&internal.ReviewMedia{ID: "m2", UserID: "u1", CommentID: "c2"})
+
+	n, err := NewAdminCommentService(db).BatchComments([]string{"c1"}, CommentActionDelete, "", "admin")
+	if err != nil || n != 1 {
+		t.Fatalf("BatchComments() = %d, %v, want 1", n, err)
+	}
+
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"评论已删除", countRows(db.Where("id = ?", "c1"), &internal.Comment{}), int64(0)},
+		{"图片已删除", countRows(db.Where("comment_id = ?", "c1"), &internal.ReviewMedia{}), int64(0)},
+		{"订单商品可重新评价", countRows(db.Where("id = ? AND comment_id = ''", "i1"), &internal.OrderItem{}), int64(1)},
+		{"订单待评价", countRows(db.Where("id = ? AND reviewed_at IS NULL", "o1"), &internal.Order{}), int64(1)},
+		{"其他评论不受影响", countRows(db.Where("comment_id = ?", "c2"), &internal.ReviewMedia{}), int64(1)},
+		{"其他订单不受影响", countRows(db.Where("id = ? AND reviewed_at IS NOT NULL", "o2"), &internal.Order{}), int64(1)},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
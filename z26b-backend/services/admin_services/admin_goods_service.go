@@ -1,9 +1,12 @@
 package admin_services
 
 import (
+	"errors"
+	"strings"
 	"time"
 
 	"z26b-backend/internal"
+	"z26b-backend/services/inventory"
 
 	"gorm.io/gorm"
 )
@@ -90,9 +93,16 @@ func (s *AdminGoodsService) UpdateProduct(id string, updates map[string]interfac
 	return s.db.Model(&internal.SPU{}).Where("id = ?", id).Updates(updates).Error
 }
 
-// DeleteProduct 删除商品
+// DeleteProduct 删除商品（移入回收站）
 func (s *AdminGoodsService) DeleteProduct(id string) error {
-	return s.db.Delete(&internal.SPU{}, "id = ?", id).Error
+	// 商品与其 SKU 使用同一删除时间，恢复商品时据此一并恢复；标签关联保留
+	now := time.Now()
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&internal.SKU{}).Where(`"SPUID" = ?`, id).UpdateColumn("deleted_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Model(&internal.SPU{}).Where("id = ?", id).UpdateColumn("deleted_at", now).Error
+	})
 }
 
 // GetCategories 获取分类列表（管理后台）
@@ -154,3 +164,159 @@ func (s *AdminGoodsService) CreateProduct(spu *internal.SPU, skus []internal.SKU
 func (s *AdminGoodsService) UpdateProductStatus(id, status string) error {
 	return s.db.Model(&internal.SPU{}).Where("id = ?", id).Update("status", status).Error
 }
+
+// ScheduleProduct 设置定时上架/下架时间（毫秒时间戳），nil 表示取消对应计划
+func (s *AdminGoodsService) ScheduleProduct(id string, publishAt, unpublishAt *int64, adminID string) error {
+	now := time.Now().UnixMilli()
+	if (publishAt != nil && *publishAt <= now) || (unpublishAt != nil && *unpublishAt <= now) {
+		return errors.New("定时时间必须晚于当前时间")
+	}
+	if publishAt != nil && unpublishAt != nil && *publishAt == *unpublishAt {
+		return errors.New("上架时间和下架时间不能相同")
+	}
+	result := s.db.Model(&internal.SPU{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"publish_at":   publishAt,
+		"unpublish_at": unpublishAt,
+		"updated_at":   now,
+		"updated_by":   adminID,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("商品不存在")
+	}
+	return nil
+}
+
+// SKUMatrixResult 规格矩阵生成结果
+type SKUMatrixResult struct {
+	Created   []internal.SKU `json:"created"`   // 新生成的 SKU
+	Existing  []internal.SKU `json:"existing"`  // 已存在对应组合的 SKU（保持不变）
+	Unmatched []internal.SKU `json:"unmatched"` // 不属于任何组合的 SKU，需人工处理
+}
+
+// SaveSpecs 保存商品规格维度
+func (s *AdminGoodsService) SaveSpecs(spuID string, specs []internal.SpecDimension) error {
+	if err := internal.ValidateSpecs(specs); err != nil {
+		return err
+	}
+	result := s.db.Model(&internal.SPU{}).Where("id = ?", spuID).Updates(map[string]interface{}{
+		"specs":      internal.ToJSON(specs),
+		"updated_at": time.Now().UnixMilli(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("商品不存在")
+	}
+	return nil
+}
+
+// GenerateSKUMatrix 保存规格维度并按全部规格组合生成 SKU，已存在的组合不会重复生成
+func (s *AdminGoodsService) GenerateSKUMatrix(spuID string, specs []internal.SpecDimension, price float64, count int, adminID string) (*SKUMatrixResult, error) {
+	if len(specs) == 0 {
+		return nil, errors.New("请设置规格维度")
+	}
+	if price < 0 || count < 0 {
+		return nil, errors.New("价格和库存不能为负数")
+	}
+
+	result := &SKUMatrixResult{
+		Created:   []internal.SKU{},
+		Existing:  []internal.SKU{},
+		Unmatched: []internal.SKU{},
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := (&AdminGoodsService{db: tx}).SaveSpecs(spuID, specs); err != nil {
+			return err
+		}
+
+		var skus []internal.SKU
+		if err := tx.Where(`"SPUID" = ?`, spuID).Find(&skus).Error; err != nil {
+			return err
+		}
+		byKey := make(map[string]internal.SKU, len(skus))
+		for _, sku := range skus {
+			if sku.SpecKey != "" {
+				byKey[sku.SpecKey] = sku
+			}
+		}
+
+		now := time.Now().UnixMilli()
+		matched := make(map[string]bool)
+		var changes []internal.SKUPriceHistory
+		for _, combo := range internal.SpecCombinations(specs) {
+			key := internal.SpecKey(combo)
+			if sku, ok := byKey[key]; ok {
+				matched[sku.ID] = true
+				result.Existing = append(result.Existing, sku)
+				continue
+			}
+			sku := internal.SKU{
+				ID: internal.GenerateUUID(), SPUID: spuID, Description: strings.Join(combo, " "),
+				SpecValues: internal.ToJSON(combo), SpecKey: key, Price: price, Count: count,
+				CreatedAt: now, UpdatedAt: now, CreatedBy: adminID, UpdatedBy: adminID,
+			}
+			if err := tx.Create(&sku).Error; err != nil {
+				return err
+			}
+			if err := inventory.NewInventoryService(tx).RecordCreated(&sku, internal.StockReasonInitial, "", adminID, "生成规格组合"); err != nil {
+				return err
+			}
+			result.Created = append(result.Created, sku)
+			changes = append(changes, internal.PriceChange(sku.ID, nil, sku.Price))
+		}
+
+		for _, sku := range skus {
+			if !matched[sku.ID] {
+				result.Unmatched = append(result.Unmatched, sku)
+			}
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		// 记录新 SKU 的初始价格并更新 SPU 价格范围
+		return internal.RecordSKUPriceChanges(tx, spuID, changes, internal.PriceSourceMatrix, adminID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ValidateSKUSpec 校验 SKU 规格值组合属于商品规格，且未被同商品的其他 SKU 占用
+func (s *AdminGoodsService) ValidateSKUSpec(spuID string, values []string, excludeSKUID string) error {
+	var spu internal.SPU
+	if err := s.db.Select("id", "specs").First(&spu, "id = ?", spuID).Error; err != nil {
+		return errors.New("商品不存在")
+	}
+	if err := internal.ValidateSpecValues(internal.ParseSpecs(spu.Specs), values); err != nil {
+		return err
+	}
+
+	var count int64
+	query := s.db.Model(&internal.SKU{}).Where(`"SPUID" = ? AND spec_key = ?`, spuID, internal.SpecKey(values))
+	if excludeSKUID != "" {
+		query = query.Where("id <> ?", excludeSKUID)
+	}
+	query.Count(&count)
+	if count > 0 {
+		return errors.New("该规格组合已存在SKU")
+	}
+	return nil
+}
+
+// GetPriceHistory 获取 SKU 调价记录，按生效时间倒序
+func (s *AdminGoodsService) GetPriceHistory(skuID string, page, pageSize int) ([]internal.SKUPriceHistory, int64, error) {
+	history := []internal.SKUPriceHistory{}
+	var total int64
+
+	query := s.db.Model(&internal.SKUPriceHistory{}).Where("sku_id = ?", skuID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("effective_at DESC, id ASC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&history).Error
+	return history, total, err
+}
@@ -22,7 +22,7 @@ func (s *AdminOrderService) GetOrders(page, pageSize int, keyword, status, userI
 	var orders []internal.Order
 	var total int64
 
-	query := s.db.Model(&internal.Order{}).Preload("OrderItems").Preload("OrderItems.SKU").Preload("OrderItems.SKU.SPU")
+	query := s.db.Model(&internal.Order{}).Preload("OrderItems").Preload("OrderItems.SKU", internal.WithDeleted).Preload("OrderItems.SKU.SPU", internal.WithDeleted)
 
 	if keyword != "" {
 		query = query.Where("order_no LIKE ? OR receiver_name LIKE ? OR receiver_phone LIKE ?",
@@ -60,7 +60,7 @@ func (s *AdminOrderService) GetOrders(page, pageSize int, keyword, status, userI
 // GetOrderByID 根据ID获取订单详情
 func (s *AdminOrderService) GetOrderByID(orderID string) (*internal.Order, error) {
 	var order internal.Order
-	if err := s.db.Preload("OrderItems").Preload("OrderItems.SKU").Preload("OrderItems.SKU.SPU").
+	if err := s.db.Preload("OrderItems").Preload("OrderItems.SKU", internal.WithDeleted).Preload("OrderItems.SKU.SPU", internal.WithDeleted).
 		Preload("User").Where("id = ?", orderID).First(&order).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("订单不存在")
@@ -0,0 +1,215 @@
+package admin_services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"z26b-backend/internal"
+
+	"gorm.io/gorm"
+)
+
+// OfficialAnswerName 商家回答对用户展示的名称
+const OfficialAnswerName = "官方客服"
+
+var (
+	ErrQuestionNotFound = errors.New("提问不存在")
+	ErrAnswerNotFound   = errors.New("回答不存在")
+)
+
+type AdminQuestionService struct {
+	db *gorm.DB
+}
+
+func NewAdminQuestionService(db *gorm.DB) AdminQuestionServiceInterface {
+	return &AdminQuestionService{db: db}
+}
+
+// validQAStatus 审核状态是否合法
+func validQAStatus(status string) bool {
+	return status == internal.QAStatusPending || status == internal.QAStatusApproved || status == internal.QAStatusRejected
+}
+
+// GetQuestions 获取提问列表（含全部回答），按提问时间倒序
+func (s *AdminQuestionService) GetQuestions(status, spuID, keyword string, page, pageSize int) ([]internal.ProductQuestion, int64, error) {
+	questions := []internal.ProductQuestion{}
+	var total int64
+
+	query := s.db.Model(&internal.ProductQuestion{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if spuID != "" {
+		query = query.Where("spu_id = ?", spuID)
+	}
+	if keyword != "" {
+		query = query.Where("content LIKE ?", "%"+keyword+"%")
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Preload("SPU", internal.WithDeleted).
+		Preload("Answers", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&questions).Error
+	return questions, total, err
+}
+
+// GetAnswers 获取回答列表（附带所属提问），用于回答审核
+func (s *AdminQuestionService) GetAnswers(status string, page, pageSize int) ([]internal.ProductAnswer, int64, error) {
+	answers := []internal.ProductAnswer{}
+	var total int64
+
+	query := s.db.Model(&internal.ProductAnswer{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Preload("Question").Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&answers).Error
+	return answers, total, err
+}
+
+// ReviewQuestion 审核提问
+func (s *AdminQuestionService) ReviewQuestion(id, status, reason string) error {
+	if !validQAStatus(status) {
+		return errors.New("无效的审核状态")
+	}
+	if status != internal.QAStatusRejected {
+		reason = ""
+	}
+	result := s.db.Model(&internal.ProductQuestion{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":        status,
+		"reject_reason": reason,
+		"updated_at":    time.Now().UnixMilli(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrQuestionNotFound
+	}
+	return nil
+}
+
+// ReviewAnswer 审核回答，并重新统计所属提问的回答数
+func (s *AdminQuestionService) ReviewAnswer(id, status, reason string) error {
+	if !validQAStatus(status) {
+		return errors.New("无效的审核状态")
+	}
+	if status != internal.QAStatusRejected {
+		reason = ""
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var answer internal.ProductAnswer
+		if err := tx.Where("id = ?", id).Limit(1).Find(&answer).Error; err != nil {
+			return err
+		}
+		if answer.ID == "" {
+			return ErrAnswerNotFound
+		}
+		err := tx.Model(&internal.ProductAnswer{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"status":        status,
+			"reject_reason": reason,
+			"updated_at":    time.Now().UnixMilli(),
+		}).Error
+		if err != nil {
+			return err
+		}
+		return internal.RefreshQuestionCounts(tx, answer.QuestionID)
+	})
+}
+
+// AnswerQuestion 商家回答提问，回答无需审核；提问尚未审核时一并通过
+func (s *AdminQuestionService) AnswerQuestion(questionID, adminID, content string) (*internal.ProductAnswer, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, errors.New("请填写回答内容")
+	}
+
+	var answer internal.ProductAnswer
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var question internal.ProductQuestion
+		if err := tx.Where("id = ?", questionID).Limit(1).Find(&question).Error; err != nil {
+			return err
+		}
+		if question.ID == "" {
+			return ErrQuestionNotFound
+		}
+
+		now := time.Now().UnixMilli()
+		if question.Status == internal.QAStatusPending {
+			err := tx.Model(&internal.ProductQuestion{}).Where("id = ?", questionID).
+				Updates(map[string]interface{}{"status": internal.QAStatusApproved, "updated_at": now}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		answer = internal.ProductAnswer{
+			ID:         internal.GenerateUUID(),
+			QuestionID: questionID,
+			SPUID:      question.SPUID,
+			AdminID:    adminID,
+			UserName:   OfficialAnswerName,
+			Content:    content,
+			IsOfficial: true,
+			Status:     internal.QAStatusApproved,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		if err := tx.Create(&answer).Error; err != nil {
+			return err
+		}
+		return internal.RefreshQuestionCounts(tx, questionID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &answer, nil
+}
+
+// DeleteQuestion 删除提问及其回答和投票
+func (s *AdminQuestionService) DeleteQuestion(id string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("answer_id IN (SELECT id FROM product_answer WHERE question_id = ?)", id).Delete(&internal.AnswerVote{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("question_id = ?", id).Delete(&internal.ProductAnswer{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", id).Delete(&internal.ProductQuestion{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrQuestionNotFound
+		}
+		return nil
+	})
+}
+
+// DeleteAnswer 删除回答及其投票，并重新统计所属提问的回答数
+func (s *AdminQuestionService) DeleteAnswer(id string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var answer internal.ProductAnswer
+		if err := tx.Where("id = ?", id).Limit(1).Find(&answer).Error; err != nil {
+			return err
+		}
+		if answer.ID == "" {
+			return ErrAnswerNotFound
+		}
+		if err := tx.Where("answer_id = ?", id).Delete(&internal.AnswerVote{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&answer).Error; err != nil {
+			return err
+		}
+		return internal.RefreshQuestionCounts(tx, answer.QuestionID)
+	})
+}
@@ -0,0 +1,346 @@
+package admin_services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"z26b-backend/internal"
+
+	"gorm.io/gorm"
+)
+
+// 回收站条目类型
+const (
+	RecycleTypeProduct  = "product"
+	RecycleTypeSKU      = "sku"
+	RecycleTypeCategory = "category"
+	RecycleTypeTag      = "tag"
+)
+
+// DefaultRecycleBinRetention 回收站默认保留时长，到期后自动彻底删除
+const DefaultRecycleBinRetention = 30 * 24 * time.Hour
+
+var (
+	ErrInvalidRecycleType  = errors.New("无效的回收站类型")
+	ErrRecycleItemNotFound = errors.New("回收站中不存在该记录")
+	ErrRestoreBlocked      = errors.New("无法恢复")
+	ErrPurgeBlocked        = errors.New("无法彻底删除")
+)
+
+// RecycleBinItem 回收站条目
+type RecycleBinItem struct {
+	Type      string    `json:"type"`
+	ID        string    `json:"_id"`
+	Name      string    `json:"name"`
+	ParentID  string    `json:"parentId,omitempty"` // SKU 所属商品ID / 上级分类ID
+	DeletedAt time.Time `json:"deletedAt"`
+	PurgeAt   time.Time `json:"purgeAt"` // 到期后自动彻底删除
+}
+
+// AdminRecycleBinService 管理后台回收站服务
+type AdminRecycleBinService struct {
+	db        *gorm.DB
+	retention time.Duration
+}
+
+// NewAdminRecycleBinService 创建回收站服务实例，retention 为删除后的保留时长
+func NewAdminRecycleBinService(db *gorm.DB, retention time.Duration) AdminRecycleBinServiceInterface {
+	if retention <= 0 {
+		retention = DefaultRecycleBinRetention
+	}
+	return &AdminRecycleBinService{db: db, retention: retention}
+}
+
+// GetItems 获取回收站列表，按删除时间倒序；随商品一起删除的 SKU 不单独列出
+func (s *AdminRecycleBinService) GetItems(itemType, keyword string, page, pageSize int) ([]RecycleBinItem, int64, error) {
+	var query *gorm.DB
+	switch itemType {
+	case RecycleTypeProduct:
+		query = s.db.Unscoped().Model(&internal.SPU{}).Select("id, name, '' AS parent_id, deleted_at")
+	case RecycleTypeSKU:
+		query = s.db.Unscoped().Model(&internal.SKU{}).Select(`id, description AS name, "SPUID" AS parent_id, deleted_at`).
+			Where(`"SPUID" IN (SELECT id FROM spu WHERE deleted_at IS NULL)`)
+	case RecycleTypeCategory:
+		query = s.db.Unscoped().Model(&internal.Category{}).Select("id, name, parent_id, deleted_at")
+	case RecycleTypeTag:
+		query = s.db.Unscoped().Model(&internal.Tag{}).Select("id, name, '' AS parent_id, deleted_at")
+	default:
+		return nil, 0, ErrInvalidRecycleType
+	}
+	query = query.Where("deleted_at IS NOT NULL")
+	if keyword != "" {
+		column := "name"
+		if itemType == RecycleTypeSKU {
+			column = "description"
+		}
+		query = query.Where(column+" LIKE ?", "%"+keyword+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []struct {
+		ID        string
+		Name      string
+		ParentID  string
+		DeletedAt time.Time
+	}
+	if err := query.Order("deleted_at DESC").Offset((page - 1) * pageSize).Limit(pageSize).Scan(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]RecycleBinItem, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, RecycleBinItem{
+			Type:      itemType,
+			ID:        row.ID,
+			Name:      row.Name,
+			ParentID:  row.ParentID,
+			DeletedAt: row.DeletedAt,
+			PurgeAt:   row.DeletedAt.Add(s.retention),
+		})
+	}
+	return items, total, nil
+}
+
+// Restore 从回收站恢复，返回需要同步搜索索引的商品ID
+func (s *AdminRecycleBinService) Restore(itemType, id string) ([]string, error) {
+	var spuIDs []string
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		switch itemType {
+		case RecycleTypeProduct:
+			var spu internal.SPU
+			if err := findDeleted(tx, &spu, id); err != nil {
+				return err
+			}
+			if spu.CategoryID != "" && isDeleted(tx, &internal.Category{}, spu.CategoryID) {
+				return fmt.Errorf("%w: 商品所属分类在回收站中，请先恢复分类", ErrRestoreBlocked)
+			}
+			// 一并恢复随商品删除的 SKU
+			if err := tx.Unscoped().Model(&internal.SKU{}).Where(`"SPUID" = ? AND deleted_at = ?`, id, spu.DeletedAt.Time).
+				UpdateColumn("deleted_at", nil).Error; err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Model(&spu).UpdateColumn("deleted_at", nil).Error; err != nil {
+				return err
+			}
+			spuIDs = []string{id}
+			return internal.UpdateSPUPriceRange(tx, id)
+
+		case RecycleTypeSKU:
+			var sku internal.SKU
+			if err := findDeleted(tx, &sku, id); err != nil {
+				return err
+			}
+			if isDeleted(tx, &internal.SPU{}, sku.SPUID) {
+				return fmt.Errorf("%w: 所属商品在回收站中，请先恢复商品", ErrRestoreBlocked)
+			}
+			if sku.SpecKey != "" {
+				var count int64
+				tx.Model(&internal.SKU{}).Where(`"SPUID" = ? AND spec_key = ?`, sku.SPUID, sku.SpecKey).Count(&count)
+				if count > 0 {
+					return fmt.Errorf("%w: 已存在相同规格组合的 SKU", ErrRestoreBlocked)
+				}
+			}
+			if err := tx.Unscoped().Model(&sku).UpdateColumn("deleted_at", nil).Error; err != nil {
+				return err
+			}
+			spuIDs = []string{sku.SPUID}
+			return internal.UpdateSPUPriceRange(tx, sku.SPUID)
+
+		case RecycleTypeCategory:
+			var category internal.Category
+			if err := findDeleted(tx, &category, id); err != nil {
+				return err
+			}
+			if category.ParentID != "" && isDeleted(tx, &internal.Category{}, category.ParentID) {
+				return fmt.Errorf("%w: 上级分类在回收站中，请先恢复上级分类", ErrRestoreBlocked)
+			}
+			if err := tx.Unscoped().Model(&category).UpdateColumn("deleted_at", nil).Error; err != nil {
+				return err
+			}
+			return tx.Model(&internal.SPU{}).Where("category_id = ?", id).Pluck("id", &spuIDs).Error
+
+		case RecycleTypeTag:
+			var tag internal.Tag
+			if err := findDeleted(tx, &tag, id); err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Model(&tag).UpdateColumn("deleted_at", nil).Error; err != nil {
+				return err
+			}
+			return tx.Model(&internal.SPUTag{}).Where("tag_id = ? AND spu_id IN (SELECT id FROM spu WHERE deleted_at IS NULL)", id).
+				Distinct().Pluck("spu_id", &spuIDs).Error
+		}
+		return ErrInvalidRecycleType
+	})
+	if err != nil {
+		return nil, err
+	}
+	return spuIDs, nil
+}
+
+// Purge 彻底删除回收站中的记录；被历史订单或组合商品引用的 SKU 及其商品不可彻底删除
+func (s *AdminRecycleBinService) Purge(itemType, id string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return purge(tx, itemType, id)
+	})
+}
+
+// PurgeExpired 彻底删除超过保留时长的记录，无法删除的记录跳过，返回删除数量
+func (s *AdminRecycleBinService) PurgeExpired(now time.Time) (int, error) {
+	before := now.Add(-s.retention)
+	purged := 0
+	// 先删除商品和 SKU，分类才可能不再被引用
+	for _, item := range []struct {
+		itemType string
+		model    interface{}
+	}{
+		{RecycleTypeSKU, &internal.SKU{}},
+		{RecycleTypeProduct, &internal.SPU{}},
+		{RecycleTypeTag, &internal.Tag{}},
+		{RecycleTypeCategory, &internal.Category{}},
+	} {
+		var ids []string
+		if err := s.db.Unscoped().Model(item.model).Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+			Order("deleted_at ASC").Pluck("id", &ids).Error; err != nil {
+			return purged, err
+		}
+		for _, id := range ids {
+			err := s.Purge(item.itemType, id)
+			if errors.Is(err, ErrPurgeBlocked) || errors.Is(err, ErrRecycleItemNotFound) {
+				continue
+			}
+			if err != nil {
+				return purged, err
+			}
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func purge(tx *gorm.DB, itemType, id string) error {
+	switch itemType {
+	case RecycleTypeProduct:
+		var spu internal.SPU
+		if err := findDeleted(tx, &spu, id); err != nil {
+			return err
+		}
+		var skuIDs []string
+		if err := tx.Unscoped().Model(&internal.SKU{}).Where(`"SPUID" = ?`, id).Pluck("id", &skuIDs).Error; err != nil {
+			return err
+		}
+		if referenced(tx, skuIDs) {
+			return fmt.Errorf("%w: 商品存在历史订单或组合商品引用", ErrPurgeBlocked)
+		}
+		// 先删除评价图片和回答投票，它们通过评价、回答关联到商品
+		if err := tx.Where("comment_id IN (?)", tx.Unscoped().Model(&internal.Comment{}).Select("id").Where("spu_id = ?", id)).
+			Delete(&internal.ReviewMedia{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("answer_id IN (?)", tx.Unscoped().Model(&internal.ProductAnswer{}).Select("id").Where("spu_id = ?", id)).
+			Delete(&internal.AnswerVote{}).Error; err != nil {
+			return err
+		}
+		for _, model := range []interface{}{&internal.SPUTag{}, &internal.Comment{}, &internal.RecommendedProduct{},
+			&internal.ProductStats{}, &internal.ProductRevision{}, &internal.SearchDocument{}, &internal.Favorite{},
+			&internal.BrowseHistory{}, &internal.ProductQuestion{}, &internal.ProductAnswer{}} {
+			if err := tx.Unscoped().Where("spu_id = ?", id).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("spu_id = ? OR related_spu_id = ?", id, id).Delete(&internal.ProductSimilarity{}).Error; err != nil {
+			return err
+		}
+		if err := purgeSKUs(tx, skuIDs); err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&spu).Error
+
+	case RecycleTypeSKU:
+		var sku internal.SKU
+		if err := findDeleted(tx, &sku, id); err != nil {
+			return err
+		}
+		if referenced(tx, []string{id}) {
+			return fmt.Errorf("%w: SKU 存在历史订单或组合商品引用", ErrPurgeBlocked)
+		}
+		return purgeSKUs(tx, []string{id})
+
+	case RecycleTypeCategory:
+		var category internal.Category
+		if err := findDeleted(tx, &category, id); err != nil {
+			return err
+		}
+		var count int64
+		tx.Unscoped().Model(&internal.Category{}).Where("parent_id = ?", id).Count(&count)
+		if count > 0 {
+			return fmt.Errorf("%w: 分类下有子分类", ErrPurgeBlocked)
+		}
+		tx.Unscoped().Model(&internal.SPU{}).Where("category_id = ?", id).Count(&count)
+		if count > 0 {
+			return fmt.Errorf("%w: 分类下有商品（含回收站中的商品）", ErrPurgeBlocked)
+		}
+		return tx.Unscoped().Delete(&category).Error
+
+	case RecycleTypeTag:
+		var tag internal.Tag
+		if err := findDeleted(tx, &tag, id); err != nil {
+			return err
+		}
+		if err := tx.Where("tag_id = ?", id).Delete(&internal.SPUTag{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&tag).Error
+	}
+	return ErrInvalidRecycleType
+}
+
+// findDeleted 查找回收站中的记录
+func findDeleted(tx *gorm.DB, dest interface{}, id string) error {
+	err := tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(dest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrRecycleItemNotFound
+	}
+	return err
+}
+
+// isDeleted 判断记录是否在回收站中
+func isDeleted(tx *gorm.DB, model interface{}, id string) bool {
+	var count int64
+	tx.Unscoped().Model(model).Where("id = ? AND deleted_at IS NOT NULL", id).Count(&count)
+	return count > 0
+}
+
+// referenced 判断 SKU 是否被订单、组合商品或分销佣金记录引用
+func referenced(tx *gorm.DB, skuIDs []string) bool {
+	if len(skuIDs) == 0 {
+		return false
+	}
+	for _, model := range []interface{}{&internal.OrderItem{}, &internal.BundleItem{}, &internal.CommissionRecord{}} {
+		var count int64
+		tx.Model(model).Where("sku_id IN ?", skuIDs).Count(&count)
+		if count > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// purgeSKUs 彻底删除 SKU 及购物车中的对应商品、价格历史、库存流水、预警和仓库库存
+func purgeSKUs(tx *gorm.DB, skuIDs []string) error {
+	if len(skuIDs) == 0 {
+		return nil
+	}
+	for _, model := range []interface{}{&internal.CartItem{}, &internal.SKUPriceHistory{}, &internal.StockMovement{},
+		&internal.StockAlert{}, &internal.WarehouseStock{}} {
+		if err := tx.Where("sku_id IN ?", skuIDs).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Unscoped().Where("id IN ?", skuIDs).Delete(&internal.SKU{}).Error
+}
@@ -0,0 +1,130 @@
+package admin_services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"z26b-backend/internal"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB 创建独立的内存 SQLite 数据库并迁移指定模型
+func newTestDB(t *testing.T, models ...interface{}) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// seedDeletedProduct 创建回收站中的商品及其 SKU 和各类关联记录
+func seedDeletedProduct(t *testing.T, db *gorm.DB, spuID, skuID string) {
+	t.Helper()
+	records := []interface{}{
+		&internal.SPU{ID: spuID, Name: spuID},
+		&internal.SKU{ID: skuID, SPUID: spuID},
+		&internal.Comment{ID: "c-" + spuID, SPUID: spuID, SKUID: skuID},
+		&internal.ReviewMedia{ID: "m-" + spuID, CommentID: "c-" + spuID},
+		&internal.Favorite{ID: "f-" + spuID, UserID: "u1", SPUID: spuID},
+		&internal.BrowseHistory{ID: "b-" + spuID, UserID: "u1", SPUID: spuID},
+		&internal.ProductSimilarity{ID: "s-" + spuID, SPUID: spuID, RelatedSPUID: "other"},
+		&internal.ProductSimilarity{ID: "r-" + spuID, SPUID: "other", RelatedSPUID: spuID},
+		&internal.ProductQuestion{ID: "q-" + spuID, SPUID: spuID},
+		&internal.ProductAnswer{ID: "a-" + spuID, QuestionID: "q-" + spuID, SPUID: spuID},
+		&internal.AnswerVote{ID: "v-" + spuID, AnswerID: "a-" + spuID, UserID: "u1"},
+		&internal.CartItem{ID: "ci-" + skuID, UserID: "u1", SKUID: skuID},
+		&internal.SKUPriceHistory{ID: "p-" + skuID, SKUID: skuID, SPUID: spuID},
+		&internal.StockMovement{ID: "sm-" + skuID, SKUID: skuID, SPUID: spuID},
+		&internal.StockAlert{ID: "sa-" + skuID, SKUID: skuID, SPUID: spuID},
+		&internal.WarehouseStock{ID: "ws-" + skuID, WarehouseID: "w1", SKUID: skuID},
+	}
+	for _, record := range records {
+		if err := db.Create(record).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Now()
+	db.Model(&internal.SKU{}).Where("id = ?", skuID).UpdateColumn("deleted_at", now)
+	db.Model(&internal.SPU{}).Where("id = ?", spuID).UpdateColumn("deleted_at", now)
+}
+
+func TestPurge(t *testing.T) {
+	tests := []struct {
+		name      string
+		itemType  string
+		reference interface{}
+		blocked   bool
+	}{
+		{"商品无引用", RecycleTypeProduct, nil, false},
+		{"SKU无引用", RecycleTypeSKU, nil, false},
+		{"商品被订单引用", RecycleTypeProduct, &internal.OrderItem{ID: "oi", SKUID: "k1"}, true},
+		{"SKU被组合商品引用", RecycleTypeSKU, &internal.BundleItem{ID: "bi", SKUID: "k1"}, true},
+		{"SKU被佣金记录引用", RecycleTypeSKU, &internal.CommissionRecord{ID: "cr", SKUID: "k1"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t, &internal.SPU{}, &internal.SKU{}, &internal.SPUTag{}, &internal.Comment{}, &internal.ReviewMedia{},
+				&internal.RecommendedProduct{}, &internal.ProductStats{}, &internal.ProductRevision{}, &internal.SearchDocument{},
+				&internal.Favorite{}, &internal.BrowseHistory{}, &internal.ProductSimilarity{}, &internal.ProductQuestion{},
+				&internal.ProductAnswer{}, &internal.AnswerVote{}, &internal.CartItem{}, &internal.SKUPriceHistory{},
+				&internal.StockMovement{}, &internal.StockAlert{}, &internal.WarehouseStock{}, &internal.OrderItem{},
+				&internal.BundleItem{}, &internal.CommissionRecord{})
+			seedDeletedProduct(t, db, "p1", "k1")
+			if tt.reference != nil {
+				if err := db.Create(tt.reference).Error; err != nil {
+					t.Fatal(err)
+				}
+			}
+			id := "p1"
+			if tt.itemType == RecycleTypeSKU {
+				id = "k1"
+			}
+
+			err := NewAdminRecycleBinService(db, 0).Purge(tt.itemType, id)
+			if tt.blocked {
+				if !errors.Is(err, ErrPurgeBlocked) {
+					t.Fatalf("Purge() error = %v, want ErrPurgeBlocked", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Purge() error = %v", err)
+			}
+
+			// SKU 相关记录总是删除，商品相关记录只在彻底删除商品时删除
+			skuModels := []interface{}{&internal.SKU{}, &internal.CartItem{}, &internal.SKUPriceHistory{},
+				&internal.StockMovement{}, &internal.StockAlert{}, &internal.WarehouseStock{}}
+			spuModels := []interface{}{&internal.SPU{}, &internal.Comment{}, &internal.ReviewMedia{}, &internal.Favorite{},
+				&internal.BrowseHistory{}, &internal.ProductSimilarity{}, &internal.ProductQuestion{},
+				&internal.ProductAnswer{}, &internal.AnswerVote{}}
+			for _, model := range skuModels {
+				if n := countRows(db, model); n != 0 {
+					t.Errorf("%T rows = %d, want 0", model, n)
+				}
+			}
+			for _, model := range spuModels {
+				n := countRows(db, model)
+				if tt.itemType == RecycleTypeProduct && n != 0 {
+					t.Errorf("%T rows = %d, want 0", model, n)
+				}
+				if tt.itemType == RecycleTypeSKU && n == 0 {
+					t.Errorf("%T rows deleted with SKU", model)
+				}
+			}
+		})
+	}
+}
+
+func countRows(db *gorm.DB, model interface{}) int64 {
+	var n int64
+	db.Unscoped().Model(model).Count(&n)
+	return n
+}
@@ -0,0 +1,237 @@
+package admin_services
+
+import (
+	"errors"
+	"time"
+
+	"z26b-backend/internal"
+	"z26b-backend/services/inventory"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrWarehouseNotFound = errors.New("仓库不存在")
+	ErrShipmentNotFound  = errors.New("发货单不存在")
+	ErrOrderNotFound     = errors.New("订单不存在")
+)
+
+type AdminWarehouseService struct {
+	db *gorm.DB
+}
+
+func NewAdminWarehouseService(db *gorm.DB) AdminWarehouseServiceInterface {
+	return &AdminWarehouseService{db: db}
+}
+
+// GetWarehouses 获取仓库列表，按优先级排序
+func (s *AdminWarehouseService) GetWarehouses(status string) ([]internal.Warehouse, error) {
+	warehouses := []internal.Warehouse{}
+	query := s.db.Model(&internal.Warehouse{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("priority DESC, created_at ASC").Find(&warehouses).Error
+	return warehouses, err
+}
+
+// CreateWarehouse 创建仓库，编码不能重复；创建第一个仓库时按现有总库存初始化其库存
+func (s *AdminWarehouseService) CreateWarehouse(warehouse *internal.Warehouse) error {
+	if warehouse.Name == "" || warehouse.Code == "" {
+		return errors.New("请填写仓库名称和编码")
+	}
+	var count int64
+	if err := s.db.Model(&internal.Warehouse{}).Where("code = ?", warehouse.Code).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("仓库编码已存在")
+	}
+
+	now := time.Now().UnixMilli()
+	warehouse.ID = internal.GenerateUUID()
+	if warehouse.Status == "" {
+		warehouse.Status = "ENABLED"
+	}
+	if warehouse.Regions == nil {
+		warehouse.Regions = internal.ToJSON([]string{})
+	}
+	warehouse.CreatedAt = now
+	warehouse.UpdatedAt = now
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var existing int64
+		if err := tx.Model(&internal.Warehouse{}).Count(&existing).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(warehouse).Error; err != nil {
+			return err
+		}
+		// 第一个仓库为默认仓库，按现有总库存初始化仓库库存
+		if existing > 0 {
+			return nil
+		}
+		_, err := inventory.NewInventoryService(tx).SeedWarehouse(warehouse.ID)
+		return err
+	})
+}
+
+// UpdateWarehouse 更新仓库
+func (s *AdminWarehouseService) UpdateWarehouse(id string, updates map[string]interface{}) error {
+	if code, ok := updates["code"].(string); ok {
+		var count int64
+		if err := s.db.Model(&internal.Warehouse{}).Where("code = ? AND id <> ?", code, id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return errors.New("仓库编码已存在")
+		}
+	}
+
+	updates["updated_at"] = time.Now().UnixMilli()
+	result := s.db.Model(&internal.Warehouse{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrWarehouseNotFound
+	}
+	return nil
+}
+
+// DeleteWarehouse 删除仓库；仍有库存或已有发货单的仓库只能停用
+func (s *AdminWarehouseService) DeleteWarehouse(id string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&internal.WarehouseStock{}).Where("warehouse_id = ? AND count > 0", id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return errors.New("仓库仍有库存，无法删除")
+		}
+		if err := tx.Model(&internal.OrderShipment{}).Where("warehouse_id = ?", id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return errors.New("仓库已有发货记录，请停用仓库")
+		}
+
+		if err := tx.Where("warehouse_id = ?", id).Delete(&internal.WarehouseStock{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", id).Delete(&internal.Warehouse{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrWarehouseNotFound
+		}
+		return nil
+	})
+}
+
+// GetShipments 仓库发货队列，按下单时间先后排序；status 为空时返回全部
+func (s *AdminWarehouseService) GetShipments(warehouseID, status string, page, pageSize int) ([]internal.OrderShipment, int64, error) {
+	var shipments []internal.OrderShipment
+	var total int64
+
+	query := s.db.Model(&internal.OrderShipment{}).Where("warehouse_id = ?", warehouseID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Preload("Order").Preload("Items.SKU", internal.WithDeleted).Preload("Items.SKU.SPU", internal.WithDeleted).
+		Order("created_at ASC").Offset(offset).Limit(pageSize).Find(&shipments).Error
+	return shipments, total, err
+}
+
+// ShipShipment 仓库发货单发货；订单的发货单全部发出后订单变为待收货
+func (s *AdminWarehouseService) ShipShipment(id, trackingNo, adminID string) (*internal.OrderShipment, error) {
+	var shipment internal.OrderShipment
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&shipment, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrShipmentNotFound
+			}
+			return err
+		}
+		if shipment.Status != internal.ShipmentStatusPending {
+			return errors.New("发货单已发货")
+		}
+
+		var order internal.Order
+		if err := tx.First(&order, "id = ?", shipment.OrderID).Error; err != nil {
+			return err
+		}
+		if order.Status != internal.OrderStatusToSend {
+			return errors.New("订单状态不正确，无法发货")
+		}
+
+		now := time.Now().UnixMilli()
+		shipment.Status = internal.ShipmentStatusShipped
+		shipment.TrackingNo = trackingNo
+		shipment.ShippedAt = &now
+		shipment.ShippedBy = adminID
+		shipment.UpdatedAt = now
+		if err := tx.Save(&shipment).Error; err != nil {
+			return err
+		}
+
+		var pending int64
+		if err := tx.Model(&internal.OrderShipment{}).
+			Where("order_id = ? AND status = ?", shipment.OrderID, internal.ShipmentStatusPending).
+			Count(&pending).Error; err != nil {
+			return err
+		}
+		if pending > 0 {
+			return nil
+		}
+		return tx.Model(&internal.Order{}).Where("id = ?", shipment.OrderID).Updates(map[string]interface{}{
+			"status":     internal.OrderStatusToReceive,
+			"updated_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &shipment, nil
+}
+
+// ShipOrder 整单发货：待发货订单变为待收货，未发出的发货单一并标记为已发货
+func (s *AdminWarehouseService) ShipOrder(orderID, adminID string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var order internal.Order
+		if err := tx.Select("id", "status").First(&order, "id = ?", orderID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrOrderNotFound
+			}
+			return err
+		}
+
+		// 条件更新，避免与并发的取消、退款同时生效
+		now := time.Now().UnixMilli()
+		result := tx.Model(&internal.Order{}).Where("id = ? AND status = ?", orderID, internal.OrderStatusToSend).
+			Updates(map[string]interface{}{
+				"status":     internal.OrderStatusToReceive,
+				"updated_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("订单状态不正确，无法发货")
+		}
+
+		return tx.Model(&internal.OrderShipment{}).
+			Where("order_id = ? AND status = ?", orderID, internal.ShipmentStatusPending).
+			Updates(map[string]interface{}{
+				"status":     internal.ShipmentStatusShipped,
+				"shipped_at": now,
+				"shipped_by": adminID,
+				"updated_at": now,
+			}).Error
+	})
+}
@@ -14,14 +14,75 @@ type AdminGoodsServiceInterface interface {
 	UpdateProduct(id string, updates map[string]interface{}) error
 	DeleteProduct(id string) error
 	UpdateProductStatus(id, status string) error
+	ScheduleProduct(id string, publishAt, unpublishAt *int64, adminID string) error
+	SaveSpecs(spuID string, specs []internal.SpecDimension) error
+	GenerateSKUMatrix(spuID string, specs []internal.SpecDimension, price float64, count int, adminID string) (*SKUMatrixResult, error)
+	ValidateSKUSpec(spuID string, values []string, excludeSKUID string) error
+	GetPriceHistory(skuID string, page, pageSize int) ([]internal.SKUPriceHistory, int64, error)
+}
+
+// AdminBundleService 管理后台组合商品服务接口
+type AdminBundleServiceInterface interface {
+	GetBundles(page, pageSize int, keyword, status string) ([]internal.Bundle, int64, error)
+	GetBundle(id string) (*internal.Bundle, error)
+	CreateBundle(bundle *internal.Bundle) error
+	UpdateBundle(id string, updates map[string]interface{}, items []internal.BundleItem) error
+	DeleteBundle(id string) error
+	UpdateBundleStatus(id, status string) error
+}
+
+// AdminCatalogService 管理后台商品批量导入导出服务接口
+type AdminCatalogServiceInterface interface {
+	ImportCatalog(rows [][]string, dryRun bool, adminID string) (*CatalogImportResult, error)
+	ExportCatalog() ([][]string, error)
+}
+
+// AdminRecycleBinService 管理后台回收站服务接口
+type AdminRecycleBinServiceInterface interface {
+	GetItems(itemType, keyword string, page, pageSize int) ([]RecycleBinItem, int64, error)
+	Restore(itemType, id string) ([]string, error)
+	Purge(itemType, id string) error
+	PurgeExpired(now time.Time) (int, error)
+}
+
+// AdminWarehouseService 管理后台仓库服务接口
+type AdminWarehouseServiceInterface interface {
+	GetWarehouses(status string) ([]internal.Warehouse, error)
+	CreateWarehouse(warehouse *internal.Warehouse) error
+	UpdateWarehouse(id string, updates map[string]interface{}) error
+	DeleteWarehouse(id string) error
+	GetShipments(warehouseID, status string, page, pageSize int) ([]internal.OrderShipment, int64, error)
+	ShipShipment(id, trackingNo, adminID string) (*internal.OrderShipment, error)
+	ShipOrder(orderID, adminID string) error
+}
+
+// AdminQuestionService 管理后台商品问答服务接口
+type AdminQuestionServiceInterface interface {
+	GetQuestions(status, spuID, keyword string, page, pageSize int) ([]internal.ProductQuestion, int64, error)
+	GetAnswers(status string, page, pageSize int) ([]internal.ProductAnswer, int64, error)
+	ReviewQuestion(id, status, reason string) error
+	ReviewAnswer(id, status, reason string) error
+	AnswerQuestion(questionID, adminID, content string) (*internal.ProductAnswer, error)
+	DeleteQuestion(id string) error
+	DeleteAnswer(id string) error
+}
+
+// AdminCommentService 管理后台评论审核服务接口
+type AdminCommentServiceInterface interface {
+	GetComments(q AdminCommentQuery, page, pageSize int) ([]internal.Comment, int64, error)
+	BatchComments(ids []string, action, reason, adminID string) (int64, error)
+	ReplyComment(id, content, adminID string) (*internal.Comment, error)
+	DeleteReply(id string) error
 }
 
 // AdminCategoryService 管理后台分类服务接口
 type AdminCategoryServiceInterface interface {
 	GetCategories() ([]internal.Category, error)
+	GetCategoryTree() ([]internal.Category, error)
 	GetCategory(id string) (*internal.Category, error)
 	CreateCategory(req *internal.Category) error
 	UpdateCategory(id string, updates map[string]interface{}) error
+	MoveCategory(id, parentID string, sort *int) error
 	DeleteCategory(id string) error
 }
 
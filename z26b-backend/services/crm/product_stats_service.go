@@ -114,6 +114,11 @@ func (s *ProductStatsService) GetProductOverview() (map[string]interface{}, erro
 	s.db.Model(&internal.ProductStats{}).Select("COALESCE(SUM(total_revenue), 0)").Scan(&totalRevenue)
 	overview["totalRevenue"] = totalRevenue
 
+	// 总收藏数
+	var totalFavorites int64
+	s.db.Model(&internal.ProductStats{}).Select("COALESCE(SUM(total_favorites), 0)").Scan(&totalFavorites)
+	overview["totalFavorites"] = totalFavorites
+
 	// 平均转化率
 	var avgConversionRate float64
 	s.db.Model(&internal.ProductStats{}).Select("COALESCE(AVG(conversion_rate), 0)").Scan(&avgConversionRate)
@@ -178,6 +183,11 @@ func (s *ProductStatsService) RefreshProductStats(spuID string) error {
 	s.db.Model(&internal.CRMEvent{}).Where("spu_id = ? AND event_type = ?", spuID, internal.CRMEventTypeCart).Count(&cartCount)
 	stats.TotalCarts = int(cartCount)
 
+	// 统计当前收藏数
+	var favoriteCount int64
+	s.db.Model(&internal.Favorite{}).Where("spu_id = ?", spuID).Count(&favoriteCount)
+	stats.TotalFavorites = int(favoriteCount)
+
 	// 从订单项统计销量和营收
 	var salesStats struct {
 		TotalSales   int
@@ -206,13 +216,13 @@ func (s *ProductStatsService) RefreshProductStats(spuID string) error {
 	stats.TotalRefunds = refundStats.TotalRefunds
 	stats.RefundAmount = refundStats.RefundAmount
 
-	// 统计评论数和平均评分
+	// 统计审核通过的评论数和平均评分
 	var commentStats struct {
 		TotalComments int64
 		AvgScore      float64
 	}
 	s.db.Model(&internal.Comment{}).
-		Where("spu_id = ?", spuID).
+		Where("spu_id = ? AND status = ?", spuID, internal.CommentStatusApproved).
 		Select("COUNT(*) as total_comments, COALESCE(AVG(comment_score), 0) as avg_score").
 		Scan(&commentStats)
 	stats.TotalComments = int(commentStats.TotalComments)
@@ -0,0 +1,354 @@
+package distribution
+
+import (
+	"errors"
+	"time"
+
+	"z26b-backend/internal"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CommissionService 分销佣金服务
+type CommissionService struct {
+	db *gorm.DB
+}
+
+// NewCommissionService 创建分销佣金服务实例
+func NewCommissionService(db *gorm.DB) *CommissionService {
+	return &CommissionService{db: db}
+}
+
+// WithTx 返回绑定到指定事务的服务实例，用于与订单状态更新共用事务
+func (s *CommissionService) WithTx(tx *gorm.DB) *CommissionService {
+	return &CommissionService{db: tx}
+}
+
+// GetRules 获取佣金规则列表
+func (s *CommissionService) GetRules(scope string) ([]internal.CommissionRule, error) {
+	var rules []internal.CommissionRule
+	query := s.db.Model(&internal.CommissionRule{})
+	if scope != "" {
+		query = query.Where("scope = ?", scope)
+	}
+	err := query.Order("created_at DESC").Find(&rules).Error
+	return rules, err
+}
+
+// CreateRule 创建佣金规则
+func (s *CommissionService) CreateRule(rule *internal.CommissionRule) error {
+	if rule.Scope != internal.CommissionScopeSPU && rule.Scope != internal.CommissionScopeCategory {
+		return errors.New("无效的规则范围")
+	}
+	if rule.TargetID == "" {
+		return errors.New("请选择商品或分类")
+	}
+	if rule.Rate <= 0 || rule.Rate > 1 {
+		return errors.New("佣金比例必须在 0-1 之间")
+	}
+
+	var count int64
+	s.db.Model(&internal.CommissionRule{}).Where("scope = ? AND target_id = ?", rule.Scope, rule.TargetID).Count(&count)
+	if count > 0 {
+		return errors.New("该商品或分类已存在佣金规则")
+	}
+
+	rule.ID = uuid.New().String()
+	if rule.Status == "" {
+		rule.Status = "active"
+	}
+	rule.CreatedAt = time.Now()
+	rule.UpdatedAt = time.Now()
+	return s.db.Create(rule).Error
+}
+
+// UpdateRule 更新佣金规则
+func (s *CommissionService) UpdateRule(id string, updates map[string]interface{}) error {
+	if rate, ok := updates["rate"].(float64); ok && (rate <= 0 || rate > 1) {
+		return errors.New("佣金比例必须在 0-1 之间")
+	}
+	updates["updated_at"] = time.Now()
+	result := s.db.Model(&internal.CommissionRule{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteRule 删除佣金规则（已生成的佣金记录不受影响）
+func (s *CommissionService) DeleteRule(id string) error {
+	return s.db.Delete(&internal.CommissionRule{}, "id = ?", id).Error
+}
+
+// ResolveRule 获取商品适用的佣金规则（商品规则优先于分类规则）
+func (s *CommissionService) ResolveRule(spuID, categoryID string) (*internal.CommissionRule, error) {
+	var rule internal.CommissionRule
+	err := s.db.Where("scope = ? AND target_id = ? AND status = ?", internal.CommissionScopeSPU, spuID, "active").First(&rule).Error
+	if err == nil {
+		return &rule, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if categoryID == "" {
+		return nil, nil
+	}
+	err = s.db.Where("scope = ? AND target_id = ? AND status = ?", internal.CommissionScopeCategory, categoryID, "active").First(&rule).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// CreateOrderCommissions 订单完成时生成佣金记录，重复调用不会重复生成；应与订单状态更新在同一事务中调用
+func (s *CommissionService) CreateOrderCommissions(orderID string) (int, error) {
+	var order internal.Order
+	if err := s.db.Preload("Items.SKU", internal.WithDeleted).Preload("Items.SKU.SPU", internal.WithDeleted).First(&order, "id = ?", orderID).Error; err != nil {
+		return 0, err
+	}
+	if order.Status != internal.OrderStatusFinished {
+		return 0, nil
+	}
+
+	var buyer internal.User
+	if err := s.db.First(&buyer, "id = ?", order.UserID).Error; err != nil {
+		return 0, err
+	}
+	if buyer.ReferrerID == "" || buyer.ReferrerID == buyer.ID {
+		return 0, nil
+	}
+
+	var existing []string
+	if err := s.db.Model(&internal.CommissionRecord{}).Where("order_id = ?", orderID).Pluck("order_item_id", &existing).Error; err != nil {
+		return 0, err
+	}
+	done := make(map[string]bool, len(existing))
+	for _, id := range existing {
+		done[id] = true
+	}
+
+	now := time.Now().UnixMilli()
+	var records []internal.CommissionRecord
+	for _, item := range order.Items {
+		if done[item.ID] || item.SKU == nil || item.SKU.SPU == nil {
+			continue
+		}
+		rule, err := s.ResolveRule(item.SKU.SPUID, item.SKU.SPU.CategoryID)
+		if err != nil {
+			return 0, err
+		}
+		if rule == nil {
+			continue
+		}
+
+		base := internal.RoundMoney(item.Price * float64(item.Quantity))
+		amount := internal.RoundMoney(base * rule.Rate)
+		if amount <= 0 {
+			continue
+		}
+		records = append(records, internal.CommissionRecord{
+			ID:          uuid.New().String(),
+			ReferrerID:  buyer.ReferrerID,
+			UserID:      buyer.ID,
+			OrderID:     order.ID,
+			OrderItemID: item.ID,
+			SPUID:       item.SKU.SPUID,
+			SKUID:       item.SKUID,
+			RuleID:      rule.ID,
+			BaseAmount:  base,
+			Rate:        rule.Rate,
+			Amount:      amount,
+			Status:      internal.CommissionStatusPending,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+	}
+
+	if len(records) == 0 {
+		return 0, nil
+	}
+	// 并发生成时由 (order_id, order_item_id) 唯一索引去重
+	result := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&records)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return int(result.RowsAffected), nil
+}
+
+// ReverseOrderCommissions 订单退款时冲正佣金记录（包括已结算的）
+func (s *CommissionService) ReverseOrderCommissions(orderID string) (int64, error) {
+	now := time.Now().UnixMilli()
+	result := s.db.Model(&internal.CommissionRecord{}).
+		Where("order_id = ? AND status IN ?", orderID, []string{internal.CommissionStatusPending, internal.CommissionStatusSettled}).
+		Updates(map[string]interface{}{
+			"status":      internal.CommissionStatusReversed,
+			"reversed_at": now,
+			"updated_at":  now,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// GetCommissions 获取佣金记录列表
+func (s *CommissionService) GetCommissions(referrerID, status string, page, pageSize int) ([]internal.CommissionRecord, int64, error) {
+	var records []internal.CommissionRecord
+	var total int64
+
+	query := s.db.Model(&internal.CommissionRecord{})
+	if referrerID != "" {
+		query = query.Where("referrer_id = ?", referrerID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Preload("Referrer").Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&records).Error
+	return records, total, err
+}
+
+// SettleCommissions 结算待结算佣金，ids 为空时结算该推荐人的全部待结算佣金
+func (s *CommissionService) SettleCommissions(ids []string, referrerID string) (int64, error) {
+	if len(ids) == 0 && referrerID == "" {
+		return 0, errors.New("请选择要结算的佣金")
+	}
+
+	query := s.db.Model(&internal.CommissionRecord{}).Where("status = ?", internal.CommissionStatusPending)
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+	if referrerID != "" {
+		query = query.Where("referrer_id = ?", referrerID)
+	}
+
+	now := time.Now().UnixMilli()
+	result := query.Updates(map[string]interface{}{
+		"status":     internal.CommissionStatusSettled,
+		"settled_at": now,
+		"updated_at": now,
+	})
+	return result.RowsAffected, result.Error
+}
+
+// referrerTotals 推荐人佣金汇总
+type referrerTotals struct {
+	ReferrerID     string
+	PendingAmount  float64
+	SettledAmount  float64
+	ReversedAmount float64
+	OrderCount     int64
+}
+
+// sumByStatusSQL 按状态汇总佣金金额
+const sumByStatusSQL = `referrer_id,
+	COALESCE(SUM(CASE WHEN status = 'pending' THEN amount ELSE 0 END), 0) as pending_amount,
+	COALESCE(SUM(CASE WHEN status = 'settled' THEN amount ELSE 0 END), 0) as settled_amount,
+	COALESCE(SUM(CASE WHEN status = 'reversed' THEN amount ELSE 0 END), 0) as reversed_amount,
+	COUNT(DISTINCT order_id) as order_count`
+
+// GetReferrerReport 按推荐人汇总待结算/已结算佣金
+func (s *CommissionService) GetReferrerReport(page, pageSize int) ([]map[string]interface{}, int64, error) {
+	var total int64
+	if err := s.db.Model(&internal.CommissionRecord{}).Distinct("referrer_id").Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []referrerTotals
+	offset := (page - 1) * pageSize
+	err := s.db.Model(&internal.CommissionRecord{}).
+		Select(sumByStatusSQL).
+		Group("referrer_id").
+		Order("pending_amount DESC").
+		Offset(offset).Limit(pageSize).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 邀请人数和推荐人信息按当前页批量查询
+	ids := make([]string, 0, len(rows))
+	for _, r := range rows {
+		ids = append(ids, r.ReferrerID)
+	}
+	var invitedRows []struct {
+		ReferrerID string
+		Invited    int64
+	}
+	err = s.db.Model(&internal.User{}).
+		Select("referrer_id, COUNT(*) as invited").
+		Where("referrer_id IN ?", ids).
+		Group("referrer_id").
+		Scan(&invitedRows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	invited := make(map[string]int64, len(invitedRows))
+	for _, r := range invitedRows {
+		invited[r.ReferrerID] = r.Invited
+	}
+	var users []internal.User
+	if err := s.db.Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	userByID := make(map[string]internal.User, len(users))
+	for _, u := range users {
+		userByID[u.ID] = u
+	}
+
+	var report []map[string]interface{}
+	for _, r := range rows {
+		item := map[string]interface{}{
+			"referrerId":     r.ReferrerID,
+			"pendingAmount":  internal.RoundMoney(r.PendingAmount),
+			"settledAmount":  internal.RoundMoney(r.SettledAmount),
+			"reversedAmount": internal.RoundMoney(r.ReversedAmount),
+			"orderCount":     r.OrderCount,
+			"invitedCount":   invited[r.ReferrerID],
+		}
+		if user, ok := userByID[r.ReferrerID]; ok {
+			item["referrer"] = user
+		}
+		report = append(report, item)
+	}
+
+	return report, total, nil
+}
+
+// GetReferralSummary 获取用户的推广概况
+func (s *CommissionService) GetReferralSummary(userID string) (map[string]interface{}, error) {
+	var user internal.User
+	if err := s.db.First(&user, "id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+
+	var invited int64
+	s.db.Model(&internal.User{}).Where("referrer_id = ?", userID).Count(&invited)
+
+	var totals referrerTotals
+	s.db.Model(&internal.CommissionRecord{}).
+		Select(sumByStatusSQL).
+		Where("referrer_id = ?", userID).
+		Group("referrer_id").
+		Scan(&totals)
+
+	return map[string]interface{}{
+		"referralCode":  user.ReferralCode,
+		"referrerId":    user.ReferrerID,
+		"invitedCount":  invited,
+		"pendingAmount": internal.RoundMoney(totals.PendingAmount),
+		"settledAmount": internal.RoundMoney(totals.SettledAmount),
+		"orderCount":    totals.OrderCount,
+	}, nil
+}
@@ -0,0 +1,86 @@
+package distribution
+
+import (
+	"testing"
+
+	"z26b-backend/internal"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.AutoMigrate(&internal.User{}, &internal.SPU{}, &internal.SKU{}, &internal.Order{}, &internal.OrderItem{},
+		&internal.CommissionRule{}, &internal.CommissionRecord{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestCreateOrderCommissions(t *testing.T) {
+	tests := []struct {
+		name     string
+		referrer string
+		status   string
+		want     int
+	}{
+		{"已完成订单", "r1", internal.OrderStatusFinished, 2},
+		{"未完成订单", "r1", internal.OrderStatusToReceive, 0},
+		{"没有推荐人", "", internal.OrderStatusFinished, 0},
+		{"自己推荐自己", "u1", internal.OrderStatusFinished, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			db.Create(&internal.User{ID: "u1", OpenID: "o1", ReferrerID: tt.referrer})
+			db.Create(&internal.SPU{ID: "p1", CategoryID: "c1"})
+			db.Create(&internal.SKU{ID: "k1", SPUID: "p1"})
+			db.Create(&internal.Order{ID: "o1", UserID: "u1", Status: tt.status})
+			db.Create(&internal.OrderItem{ID: "i1", OrderID: "o1", SKUID: "k1", Quantity: 2, Price: 50})
+			db.Create(&internal.OrderItem{ID: "i2", OrderID: "o1", SKUID: "k1", Quantity: 1, Price: 30})
+			db.Create(&internal.CommissionRule{ID: "rule", Scope: internal.CommissionScopeCategory, TargetID: "c1", Rate: 0.1, Status: "active"})
+
+			service := NewCommissionService(db)
+			got, err := service.CreateOrderCommissions("o1")
+			if err != nil {
+				t.Fatalf("CreateOrderCommissions() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CreateOrderCommissions() = %d, want %d", got, tt.want)
+			}
+
+			// 重复调用不重复生成
+			again, err := service.CreateOrderCommissions("o1")
+			if err != nil || again != 0 {
+				t.Errorf("CreateOrderCommissions() again = %d, %v, want 0", again, err)
+			}
+			var records []internal.CommissionRecord
+			db.Order("order_item_id").Find(&records)
+			if len(records) != tt.want {
+				t.Fatalf("commission records = %d, want %d", len(records), tt.want)
+			}
+			if tt.want > 0 && (records[0].Amount != 10 || records[1].Amount != 3 || records[0].ReferrerID != "r1") {
+				t.Errorf("commission records = %+v", records)
+			}
+		})
+	}
+}
+
+func TestCommissionRecordUnique(t *testing.T) {
+	db := newTestDB(t)
+	record := internal.CommissionRecord{ID: "c1", OrderID: "o1", OrderItemID: "i1"}
+	if err := db.Create(&record).Error; err != nil {
+		t.Fatal(err)
+	}
+	record.ID = "c2"
+	if err := db.Create(&record).Error; err == nil {
+		t.Error("duplicate commission for the same order item was created")
+	}
+}
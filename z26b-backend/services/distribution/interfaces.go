@@ -0,0 +1,29 @@
+package distribution
+
+import "z26b-backend/internal"
+
+// CommissionServiceInterface 分销佣金服务接口
+type CommissionServiceInterface interface {
+	// GetRules 获取佣金规则列表
+	GetRules(scope string) ([]internal.CommissionRule, error)
+	// CreateRule 创建佣金规则
+	CreateRule(rule *internal.CommissionRule) error
+	// UpdateRule 更新佣金规则
+	UpdateRule(id string, updates map[string]interface{}) error
+	// DeleteRule 删除佣金规则
+	DeleteRule(id string) error
+	// ResolveRule 获取商品适用的佣金规则（商品规则优先于分类规则）
+	ResolveRule(spuID, categoryID string) (*internal.CommissionRule, error)
+	// CreateOrderCommissions 订单完成时生成佣金记录
+	CreateOrderCommissions(orderID string) (int, error)
+	// ReverseOrderCommissions 订单退款时冲正佣金记录
+	ReverseOrderCommissions(orderID string) (int64, error)
+	// GetCommissions 获取佣金记录列表
+	GetCommissions(referrerID, status string, page, pageSize int) ([]internal.CommissionRecord, int64, error)
+	// SettleCommissions 结算待结算佣金
+	SettleCommissions(ids []string, referrerID string) (int64, error)
+	// GetReferrerReport 按推荐人汇总佣金
+	GetReferrerReport(page, pageSize int) ([]map[string]interface{}, int64, error)
+	// GetReferralSummary 获取用户的推广概况
+	GetReferralSummary(userID string) (map[string]interface{}, error)
+}
@@ -0,0 +1,17 @@
+package history
+
+import "z26b-backend/internal"
+
+// ProductHistoryServiceInterface 商品变更历史服务接口
+type ProductHistoryServiceInterface interface {
+	// Capture 获取商品当前快照，用于在变更前保存原始状态
+	Capture(spuID string) (*ProductSnapshot, error)
+	// Record 对比变更前快照与当前状态，有差异时保存新版本；首次记录时同时保存变更前的原始版本
+	Record(spuID, action, adminID string, before *ProductSnapshot) (*internal.ProductRevision, error)
+	// GetHistory 获取商品版本列表（按版本号倒序，不含快照）
+	GetHistory(spuID string, page, pageSize int) ([]internal.ProductRevision, int64, error)
+	// GetRevision 获取指定版本（含快照）
+	GetRevision(spuID string, version int) (*internal.ProductRevision, error)
+	// Restore 将商品恢复到指定版本，并记录为新版本
+	Restore(spuID string, version int, adminID string) (*RestoreResult, error)
+}
@@ -0,0 +1,232 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"z26b-backend/internal"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrRevisionNotFound 版本不存在
+var ErrRevisionNotFound = errors.New("版本不存在")
+
+// ProductHistoryService 商品变更历史服务
+type ProductHistoryService struct {
+	db *gorm.DB
+}
+
+// NewProductHistoryService 创建商品变更历史服务实例
+func NewProductHistoryService(db *gorm.DB) *ProductHistoryService {
+	return &ProductHistoryService{db: db}
+}
+
+// RestoreResult 恢复结果；已删除的 SKU 不会重建，恢复后新增的 SKU 保持不变
+type RestoreResult struct {
+	Revision    *internal.ProductRevision `json:"revision"`
+	MissingSKUs []string                  `json:"missingSkus"` // 快照中存在但已被删除的 SKU
+	MissingTags []string                  `json:"missingTags"` // 快照中存在但已被删除的标签
+}
+
+// Capture 获取商品当前快照
+func (s *ProductHistoryService) Capture(spuID string) (*ProductSnapshot, error) {
+	return capture(s.db, spuID)
+}
+
+// Record 对比变更前快照与当前状态，有差异时保存新版本
+func (s *ProductHistoryService) Record(spuID, action, adminID string, before *ProductSnapshot) (*internal.ProductRevision, error) {
+	var revision *internal.ProductRevision
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		revision, err = record(tx, spuID, action, adminID, before, 0)
+		return err
+	})
+	return revision, err
+}
+
+// record 保存新版本，没有差异时返回 nil；商品尚无历史时先把变更前状态保存为初始版本
+func record(tx *gorm.DB, spuID, action, adminID string, before *ProductSnapshot, restoredFrom int) (*internal.ProductRevision, error) {
+	after, err := capture(tx, spuID)
+	if err != nil {
+		return nil, err
+	}
+	changes := Diff(before, after)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	var maxVersion int
+	if err := tx.Model(&internal.ProductRevision{}).Where("spu_id = ?", spuID).
+		Select("COALESCE(MAX(version), 0)").Scan(&maxVersion).Error; err != nil {
+		return nil, err
+	}
+	now := time.Now().UnixMilli()
+	if maxVersion == 0 {
+		baseline := internal.ProductRevision{
+			ID:        uuid.New().String(),
+			SPUID:     spuID,
+			Version:   1,
+			Action:    internal.RevisionActionBaseline,
+			Snapshot:  internal.ToJSON(before),
+			Changes:   internal.ToJSON([]FieldChange{}),
+			CreatedAt: now,
+		}
+		if err := tx.Create(&baseline).Error; err != nil {
+			return nil, err
+		}
+		maxVersion = 1
+	}
+
+	revision := internal.ProductRevision{
+		ID:           uuid.New().String(),
+		SPUID:        spuID,
+		Version:      maxVersion + 1,
+		Action:       action,
+		Snapshot:     internal.ToJSON(after),
+		Changes:      internal.ToJSON(changes),
+		RestoredFrom: restoredFrom,
+		AdminID:      adminID,
+		CreatedAt:    now,
+	}
+	if err := tx.Create(&revision).Error; err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// GetHistory 获取商品版本列表（按版本号倒序，不含快照）
+func (s *ProductHistoryService) GetHistory(spuID string, page, pageSize int) ([]internal.ProductRevision, int64, error) {
+	var total int64
+	query := s.db.Model(&internal.ProductRevision{}).Where("spu_id = ?", spuID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var revisions []internal.ProductRevision
+	err := query.Omit("snapshot").Order("version DESC").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&revisions).Error
+	return revisions, total, err
+}
+
+// GetRevision 获取指定版本（含快照）
+func (s *ProductHistoryService) GetRevision(spuID string, version int) (*internal.ProductRevision, error) {
+	var revision internal.ProductRevision
+	err := s.db.Where("spu_id = ? AND version = ?", spuID, version).First(&revision).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRevisionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// Restore 将商品恢复到指定版本：恢复 SPU 字段、标签和仍存在的 SKU（库存除外），并记录为新版本
+func (s *ProductHistoryService) Restore(spuID string, version int, adminID string) (*RestoreResult, error) {
+	target, err := s.GetRevision(spuID, version)
+	if err != nil {
+		return nil, err
+	}
+	var snap ProductSnapshot
+	if err := json.Unmarshal(target.Snapshot, &snap); err != nil {
+		return nil, err
+	}
+
+	result := &RestoreResult{MissingSKUs: []string{}, MissingTags: []string{}}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		before, err := capture(tx, spuID)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UnixMilli()
+
+		err = tx.Model(&internal.SPU{}).Where("id = ?", spuID).Updates(map[string]interface{}{
+			"code":         snap.Code,
+			"name":         snap.Name,
+			"detail":       internal.SanitizeHTML(snap.Detail), // 旧版本可能保存于清洗规则上线前
+			"cover_image":  snap.CoverImage,
+			"swipe_images": internal.ToJSON(snap.SwiperImages),
+			"category_id":  snap.CategoryID,
+			"specs":        internal.ToJSON(snap.Specs),
+			"status":       snap.Status,
+			"priority":     snap.Priority,
+			"updated_at":   now,
+			"updated_by":   adminID,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		// 标签：按快照替换，已删除的标签跳过，回收站中标签的关联保留以便恢复
+		if err := tx.Where("spu_id = ? AND tag_id IN (SELECT id FROM tag WHERE deleted_at IS NULL)", spuID).Delete(&internal.SPUTag{}).Error; err != nil {
+			return err
+		}
+		for _, tag := range snap.Tags {
+			var count int64
+			if err := tx.Model(&internal.Tag{}).Where("id = ?", tag.ID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				result.MissingTags = append(result.MissingTags, tag.Name)
+				continue
+			}
+			spuTag := internal.SPUTag{
+				ID:        uuid.New().String(),
+				SPUID:     spuID,
+				TagID:     tag.ID,
+				CreatedAt: time.Now(),
+			}
+			if err := tx.Create(&spuTag).Error; err != nil {
+				return err
+			}
+		}
+
+		// SKU：只恢复仍存在的 SKU，库存保持当前值
+		var current []internal.SKU
+		if err := tx.Select("id", "price").Where(`"SPUID" = ?`, spuID).Find(&current).Error; err != nil {
+			return err
+		}
+		oldPrices := make(map[string]float64, len(current))
+		for _, sku := range current {
+			oldPrices[sku.ID] = sku.Price
+		}
+		var changes []internal.SKUPriceHistory
+		for _, sku := range snap.SKUs {
+			res := tx.Model(&internal.SKU{}).Where(`id = ? AND "SPUID" = ?`, sku.ID, spuID).Updates(map[string]interface{}{
+				"code":        sku.Code,
+				"description": sku.Description,
+				"image":       sku.Image,
+				"price":       sku.Price,
+				"spec_values": internal.ToJSON(sku.SpecValues),
+				"spec_key":    internal.SpecKey(sku.SpecValues),
+				"updated_at":  now,
+				"updated_by":  adminID,
+			})
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				result.MissingSKUs = append(result.MissingSKUs, sku.ID)
+				continue
+			}
+			oldPrice := oldPrices[sku.ID]
+			changes = append(changes, internal.PriceChange(sku.ID, &oldPrice, sku.Price))
+		}
+
+		// 记录调价历史并更新 SPU 价格范围
+		if err := internal.RecordSKUPriceChanges(tx, spuID, changes, internal.PriceSourceRestore, adminID); err != nil {
+			return err
+		}
+
+		result.Revision, err = record(tx, spuID, internal.RevisionActionRestore, adminID, before, version)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,190 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"z26b-backend/internal"
+
+	"gorm.io/gorm"
+)
+
+// ProductSnapshot 商品快照：SPU 可编辑字段、标签和 SKU
+type ProductSnapshot struct {
+	Code         string                   `json:"code"`
+	Name         string                   `json:"name"`
+	Detail       string                   `json:"detail"`
+	CoverImage   string                   `json:"coverImage"`
+	SwiperImages []string                 `json:"swiperImages"`
+	CategoryID   string                   `json:"categoryId"`
+	Specs        []internal.SpecDimension `json:"specs"`
+	Status       string                   `json:"status"`
+	Priority     int                      `json:"priority"`
+	Tags         []SnapshotTag            `json:"tags"`
+	SKUs         []SnapshotSKU            `json:"skus"`
+}
+
+// SnapshotTag 快照中的标签
+type SnapshotTag struct {
+	ID   string `json:"_id"`
+	Name string `json:"name"`
+}
+
+// SnapshotSKU 快照中的 SKU；库存随订单实时变化，恢复版本时不回滚库存
+type SnapshotSKU struct {
+	ID          string   `json:"_id"`
+	Code        string   `json:"code"`
+	Description string   `json:"description"`
+	Image       string   `json:"image"`
+	Price       float64  `json:"price"`
+	Count       int      `json:"count"`
+	SpecValues  []string `json:"specValues"`
+}
+
+// FieldChange 字段级差异，Old/New 为 nil 表示新增/删除
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// capture 读取商品快照
+func capture(db *gorm.DB, spuID string) (*ProductSnapshot, error) {
+	var spu internal.SPU
+	if err := db.First(&spu, "id = ?", spuID).Error; err != nil {
+		return nil, err
+	}
+
+	snap := &ProductSnapshot{
+		Code:         spu.Code,
+		Name:         spu.Name,
+		Detail:       spu.Detail,
+		CoverImage:   spu.CoverImage,
+		SwiperImages: []string{},
+		CategoryID:   spu.CategoryID,
+		Specs:        internal.ParseSpecs(spu.Specs),
+		Status:       spu.Status,
+		Priority:     spu.Priority,
+		Tags:         []SnapshotTag{},
+		SKUs:         []SnapshotSKU{},
+	}
+	if len(spu.SwipeImages) > 0 {
+		_ = json.Unmarshal(spu.SwipeImages, &snap.SwiperImages)
+	}
+
+	var spuTags []internal.SPUTag
+	if err := db.Preload("Tag").Where("spu_id = ?", spuID).Find(&spuTags).Error; err != nil {
+		return nil, err
+	}
+	for _, st := range spuTags {
+		// 回收站中的标签不计入
+		if st.Tag != nil {
+			snap.Tags = append(snap.Tags, SnapshotTag{ID: st.TagID, Name: st.Tag.Name})
+		}
+	}
+	sort.Slice(snap.Tags, func(i, j int) bool { return snap.Tags[i].ID < snap.Tags[j].ID })
+
+	var skus []internal.SKU
+	if err := db.Where(`"SPUID" = ?`, spuID).Order("created_at ASC, id ASC").Find(&skus).Error; err != nil {
+		return nil, err
+	}
+	for _, sku := range skus {
+		snap.SKUs = append(snap.SKUs, SnapshotSKU{
+			ID:          sku.ID,
+			Code:        sku.Code,
+			Description: sku.Description,
+			Image:       sku.Image,
+			Price:       sku.Price,
+			Count:       sku.Count,
+			SpecValues:  internal.ParseSpecValues(sku.SpecValues),
+		})
+	}
+	return snap, nil
+}
+
+// Diff 对比两个快照，返回字段级差异；SKU 字段以 skus[SKU ID].字段名 表示
+func Diff(before, after *ProductSnapshot) []FieldChange {
+	changes := []FieldChange{}
+	add := func(field string, old, new interface{}) {
+		if !equalJSON(old, new) {
+			changes = append(changes, FieldChange{Field: field, Old: old, New: new})
+		}
+	}
+
+	add("code", before.Code, after.Code)
+	add("name", before.Name, after.Name)
+	add("detail", before.Detail, after.Detail)
+	add("coverImage", before.CoverImage, after.CoverImage)
+	add("swiperImages", before.SwiperImages, after.SwiperImages)
+	add("categoryId", before.CategoryID, after.CategoryID)
+	add("specs", before.Specs, after.Specs)
+	add("status", before.Status, after.Status)
+	add("priority", before.Priority, after.Priority)
+	if !equalJSON(tagIDs(before.Tags), tagIDs(after.Tags)) {
+		changes = append(changes, FieldChange{Field: "tags", Old: tagNames(before.Tags), New: tagNames(after.Tags)})
+	}
+
+	old := make(map[string]SnapshotSKU, len(before.SKUs))
+	for _, sku := range before.SKUs {
+		old[sku.ID] = sku
+	}
+	seen := make(map[string]bool, len(after.SKUs))
+	for _, sku := range after.SKUs {
+		seen[sku.ID] = true
+		prev, ok := old[sku.ID]
+		if !ok {
+			changes = append(changes, FieldChange{Field: skuField(sku.ID, ""), Old: nil, New: sku})
+			continue
+		}
+		add(skuField(sku.ID, "code"), prev.Code, sku.Code)
+		add(skuField(sku.ID, "description"), prev.Description, sku.Description)
+		add(skuField(sku.ID, "image"), prev.Image, sku.Image)
+		add(skuField(sku.ID, "price"), prev.Price, sku.Price)
+		add(skuField(sku.ID, "count"), prev.Count, sku.Count)
+		add(skuField(sku.ID, "specValues"), prev.SpecValues, sku.SpecValues)
+	}
+	for _, sku := range before.SKUs {
+		if !seen[sku.ID] {
+			changes = append(changes, FieldChange{Field: skuField(sku.ID, ""), Old: sku, New: nil})
+		}
+	}
+	return changes
+}
+
+func skuField(id, field string) string {
+	if field == "" {
+		return fmt.Sprintf("skus[%s]", id)
+	}
+	return fmt.Sprintf("skus[%s].%s", id, field)
+}
+
+func tagIDs(tags []SnapshotTag) []string {
+	ids := make([]string, 0, len(tags))
+	for _, t := range tags {
+		ids = append(ids, t.ID)
+	}
+	return ids
+}
+
+func tagNames(tags []SnapshotTag) string {
+	names := make([]string, 0, len(tags))
+	for _, t := range tags {
+		names = append(names, t.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+// equalJSON 按 JSON 序列化结果比较，nil 切片与空切片视为相同
+func equalJSON(a, b interface{}) bool {
+	ja, _ := json.Marshal(a)
+	jb, _ := json.Marshal(b)
+	normalize := func(s []byte) string {
+		if string(s) == "null" {
+			return "[]"
+		}
+		return string(s)
+	}
+	return normalize(ja) == normalize(jb)
+}
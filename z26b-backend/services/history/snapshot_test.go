@@ -0,0 +1,73 @@
+package history
+
+import (
+	"reflect"
+	"testing"
+
+	"z26b-backend/internal"
+)
+
+func baseSnapshot() *ProductSnapshot {
+	return &ProductSnapshot{
+		Code:         "P001",
+		Name:         "T恤",
+		SwiperImages: []string{},
+		Specs:        []internal.SpecDimension{{Name: "颜色", Values: []string{"红", "蓝"}}},
+		Status:       "ENABLED",
+		Tags:         []SnapshotTag{{ID: "t1", Name: "新品"}},
+		SKUs: []SnapshotSKU{
+			{ID: "k1", Description: "红", Price: 10, Count: 5, SpecValues: []string{"红"}},
+			{ID: "k2", Description: "蓝", Price: 10, Count: 5, SpecValues: []string{"蓝"}},
+		},
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(s *ProductSnapshot)
+		want   []string
+	}{
+		{"无变化", func(s *ProductSnapshot) {}, []string{}},
+		{"nil与空切片相同", func(s *ProductSnapshot) { s.SwiperImages = nil }, []string{}},
+		{"基本字段", func(s *ProductSnapshot) { s.Name = "长袖T恤"; s.Priority = 3 }, []string{"name", "priority"}},
+		{"嵌套规格", func(s *ProductSnapshot) { s.Specs[0].Values = []string{"红", "绿"} }, []string{"specs"}},
+		{"标签", func(s *ProductSnapshot) { s.Tags = append(s.Tags, SnapshotTag{ID: "t2", Name: "热卖"}) }, []string{"tags"}},
+		{"SKU字段", func(s *ProductSnapshot) { s.SKUs[1].Price = 12; s.SKUs[1].SpecValues = []string{"绿"} },
+			[]string{"skus[k2].price", "skus[k2].specValues"}},
+		{"新增和删除SKU", func(s *ProductSnapshot) { s.SKUs = []SnapshotSKU{s.SKUs[1], {ID: "k3"}} },
+			[]string{"skus[k3]", "skus[k1]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := baseSnapshot()
+			after := baseSnapshot()
+			tt.modify(after)
+			fields := []string{}
+			for _, change := range Diff(before, after) {
+				fields = append(fields, change.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.want) {
+				t.Errorf("Diff() fields = %v, want %v", fields, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffValues(t *testing.T) {
+	before := baseSnapshot()
+	after := baseSnapshot()
+	after.Tags = []SnapshotTag{{ID: "t2", Name: "热卖"}, {ID: "t3", Name: "清仓"}}
+	after.SKUs = after.SKUs[:1]
+
+	changes := Diff(before, after)
+	if len(changes) != 2 {
+		t.Fatalf("Diff() = %+v, want 2 changes", changes)
+	}
+	if changes[0].Old != "新品" || changes[0].New != "热卖,清仓" {
+		t.Errorf("tags change = %+v, want tag names", changes[0])
+	}
+	if removed, ok := changes[1].Old.(SnapshotSKU); !ok || removed.ID != "k2" || changes[1].New != nil {
+		t.Errorf("removed SKU change = %+v", changes[1])
+	}
+}
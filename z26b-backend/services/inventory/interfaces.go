@@ -0,0 +1,37 @@
+package inventory
+
+import "z26b-backend/internal"
+
+// InventoryServiceInterface 库存服务接口
+type InventoryServiceInterface interface {
+	// Change 按增量变动库存并记录流水，quantity 为负数时扣减
+	Change(skuID string, quantity int, reason, refID, operatorID, remark string) (*internal.StockMovement, error)
+	// ChangeAt 按增量变动指定仓库的库存并同步 SKU 总库存
+	ChangeAt(warehouseID, skuID string, quantity int, reason, refID, operatorID, remark string) (*internal.StockMovement, error)
+	// SetCount 将库存设置为指定数量并记录差额流水
+	SetCount(skuID string, count int, reason, refID, operatorID, remark string) (*internal.StockMovement, error)
+	// RestoreOrder 按库存流水归还订单扣减的库存
+	RestoreOrder(orderID, reason, operatorID, remark string) error
+	// RecordCreated 新建 SKU 后记录初始库存流水
+	RecordCreated(sku *internal.SKU, reason, refID, operatorID, remark string) error
+	// GetMovements 获取 SKU 库存流水
+	GetMovements(skuID, reason string, page, pageSize int) ([]internal.StockMovement, int64, error)
+	// GetLowStock 低库存报表
+	GetLowStock(keyword string, page, pageSize int) ([]LowStockItem, int64, error)
+	// GetAlerts 获取低库存预警
+	GetAlerts(unacknowledged bool, page, pageSize int) ([]internal.StockAlert, int64, error)
+	// AcknowledgeAlerts 将预警标记为已处理
+	AcknowledgeAlerts(ids []string, adminID string) (int64, error)
+	// SetThreshold 设置 SKU 低库存预警值
+	SetThreshold(spuID string, skuIDs []string, threshold int) (int64, error)
+	// SetWarehouseCount 设置仓库中 SKU 的库存并同步总库存
+	SetWarehouseCount(warehouseID, skuID string, count int, reason, refID, operatorID, remark string) (*internal.StockMovement, error)
+	// SeedWarehouse 将尚未分配到仓库的总库存记入指定仓库
+	SeedWarehouse(warehouseID string) (int, error)
+	// GetWarehouseStock 获取仓库库存列表
+	GetWarehouseStock(warehouseID, keyword string, page, pageSize int) ([]internal.WarehouseStock, int64, error)
+	// WarehousesEnabled 是否存在启用的仓库
+	WarehousesEnabled() (bool, error)
+	// AllocateOrder 为订单明细分配仓库并扣减仓库库存
+	AllocateOrder(orderID, provinceName, provinceCode string, items []internal.OrderItem) ([]internal.OrderItem, error)
+}
@@ -0,0 +1,344 @@
+package inventory
+
+import (
+	"errors"
+	"time"
+
+	"z26b-backend/internal"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInsufficientStock 库存不足
+	ErrInsufficientStock = errors.New("库存不足")
+	// ErrSKUNotFound SKU 不存在
+	ErrSKUNotFound = errors.New("SKU不存在")
+	// ErrStockChanged 设置库存时库存已被其他操作修改
+	ErrStockChanged = errors.New("库存已被其他操作修改，请刷新后重试")
+	// ErrWarehouseNotFound 仓库不存在
+	ErrWarehouseNotFound = errors.New("仓库不存在")
+	// ErrDefaultWarehouseStock 按总库存减少库存时默认仓库库存不足
+	ErrDefaultWarehouseStock = errors.New("默认仓库库存不足，请在仓库库存中调整")
+)
+
+// InventoryService 库存服务：库存变动统一经此记账，并在库存降到预警值时生成预警
+type InventoryService struct {
+	db *gorm.DB
+}
+
+// NewInventoryService 创建库存服务实例
+func NewInventoryService(db *gorm.DB) *InventoryService {
+	return &InventoryService{db: db}
+}
+
+// WithTx 返回绑定到指定事务的服务实例，用于与订单等操作共用事务
+func (s *InventoryService) WithTx(tx *gorm.DB) *InventoryService {
+	return &InventoryService{db: tx}
+}
+
+// Change 按增量变动库存并记录流水，quantity 为负数时扣减并校验库存充足
+func (s *InventoryService) Change(skuID string, quantity int, reason, refID, operatorID, remark string) (*internal.StockMovement, error) {
+	return s.ChangeAt("", skuID, quantity, reason, refID, operatorID, remark)
+}
+
+// ChangeAt 按增量变动指定仓库的库存并同步 SKU 总库存，warehouseID 为空时变动默认仓库；
+// 归还库存时仓库已删除则归还到默认仓库，没有仓库时只变动总库存
+func (s *InventoryService) ChangeAt(warehouseID, skuID string, quantity int, reason, refID, operatorID, remark string) (*internal.StockMovement, error) {
+	var movement *internal.StockMovement
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if warehouseID != "" {
+			var exists int64
+			if err := tx.Model(&internal.Warehouse{}).Where("id = ?", warehouseID).Count(&exists).Error; err != nil {
+				return err
+			}
+			if exists == 0 && quantity < 0 {
+				return ErrWarehouseNotFound
+			}
+			if exists == 0 {
+				warehouseID = ""
+			}
+		}
+		if warehouseID == "" {
+			var err error
+			if warehouseID, err = defaultWarehouseID(tx); err != nil {
+				return err
+			}
+		}
+		if warehouseID != "" {
+			if err := changeWarehouseStock(tx, warehouseID, skuID, quantity); err != nil {
+				return err
+			}
+		}
+
+		// 已删除的 SKU 仍可归还库存，保证历史订单退款/取消的流水完整
+		query := tx.Unscoped().Model(&internal.SKU{}).Where("id = ?", skuID)
+		if quantity < 0 {
+			query = query.Where("count >= ?", -quantity)
+		}
+		result := query.UpdateColumn("count", gorm.Expr("count + ?", quantity))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			var exists int64
+			tx.Unscoped().Model(&internal.SKU{}).Where("id = ?", skuID).Count(&exists)
+			if exists == 0 {
+				return ErrSKUNotFound
+			}
+			return ErrInsufficientStock
+		}
+
+		var err error
+		movement, err = record(tx, warehouseID, skuID, quantity, reason, refID, operatorID, remark)
+		return err
+	})
+	return movement, err
+}
+
+// SetCount 将库存设置为指定数量并记录差额流水，数量不变时不记录；存在仓库时差额记到默认仓库
+func (s *InventoryService) SetCount(skuID string, count int, reason, refID, operatorID, remark string) (*internal.StockMovement, error) {
+	if count < 0 {
+		return nil, errors.New("库存不能为负数")
+	}
+	var movement *internal.StockMovement
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var sku internal.SKU
+		if err := tx.Select("id", "count").First(&sku, "id = ?", skuID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrSKUNotFound
+			}
+			return err
+		}
+		if sku.Count == count {
+			return nil
+		}
+
+		// 条件更新，避免覆盖读取后发生的订单扣减
+		result := tx.Model(&internal.SKU{}).Where("id = ? AND count = ?", skuID, sku.Count).UpdateColumn("count", count)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrStockChanged
+		}
+
+		warehouseID, err := defaultWarehouseID(tx)
+		if err != nil {
+			return err
+		}
+		if warehouseID != "" {
+			if err := changeWarehouseStock(tx, warehouseID, skuID, count-sku.Count); errors.Is(err, ErrInsufficientStock) {
+				return ErrDefaultWarehouseStock
+			} else if err != nil {
+				return err
+			}
+		}
+
+		movement, err = record(tx, warehouseID, skuID, count-sku.Count, reason, refID, operatorID, remark)
+		return err
+	})
+	return movement, err
+}
+
+// RestoreOrder 归还订单扣减的库存：按库存流水汇总该订单在各仓库、各 SKU 上的净扣减并逐项归还，
+// 未扣减库存的明细不归还，重复调用时不会多归还
+func (s *InventoryService) RestoreOrder(orderID, reason, operatorID, remark string) error {
+	var rows []struct {
+		WarehouseID string `gorm:"column:warehouse_id"`
+		SKUID       string `gorm:"column:sku_id"`
+		Quantity    int    `gorm:"column:quantity"`
+	}
+	err := s.db.Model(&internal.StockMovement{}).
+		Select("warehouse_id, sku_id, SUM(quantity) AS quantity").
+		Where("ref_id = ? AND reason IN ?", orderID, []string{internal.StockReasonOrder, internal.StockReasonCancel, internal.StockReasonRefund}).
+		Group("warehouse_id, sku_id").
+		Order("warehouse_id, sku_id").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if row.Quantity >= 0 {
+			continue
+		}
+		if _, err := s.ChangeAt(row.WarehouseID, row.SKUID, -row.Quantity, reason, orderID, operatorID, remark); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordCreated 新建 SKU 后记录初始库存流水，存在仓库时初始库存记到默认仓库；库存为 0 时不记录
+func (s *InventoryService) RecordCreated(sku *internal.SKU, reason, refID, operatorID, remark string) error {
+	if sku.Count == 0 {
+		return nil
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		warehouseID, err := defaultWarehouseID(tx)
+		if err != nil {
+			return err
+		}
+		if warehouseID != "" {
+			if err := changeWarehouseStock(tx, warehouseID, sku.ID, sku.Count); err != nil {
+				return err
+			}
+		}
+		movement := internal.StockMovement{
+			ID:          uuid.New().String(),
+			SKUID:       sku.ID,
+			SPUID:       sku.SPUID,
+			Reason:      reason,
+			Quantity:    sku.Count,
+			CountAfter:  sku.Count,
+			WarehouseID: warehouseID,
+			RefID:       refID,
+			OperatorID:  operatorID,
+			Remark:      remark,
+			CreatedAt:   time.Now().UnixMilli(),
+		}
+		return tx.Create(&movement).Error
+	})
+}
+
+// record 写入流水；库存由高于预警值降到预警值及以下时生成预警
+func record(tx *gorm.DB, warehouseID, skuID string, quantity int, reason, refID, operatorID, remark string) (*internal.StockMovement, error) {
+	var sku internal.SKU
+	if err := tx.Unscoped().Select("id", "SPUID", "count", "low_stock_threshold").First(&sku, "id = ?", skuID).Error; err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	movement := internal.StockMovement{
+		ID:          uuid.New().String(),
+		SKUID:       skuID,
+		SPUID:       sku.SPUID,
+		Reason:      reason,
+		Quantity:    quantity,
+		CountAfter:  sku.Count,
+		WarehouseID: warehouseID,
+		RefID:       refID,
+		OperatorID:  operatorID,
+		Remark:      remark,
+		CreatedAt:   now,
+	}
+	if err := tx.Create(&movement).Error; err != nil {
+		return nil, err
+	}
+
+	if internal.CrossedLowStock(sku.Count-quantity, sku.Count, sku.LowStockThreshold) {
+		alert := internal.StockAlert{
+			ID:        uuid.New().String(),
+			SKUID:     skuID,
+			SPUID:     sku.SPUID,
+			Count:     sku.Count,
+			Threshold: sku.LowStockThreshold,
+			CreatedAt: now,
+		}
+		if err := tx.Create(&alert).Error; err != nil {
+			return nil, err
+		}
+		internal.GlobalLogger.Warn("SKU stock is low", map[string]interface{}{"skuId": skuID, "count": sku.Count, "threshold": sku.LowStockThreshold})
+	}
+	return &movement, nil
+}
+
+// GetMovements 获取 SKU 库存流水，reason 为空时返回全部
+func (s *InventoryService) GetMovements(skuID, reason string, page, pageSize int) ([]internal.StockMovement, int64, error) {
+	var movements []internal.StockMovement
+	var total int64
+
+	query := s.db.Model(&internal.StockMovement{}).Where("sku_id = ?", skuID)
+	if reason != "" {
+		query = query.Where("reason = ?", reason)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("created_at DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&movements).Error
+	return movements, total, err
+}
+
+// LowStockItem 低库存报表条目
+type LowStockItem struct {
+	SKUID       string `json:"skuId" gorm:"column:sku_id"`
+	SPUID       string `json:"spuId" gorm:"column:spu_id"`
+	SPUName     string `json:"spuName"`
+	Code        string `json:"code"`
+	Description string `json:"description"`
+	Count       int    `json:"count"`
+	Threshold   int    `json:"threshold"`
+}
+
+// GetLowStock 低库存报表：库存不高于预警值的 SKU，按库存升序
+func (s *InventoryService) GetLowStock(keyword string, page, pageSize int) ([]LowStockItem, int64, error) {
+	query := s.db.Table("sku").
+		Joins(`JOIN spu ON spu.id = sku."SPUID" AND spu.deleted_at IS NULL`).
+		Where("sku.deleted_at IS NULL AND sku.low_stock_threshold > 0 AND sku.count <= sku.low_stock_threshold")
+	if keyword != "" {
+		like := "%" + keyword + "%"
+		query = query.Where("spu.name LIKE ? OR sku.description LIKE ? OR sku.code LIKE ?", like, like, like)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	items := []LowStockItem{}
+	err := query.Select(`sku.id AS sku_id, sku."SPUID" AS spu_id, spu.name AS spu_name, sku.code, sku.description,
+			sku.count, sku.low_stock_threshold AS threshold`).
+		Order("sku.count ASC, sku.id ASC").Offset((page - 1) * pageSize).Limit(pageSize).
+		Scan(&items).Error
+	return items, total, err
+}
+
+// GetAlerts 获取低库存预警，unacknowledged 为 true 时只返回未处理的预警
+func (s *InventoryService) GetAlerts(unacknowledged bool, page, pageSize int) ([]internal.StockAlert, int64, error) {
+	var alerts []internal.StockAlert
+	var total int64
+
+	query := s.db.Model(&internal.StockAlert{})
+	if unacknowledged {
+		query = query.Where("acknowledged_at IS NULL")
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Preload("SKU", internal.WithDeleted).Preload("SKU.SPU", internal.WithDeleted).
+		Order("created_at DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&alerts).Error
+	return alerts, total, err
+}
+
+// AcknowledgeAlerts 将预警标记为已处理，ids 为空时处理全部未处理预警，返回处理数量
+func (s *InventoryService) AcknowledgeAlerts(ids []string, adminID string) (int64, error) {
+	query := s.db.Model(&internal.StockAlert{}).Where("acknowledged_at IS NULL")
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+	result := query.Updates(map[string]interface{}{
+		"acknowledged_at": time.Now().UnixMilli(),
+		"acknowledged_by": adminID,
+	})
+	return result.RowsAffected, result.Error
+}
+
+// SetThreshold 设置 SKU 低库存预警值，skuIDs 为空时设置商品下全部 SKU，返回更新数量
+func (s *InventoryService) SetThreshold(spuID string, skuIDs []string, threshold int) (int64, error) {
+	if threshold < 0 {
+		return 0, errors.New("预警值不能为负数")
+	}
+	if spuID == "" && len(skuIDs) == 0 {
+		return 0, errors.New("请选择商品或SKU")
+	}
+	query := s.db.Model(&internal.SKU{})
+	if spuID != "" {
+		query = query.Where(`"SPUID" = ?`, spuID)
+	}
+	if len(skuIDs) > 0 {
+		query = query.Where("id IN ?", skuIDs)
+	}
+	result := query.UpdateColumn("low_stock_threshold", threshold)
+	return result.RowsAffected, result.Error
+}
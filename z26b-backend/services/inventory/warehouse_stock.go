@@ -0,0 +1,229 @@
+package inventory
+
+import (
+	"errors"
+	"time"
+
+	"z26b-backend/internal"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// defaultWarehouseID 默认仓库为最早创建的仓库，创建时已按总库存初始化仓库库存；没有仓库时返回空
+func defaultWarehouseID(tx *gorm.DB) (string, error) {
+	var ids []string
+	err := tx.Model(&internal.Warehouse{}).Order("created_at ASC, id ASC").Limit(1).Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return "", err
+	}
+	return ids[0], nil
+}
+
+// changeWarehouseStock 按增量变动仓库库存，扣减时校验仓库库存充足，归还时库存记录不存在则新建
+func changeWarehouseStock(tx *gorm.DB, warehouseID, skuID string, quantity int) error {
+	now := time.Now().UnixMilli()
+	query := tx.Model(&internal.WarehouseStock{}).Where("warehouse_id = ? AND sku_id = ?", warehouseID, skuID)
+	if quantity < 0 {
+		query = query.Where("count >= ?", -quantity)
+	}
+	result := query.Updates(map[string]interface{}{
+		"count":      gorm.Expr("count + ?", quantity),
+		"updated_at": now,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+	if quantity < 0 {
+		return ErrInsufficientStock
+	}
+	stock := internal.WarehouseStock{
+		ID:          uuid.New().String(),
+		WarehouseID: warehouseID,
+		SKUID:       skuID,
+		Count:       quantity,
+		UpdatedAt:   now,
+	}
+	return tx.Create(&stock).Error
+}
+
+// SetWarehouseCount 将仓库中 SKU 的库存设置为指定数量，差额同步到 SKU 总库存并记录流水
+func (s *InventoryService) SetWarehouseCount(warehouseID, skuID string, count int, reason, refID, operatorID, remark string) (*internal.StockMovement, error) {
+	if count < 0 {
+		return nil, errors.New("库存不能为负数")
+	}
+	var movement *internal.StockMovement
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var exists int64
+		if err := tx.Model(&internal.Warehouse{}).Where("id = ?", warehouseID).Count(&exists).Error; err != nil {
+			return err
+		}
+		if exists == 0 {
+			return ErrWarehouseNotFound
+		}
+		if err := tx.Model(&internal.SKU{}).Where("id = ?", skuID).Count(&exists).Error; err != nil {
+			return err
+		}
+		if exists == 0 {
+			return ErrSKUNotFound
+		}
+
+		// 尚无库存记录时按 0 处理
+		var stock internal.WarehouseStock
+		if err := tx.Where("warehouse_id = ? AND sku_id = ?", warehouseID, skuID).Limit(1).Find(&stock).Error; err != nil {
+			return err
+		}
+		delta := count - stock.Count
+		if delta == 0 {
+			return nil
+		}
+
+		now := time.Now().UnixMilli()
+		if stock.ID == "" {
+			stock = internal.WarehouseStock{ID: uuid.New().String(), WarehouseID: warehouseID, SKUID: skuID, Count: count, UpdatedAt: now}
+			if err := tx.Create(&stock).Error; err != nil {
+				return err
+			}
+		} else {
+			// 条件更新，避免覆盖读取后发生的订单扣减
+			result := tx.Model(&internal.WarehouseStock{}).Where("id = ? AND count = ?", stock.ID, stock.Count).
+				Updates(map[string]interface{}{"count": count, "updated_at": now})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrStockChanged
+			}
+		}
+
+		query := tx.Model(&internal.SKU{}).Where("id = ?", skuID)
+		if delta < 0 {
+			query = query.Where("count >= ?", -delta)
+		}
+		result := query.UpdateColumn("count", gorm.Expr("count + ?", delta))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInsufficientStock
+		}
+
+		var err error
+		movement, err = record(tx, warehouseID, skuID, delta, reason, refID, operatorID, remark)
+		return err
+	})
+	return movement, err
+}
+
+// SeedWarehouse 将 SKU 总库存中尚未分配到任何仓库的部分记入指定仓库，总库存不变、不记录流水；
+// 创建第一个仓库时调用，使各仓库库存之和与总库存一致，返回初始化的 SKU 数量
+func (s *InventoryService) SeedWarehouse(warehouseID string) (int, error) {
+	var rows []struct {
+		ID       string `gorm:"column:id"`
+		Quantity int    `gorm:"column:quantity"`
+	}
+	// 包含已删除的 SKU，回收站恢复后仓库库存仍与总库存一致
+	err := s.db.Unscoped().Model(&internal.SKU{}).
+		Select("id, count - COALESCE((SELECT SUM(ws.count) FROM warehouse_stock ws WHERE ws.sku_id = sku.id), 0) AS quantity").
+		Where("count > COALESCE((SELECT SUM(ws.count) FROM warehouse_stock ws WHERE ws.sku_id = sku.id), 0)").
+		Order("id").
+		Scan(&rows).Error
+	if err != nil {
+		return 0, err
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		for _, row := range rows {
+			if err := changeWarehouseStock(tx, warehouseID, row.ID, row.Quantity); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(rows), nil
+}
+
+// GetWarehouseStock 获取仓库库存列表，keyword 匹配 SKU 编码、描述或商品名称
+func (s *InventoryService) GetWarehouseStock(warehouseID, keyword string, page, pageSize int) ([]internal.WarehouseStock, int64, error) {
+	var stocks []internal.WarehouseStock
+	var total int64
+
+	query := s.db.Model(&internal.WarehouseStock{}).Where("warehouse_id = ?", warehouseID)
+	if keyword != "" {
+		like := "%" + keyword + "%"
+		query = query.Where(`sku_id IN (SELECT sku.id FROM sku JOIN spu ON spu.id = sku."SPUID"
+			WHERE sku.code LIKE ? OR sku.description LIKE ? OR spu.name LIKE ?)`, like, like, like)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Preload("SKU", internal.WithDeleted).Preload("SKU.SPU", internal.WithDeleted).
+		Order("count ASC, sku_id ASC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&stocks).Error
+	return stocks, total, err
+}
+
+// WarehousesEnabled 是否存在启用的仓库；不存在时下单不分配仓库
+func (s *InventoryService) WarehousesEnabled() (bool, error) {
+	var count int64
+	err := s.db.Model(&internal.Warehouse{}).Where("status = ?", "ENABLED").Count(&count).Error
+	return count > 0, err
+}
+
+// AllocateOrder 按收货省份为订单明细分配仓库并扣减仓库库存，单个仓库不足时拆分明细；
+// 返回设置了 WarehouseID 的明细，库存不足时返回 ErrInsufficientStock
+func (s *InventoryService) AllocateOrder(orderID, provinceName, provinceCode string, items []internal.OrderItem) ([]internal.OrderItem, error) {
+	var warehouses []internal.Warehouse
+	if err := s.db.Where("status = ?", "ENABLED").Order("priority DESC, created_at ASC").Find(&warehouses).Error; err != nil {
+		return nil, err
+	}
+	if len(warehouses) == 0 {
+		return nil, ErrWarehouseNotFound
+	}
+	ranked := internal.RankWarehouses(warehouses, provinceName, provinceCode)
+
+	skuIDs := make([]string, len(items))
+	quantities := make([]int, len(items))
+	for i, item := range items {
+		skuIDs[i] = item.SKUID
+		quantities[i] = item.Quantity
+	}
+
+	var rows []internal.WarehouseStock
+	if err := s.db.Where("warehouse_id IN ? AND sku_id IN ?", ranked, skuIDs).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	stock := make(map[string]map[string]int, len(ranked))
+	for _, row := range rows {
+		if stock[row.WarehouseID] == nil {
+			stock[row.WarehouseID] = make(map[string]int)
+		}
+		stock[row.WarehouseID][row.SKUID] = row.Count
+	}
+
+	allocations, ok := internal.AllocateStock(skuIDs, quantities, ranked, stock)
+	if !ok {
+		return nil, ErrInsufficientStock
+	}
+
+	allocated := make([]internal.OrderItem, 0, len(allocations))
+	split := make(map[int]bool, len(items))
+	for _, a := range allocations {
+		item := items[a.Line]
+		if split[a.Line] {
+			item.ID = internal.GenerateUUID()
+		}
+		split[a.Line] = true
+		item.WarehouseID = a.WarehouseID
+		item.Quantity = a.Quantity
+		if _, err := s.ChangeAt(a.WarehouseID, item.SKUID, -a.Quantity, internal.StockReasonOrder, orderID, "", "下单"); err != nil {
+			return nil, err
+		}
+		allocated = append(allocated, item)
+	}
+	return allocated, nil
+}
@@ -0,0 +1,91 @@
+package miniprogram
+
+import (
+	"time"
+
+	"z26b-backend/internal"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BrowseHistoryGroup 按日期分组的足迹
+type BrowseHistoryGroup struct {
+	Date  string                   `json:"date"` // 2006-01-02
+	Items []internal.BrowseHistory `json:"items"`
+}
+
+type BrowseHistoryService struct {
+	db *gorm.DB
+}
+
+func NewBrowseHistoryService(db *gorm.DB) BrowseHistoryServiceInterface {
+	return &BrowseHistoryService{db: db}
+}
+
+// RecordView 记录浏览，同一商品只更新浏览时间；超过 MaxBrowseHistory 条时删除最早的足迹
+func (s *BrowseHistoryService) RecordView(userID, spuID string) error {
+	history := internal.BrowseHistory{
+		ID:       internal.GenerateUUID(),
+		UserID:   userID,
+		SPUID:    spuID,
+		ViewedAt: time.Now().UnixMilli(),
+	}
+	err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "spu_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"viewed_at"}),
+	}).Create(&history).Error
+	if err != nil {
+		return err
+	}
+
+	return s.db.Where("user_id = ? AND id NOT IN (?)", userID,
+		s.db.Model(&internal.BrowseHistory{}).Select("id").Where("user_id = ?", userID).
+			Order("viewed_at DESC").Limit(internal.MaxBrowseHistory),
+	).Delete(&internal.BrowseHistory{}).Error
+}
+
+// GetHistory 获取足迹，按浏览时间倒序并按日期分组；已删除或已下架的商品不返回
+func (s *BrowseHistoryService) GetHistory(userID string, page, pageSize int) ([]BrowseHistoryGroup, int64, error) {
+	var histories []internal.BrowseHistory
+	var total int64
+
+	query := s.db.Model(&internal.BrowseHistory{}).
+		Joins("JOIN spu ON spu.id = browse_history.spu_id AND spu.deleted_at IS NULL").
+		Where("browse_history.user_id = ?", userID).
+		Scopes(internal.SPUVisibleAt(time.Now().UnixMilli()))
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Preload("SPU").Order("browse_history.viewed_at DESC").
+		Offset(offset).Limit(pageSize).Find(&histories).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	groups := []BrowseHistoryGroup{}
+	for _, h := range histories {
+		date := time.UnixMilli(h.ViewedAt).Format("2006-01-02")
+		if len(groups) == 0 || groups[len(groups)-1].Date != date {
+			groups = append(groups, BrowseHistoryGroup{Date: date})
+		}
+		last := &groups[len(groups)-1]
+		last.Items = append(last.Items, h)
+	}
+	return groups, total, nil
+}
+
+// DeleteHistory 删除指定商品的足迹
+func (s *BrowseHistoryService) DeleteHistory(userID string, spuIDs []string) error {
+	if len(spuIDs) == 0 {
+		return nil
+	}
+	return s.db.Where("user_id = ? AND spu_id IN ?", userID, spuIDs).Delete(&internal.BrowseHistory{}).Error
+}
+
+// ClearHistory 清空足迹
+func (s *BrowseHistoryService) ClearHistory(userID string) error {
+	return s.db.Where("user_id = ?", userID).Delete(&internal.BrowseHistory{}).Error
+}
@@ -0,0 +1,139 @@
+package miniprogram
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"z26b-backend/internal"
+
+	"gorm.io/gorm"
+)
+
+func TestRecordView(t *testing.T) {
+	tests := []struct {
+		name       string
+		seeded     int
+		spuID      string
+		wantCount  int64
+		wantOldest bool // 最早的足迹是否保留
+	}{
+		{"新商品", 3, "new", 4, true},
+		{"重复浏览只更新时间", 3, "p0", 3, true},
+		{"超过上限删除最早的足迹", internal.MaxBrowseHistory, "new", internal.MaxBrowseHistory, false},
+		{"上限内重复浏览不删除", internal.MaxBrowseHistory, "p0", internal.MaxBrowseHistory, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t, &internal.BrowseHistory{})
+			for i := 0; i < tt.seeded; i++ {
+				db.Create(&internal.BrowseHistory{ID: fmt.Sprintf("h%d", i), UserID: "u1", SPUID: fmt.Sprintf("p%d", i), ViewedAt: int64(i + 1)})
+			}
+			db.Create(&internal.BrowseHistory{ID: "other", UserID: "u2", SPUID: "p0", ViewedAt: 1})
+
+			if err := NewBrowseHistoryService(db).RecordView("u1", tt.spuID); err != nil {
+				t.Fatalf("RecordView() error = %v", err)
+			}
+			var count int64
+			db.Model(&internal.BrowseHistory{}).Where("user_id = ?", "u1").Count(&count)
+			if count != tt.wantCount {
+				t.Errorf("history count = %d, want %d", count, tt.wantCount)
+			}
+			var viewed internal.BrowseHistory
+			db.First(&viewed, "user_id = ? AND spu_id = ?", "u1", tt.spuID)
+			if viewed.ViewedAt < time.Now().Add(-time.Minute).UnixMilli() {
+				t.Errorf("viewed_at = %d, want now", viewed.ViewedAt)
+			}
+			var oldest int64
+			db.Model(&internal.BrowseHistory{}).Where("id = ?", "h0").Count(&oldest)
+			if (oldest == 1) != tt.wantOldest {
+				t.Errorf("oldest history kept = %v, want %v", oldest == 1, tt.wantOldest)
+			}
+			if n := countHistory(db, "u2"); n != 1 {
+				t.Errorf("other user history = %d, want 1", n)
+			}
+		})
+	}
+}
+
+func TestGetHistory(t *testing.T) {
+	db := newTestDB(t, &internal.SPU{}, &internal.BrowseHistory{})
+	day := time.Date(2024, 5, 2, 10, 0, 0, 0, time.Local)
+	views := []struct {
+		spu      internal.SPU
+		viewedAt time.Time
+	}{
+		{internal.SPU{ID: "today", Status: "ENABLED"}, day},
+		{internal.SPU{ID: "earlier", Status: "ENABLED"}, day.Add(-time.Hour)},
+		{internal.SPU{ID: "off", Status: "DISABLED"}, day.Add(-2 * time.Hour)},
+		{internal.SPU{ID: "deleted", Status: "ENABLED"}, day.Add(-3 * time.Hour)},
+		{internal.SPU{ID: "yesterday", Status: "ENABLED"}, day.Add(-24 * time.Hour)},
+	}
+	for _, v := range views {
+		db.Create(&v.spu)
+		db.Create(&internal.BrowseHistory{ID: "h-" + v.spu.ID, UserID: "u1", SPUID: v.spu.ID, ViewedAt: v.viewedAt.UnixMilli()})
+	}
+	db.Delete(&internal.SPU{}, "id = ?", "deleted")
+
+	groups, total, err := NewBrowseHistoryService(db).GetHistory("u1", 1, 10)
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+	got := map[string][]string{}
+	var dates []string
+	for _, g := range groups {
+		dates = append(dates, g.Date)
+		for _, item := range g.Items {
+			got[g.Date] = append(got[g.Date], item.SPUID)
+		}
+	}
+	want := map[string][]string{"2024-05-02": {"today", "earlier"}, "2024-05-01": {"yesterday"}}
+	if total != 3 || !reflect.DeepEqual(got, want) || !reflect.DeepEqual(dates, []string{"2024-05-02", "2024-05-01"}) {
+		t.Errorf("GetHistory() = %v (dates %v), total %d, want %v", got, dates, total, want)
+	}
+}
+
+func TestDeleteHistory(t *testing.T) {
+	tests := []struct {
+		name   string
+		spuIDs []string
+		clear  bool
+		want   int64
+	}{
+		{"删除指定商品", []string{"p1", "p2"}, false, 1},
+		{"空列表不删除", nil, false, 3},
+		{"清空", nil, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t, &internal.BrowseHistory{})
+			for _, id := range []string{"p1", "p2", "p3"} {
+				db.Create(&internal.BrowseHistory{ID: "u1-" + id, UserID: "u1", SPUID: id})
+				db.Create(&internal.BrowseHistory{ID: "u2-" + id, UserID: "u2", SPUID: id})
+			}
+			service := NewBrowseHistoryService(db)
+			var err error
+			if tt.clear {
+				err = service.ClearHistory("u1")
+			} else {
+				err = service.DeleteHistory("u1", tt.spuIDs)
+			}
+			if err != nil {
+				t.Fatalf("delete error = %v", err)
+			}
+			if n := countHistory(db, "u1"); n != tt.want {
+				t.Errorf("history count = %d, want %d", n, tt.want)
+			}
+			if n := countHistory(db, "u2"); n != 3 {
+				t.Errorf("other user history = %d, want 3", n)
+			}
+		})
+	}
+}
+
+func countHistory(db *gorm.DB, userID string) int64 {
+	var n int64
+	db.Model(&internal.BrowseHistory{}).Where("user_id = ?", userID).Count(&n)
+	return n
+}
@@ -0,0 +1,132 @@
+package miniprogram
+
+import (
+	"errors"
+	"time"
+
+	"z26b-backend/internal"
+	"z26b-backend/services/inventory"
+
+	"gorm.io/gorm"
+)
+
+// BundleLine 下单时选择的组合商品及套数
+type BundleLine struct {
+	BundleID string `json:"bundleId"`
+	Quantity int    `json:"quantity"`
+}
+
+type BundleService struct {
+	db *gorm.DB
+}
+
+func NewBundleService(db *gorm.DB) BundleServiceInterface {
+	return &BundleService{db: db}
+}
+
+// GetBundles 获取上架的组合商品列表
+func (s *BundleService) GetBundles(page, pageSize int) ([]internal.Bundle, int64, error) {
+	var bundles []internal.Bundle
+	var total int64
+
+	query := s.db.Model(&internal.Bundle{}).Where("status = ?", "ENABLED")
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Preload("Items.SKU").Order("priority DESC, created_at DESC").Offset(offset).Limit(pageSize).Find(&bundles).Error
+	for i := range bundles {
+		bundles[i].FillSummary()
+	}
+	return bundles, total, err
+}
+
+// GetBundle 获取组合商品详情（含组成 SKU 及所属商品）
+func (s *BundleService) GetBundle(id string) (*internal.Bundle, error) {
+	var bundle internal.Bundle
+	err := s.db.Preload("Items.SKU.SPU").Where("id = ? AND status = ?", id, "ENABLED").First(&bundle).Error
+	if err != nil {
+		return nil, err
+	}
+	bundle.FillSummary()
+	return &bundle, nil
+}
+
+// expandBundle 将组合商品展开为组成项订单明细，按原价占比分摊组合价，并在事务内扣减各组成 SKU 库存；
+// stock 为 nil 时不扣减（由仓库分配统一扣减）
+func expandBundle(tx *gorm.DB, stock *inventory.InventoryService, orderID string, line BundleLine) ([]internal.OrderItem, error) {
+	if line.Quantity <= 0 {
+		return nil, errors.New("invalid bundle quantity: " + line.BundleID)
+	}
+
+	var bundle internal.Bundle
+	if err := tx.Preload("Items.SKU").Where("id = ? AND status = ?", line.BundleID, "ENABLED").First(&bundle).Error; err != nil {
+		return nil, errors.New("invalid bundle: " + line.BundleID)
+	}
+	if len(bundle.Items) == 0 {
+		return nil, errors.New("bundle has no items: " + line.BundleID)
+	}
+
+	prices := make([]float64, len(bundle.Items))
+	quantities := make([]int, len(bundle.Items))
+	for i, item := range bundle.Items {
+		if item.SKU == nil {
+			return nil, errors.New("invalid sku in bundle: " + item.SKUID)
+		}
+		prices[i] = item.SKU.Price
+		quantities[i] = item.Quantity
+	}
+	shares := internal.ProrateBundlePrice(bundle.Price, prices, quantities)
+
+	// 条件扣减，避免并发超卖
+	if stock != nil {
+		for _, item := range bundle.Items {
+			if _, err := stock.Change(item.SKUID, -item.Quantity*line.Quantity, internal.StockReasonOrder, orderID, "", "组合商品下单"); err != nil {
+				if errors.Is(err, inventory.ErrInsufficientStock) {
+					return nil, errors.New("insufficient stock for bundle: " + bundle.Name)
+				}
+				return nil, err
+			}
+		}
+	}
+
+	// 承担尾差的组成项会拆成两条明细
+	items := make([]internal.OrderItem, 0, len(shares))
+	for _, share := range shares {
+		items = append(items, internal.OrderItem{
+			ID:       internal.GenerateUUID(),
+			SKUID:    bundle.Items[share.Index].SKUID,
+			BundleID: bundle.ID,
+			Quantity: share.Quantity * line.Quantity,
+			Price:    share.UnitPrice,
+		})
+	}
+	return items, nil
+}
+
+// createShipments 按分配的仓库为订单创建发货单，并设置明细所属发货单
+func createShipments(tx *gorm.DB, orderID string, items []internal.OrderItem) error {
+	now := time.Now().UnixMilli()
+	byWarehouse := make(map[string]string)
+	for i := range items {
+		shipmentID, ok := byWarehouse[items[i].WarehouseID]
+		if !ok {
+			shipment := internal.OrderShipment{
+				ID:          internal.GenerateUUID(),
+				OrderID:     orderID,
+				WarehouseID: items[i].WarehouseID,
+				Status:      internal.ShipmentStatusPending,
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			}
+			if err := tx.Create(&shipment).Error; err != nil {
+				return err
+			}
+			shipmentID = shipment.ID
+			byWarehouse[items[i].WarehouseID] = shipmentID
+		}
+		items[i].ShipmentID = shipmentID
+	}
+	return nil
+}
@@ -19,8 +19,18 @@ func NewCartService(db *gorm.DB) CartServiceInterface {
 // GetCartItems 获取用户购物车商品
 func (s *CartService) GetCartItems(userID string) ([]internal.CartItem, error) {
 	var items []internal.CartItem
-	err := s.db.Preload("SKU").Preload("SKU.SPU").Where("user_id = ?", userID).Find(&items).Error
-	return items, err
+	if err := s.db.Preload("SKU").Preload("SKU.SPU").Where("user_id = ?", userID).Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	// 过滤已删除的商品/SKU
+	available := make([]internal.CartItem, 0, len(items))
+	for _, item := range items {
+		if item.SKU != nil && item.SKU.SPU != nil {
+			available = append(available, item)
+		}
+	}
+	return available, nil
 }
 
 // AddToCart 添加商品到购物车
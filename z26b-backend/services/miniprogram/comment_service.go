@@ -1,25 +1,53 @@
 package miniprogram
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
 	"z26b-backend/internal"
+	"z26b-backend/services/moderation"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrOrderItemNotFound = errors.New("order item not found")
+	ErrOrderNotFinished  = errors.New("only finished orders can be reviewed")
+	ErrAlreadyReviewed   = errors.New("this item has already been reviewed")
+	ErrMediaQuota        = errors.New("daily upload limit reached")
+	ErrInvalidMedia      = errors.New("invalid or already used media")
 )
 
 type CommentService struct {
-	db *gorm.DB
+	db         *gorm.DB
+	moderation moderation.ModerationServiceInterface
 }
 
-func NewCommentService(db *gorm.DB) CommentServiceInterface {
-	return &CommentService{db: db}
+func NewCommentService(db *gorm.DB, moderationService moderation.ModerationServiceInterface) CommentServiceInterface {
+	return &CommentService{db: db, moderation: moderationService}
 }
 
-// GetGoodsComments 获取商品评论
-func (s *CommentService) GetGoodsComments(spuID string, page, pageSize int) ([]internal.Comment, int64, error) {
+// CommentQuery 商品评论筛选条件
+type CommentQuery struct {
+	Score     int  // 评分 1-5，0 表示不限
+	WithMedia bool // 只看带图片/视频的评论
+}
+
+// GetGoodsComments 获取商品审核通过的评论
+func (s *CommentService) GetGoodsComments(spuID string, q CommentQuery, page, pageSize int) ([]internal.Comment, int64, error) {
 	var comments []internal.Comment
 	var total int64
 
-	query := s.db.Where("spu_id = ?", spuID)
+	query := s.db.Where("spu_id = ? AND status = ?", spuID, internal.CommentStatusApproved)
+	if q.Score > 0 {
+		query = query.Where("comment_score = ?", q.Score)
+	}
+	if q.WithMedia {
+		query = query.Where("has_media = ?", true)
+	}
 	err := query.Model(&internal.Comment{}).Count(&total).Error
 	if err != nil {
 		return nil, 0, err
@@ -30,9 +58,234 @@ func (s *CommentService) GetGoodsComments(spuID string, page, pageSize int) ([]i
 	return comments, total, err
 }
 
-// CreateComment 创建评论
-func (s *CommentService) CreateComment(comment *internal.Comment) error {
-	return s.db.Create(comment).Error
+// ReviewInput 提交评价的参数，通过 OrderItemID 或 OrderID+SKUID 指定评价的订单商品
+type ReviewInput struct {
+	OrderID     string
+	OrderItemID string
+	SKUID       string
+	Content     string
+	Score       int
+	IsAnonymity bool
+	MediaIDs    []string // 已上传的图片/视频，按展示顺序
+}
+
+// SubmitReview 评价已完成订单中的商品：每个订单商品只能评价一次，全部评价后标记订单已评价
+func (s *CommentService) SubmitReview(user *internal.User, in ReviewInput) (*internal.Comment, error) {
+	in.Content = strings.TrimSpace(in.Content)
+	if in.Content == "" {
+		return nil, errors.New("content is required")
+	}
+	if in.Score < 1 || in.Score > 5 {
+		return nil, errors.New("score must be between 1 and 5")
+	}
+	if in.OrderItemID == "" && (in.OrderID == "" || in.SKUID == "") {
+		return nil, errors.New("orderItemId is required")
+	}
+	moderated, err := s.moderation.Check(in.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	var comment *internal.Comment
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var order internal.Order
+		var item internal.OrderItem
+		query := tx.Model(&internal.OrderItem{}).
+			Joins(`JOIN "order" ON "order".id = order_item.order_id`).
+			Where(`"order".user_id = ?`, user.ID)
+		if in.OrderItemID != "" {
+			query = query.Where("order_item.id = ?", in.OrderItemID)
+		}
+		if in.OrderID != "" {
+			query = query.Where("order_item.order_id = ?", in.OrderID)
+		}
+		if in.OrderItemID == "" {
+			// 同一 SKU 可能拆成多条明细（如来自组合商品），优先未评价的
+			query = query.Where("order_item.sku_id = ?", in.SKUID).Order("COALESCE(order_item.comment_id, '') ASC")
+		}
+		if err := query.Preload("SKU", internal.WithDeleted).Limit(1).Find(&item).Error; err != nil {
+			return err
+		}
+		if item.ID == "" {
+			return ErrOrderItemNotFound
+		}
+		if err := tx.Select("id", "status").First(&order, "id = ?", item.OrderID).Error; err != nil {
+			return err
+		}
+		if order.Status != internal.OrderStatusFinished {
+			return ErrOrderNotFinished
+		}
+		if item.CommentID != "" {
+			return ErrAlreadyReviewed
+		}
+
+		comment = &internal.Comment{
+			ID:               internal.GenerateUUID(),
+			SKUID:            item.SKUID,
+			OrderID:          item.OrderID,
+			OrderItemID:      item.ID,
+			VerifiedPurchase: true,
+			UserID:           user.ID,
+			UserName:         user.NickName,
+			UserHeadURL:      user.Avatar,
+			CommentContent:   in.Content,
+			CommentScore:     in.Score,
+			IsAnonymity:      in.IsAnonymity,
+			CreatedAt:        time.Now().Unix(),
+		}
+		if item.SKU != nil {
+			comment.SPUID = item.SKU.SPUID
+		}
+		if in.IsAnonymity {
+			comment.UserName = internal.MaskUserName(user.NickName)
+			comment.UserHeadURL = ""
+		}
+		applyModeration(comment, moderated)
+		media, err := s.attachableMedia(tx, user.ID, in.MediaIDs)
+		if err != nil {
+			return err
+		}
+		if len(media) > 0 {
+			resources := make([]internal.CommentResource, 0, len(media))
+			for _, m := range media {
+				resources = append(resources, internal.CommentResource{Src: m.URL, Type: m.Type})
+			}
+			comment.CommentResources = internal.ToJSON(resources)
+			comment.HasMedia = true
+		}
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		if len(media) > 0 {
+			// 条件更新，并发提交时同一图片/视频只能被一条评价使用
+			result := tx.Model(&internal.ReviewMedia{}).
+				Where("id IN ? AND user_id = ? AND (comment_id IS NULL OR comment_id = '')", in.MediaIDs, user.ID).
+				Update("comment_id", comment.ID)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected != int64(len(in.MediaIDs)) {
+				return ErrInvalidMedia
+			}
+		}
+
+		result := tx.Model(&internal.OrderItem{}).
+			Where("id = ? AND (comment_id IS NULL OR comment_id = '')", item.ID).
+			Update("comment_id", comment.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAlreadyReviewed
+		}
+
+		var pending int64
+		if err := tx.Model(&internal.OrderItem{}).
+			Where("order_id = ? AND (comment_id IS NULL OR comment_id = '')", item.OrderID).
+			Count(&pending).Error; err != nil {
+			return err
+		}
+		if pending > 0 {
+			return nil
+		}
+		return tx.Model(&internal.Order{}).Where("id = ?", item.OrderID).Update("reviewed_at", time.Now().UnixMilli()).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// attachableMedia 校验评价附带的图片/视频属于该用户且未被使用，按 ids 顺序返回
+func (s *CommentService) attachableMedia(tx *gorm.DB, userID string, ids []string) ([]internal.ReviewMedia, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	if len(ids) > internal.MaxReviewMedia {
+		return nil, fmt.Errorf("at most %d images or videos per review", internal.MaxReviewMedia)
+	}
+
+	var media []internal.ReviewMedia
+	err := tx.Where("id IN ? AND user_id = ? AND (comment_id IS NULL OR comment_id = '')", ids, userID).Find(&media).Error
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]internal.ReviewMedia, len(media))
+	for _, m := range media {
+		byID[m.ID] = m
+	}
+
+	ordered := make([]internal.ReviewMedia, 0, len(ids))
+	videos := 0
+	for _, id := range ids {
+		m, ok := byID[id]
+		if !ok {
+			return nil, ErrInvalidMedia
+		}
+		delete(byID, id) // 重复的ID视为无效
+		if m.Type == internal.MediaTypeVideo {
+			videos++
+		}
+		ordered = append(ordered, m)
+	}
+	if videos > internal.MaxReviewVideos {
+		return nil, fmt.Errorf("at most %d video per review", internal.MaxReviewVideos)
+	}
+	return ordered, nil
+}
+
+// CheckMediaQuota 检查用户当天的上传次数和总大小是否超出配额，用于上传文件前提前拒绝
+func (s *CommentService) CheckMediaQuota(userID string, size int64) error {
+	return checkMediaQuota(s.db, userID, size)
+}
+
+func checkMediaQuota(db *gorm.DB, userID string, size int64) error {
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).UnixMilli()
+
+	var usage struct {
+		Count int64
+		Bytes int64
+	}
+	err := db.Model(&internal.ReviewMedia{}).
+		Select("COUNT(*) AS count, COALESCE(SUM(size), 0) AS bytes").
+		Where("user_id = ? AND created_at >= ?", userID, startOfDay).
+		Scan(&usage).Error
+	if err != nil {
+		return err
+	}
+	if usage.Count >= internal.ReviewMediaDailyCount || usage.Bytes+size > internal.ReviewMediaDailyBytes {
+		return ErrMediaQuota
+	}
+	return nil
+}
+
+// SaveMedia 记录上传的评价图片/视频；在同一事务中锁定用户后再次检查配额并写入，
+// 同一用户的并发上传依次检查，超出配额时返回 ErrMediaQuota
+func (s *CommentService) SaveMedia(media *internal.ReviewMedia) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		// SQLite 不支持行锁，写事务本身是串行的
+		var user internal.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&user, "id = ?", media.UserID).Error; err != nil {
+			return err
+		}
+		if err := checkMediaQuota(tx, media.UserID, media.Size); err != nil {
+			return err
+		}
+		media.ID = internal.GenerateUUID()
+		media.CreatedAt = time.Now().UnixMilli()
+		return tx.Create(media).Error
+	})
+}
+
+// applyModeration 按敏感词过滤结果设置评论内容和审核状态
+func applyModeration(comment *internal.Comment, result *moderation.Result) {
+	comment.CommentContent = result.Content
+	comment.Status = result.Status
+	comment.SensitiveWords = strings.Join(result.Words, ",")
+	if result.Status == internal.CommentStatusRejected {
+		comment.RejectReason = "包含违禁词"
+	}
 }
 
 // GetCommentByID 根据ID获取评论
@@ -0,0 +1,60 @@
+package miniprogram
+
+import (
+	"errors"
+	"testing"
+
+	"z26b-backend/internal"
+	"z26b-backend/services/moderation"
+)
+
+func TestSubmitReviewMedia(t *testing.T) {
+	tests := []struct {
+		name     string
+		mediaIDs []string
+		wantErr  error
+	}{
+		{"本人未使用的图片", []string{"m1", "m2"}, nil},
+		{"已被其他评价使用", []string{"m1", "used"}, ErrInvalidMedia},
+		{"其他用户的图片", []string{"other"}, ErrInvalidMedia},
+		{"重复的图片", []string{"m1", "m1"}, ErrInvalidMedia},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t, &internal.User{}, &internal.SPU{}, &internal.SKU{}, &internal.Order{}, &internal.OrderItem{},
+				&internal.Comment{}, &internal.ReviewMedia{}, &internal.SensitiveWord{})
+			user := &internal.User{ID: "u1", OpenID: "o1", NickName: "张三"}
+			db.Create(user)
+			db.Create(&internal.SPU{ID: "p1"})
+			db.Create(&internal.SKU{ID: "k1", SPUID: "p1"})
+			db.Create(&internal.Order{ID: "o1", UserID: "u1", Status: internal.OrderStatusFinished})
+			db.Create(&internal.OrderItem{ID: "i1", OrderID: "o1", SKUID: "k1", Quantity: 1})
+			db.Create(&[]internal.ReviewMedia{
+				{ID: "m1", UserID: "u1", Type: internal.MediaTypeImage, URL: "/m1.jpg"},
+				{ID: "m2", UserID: "u1", Type: internal.MediaTypeImage, URL: "/m2.jpg"},
+				{ID: "used", UserID: "u1", CommentID: "c0", Type: internal.MediaTypeImage, URL: "/used.jpg"},
+				{ID: "other", UserID: "u2", Type: internal.MediaTypeImage, URL: "/other.jpg"},
+			})
+			service := NewCommentService(db, moderation.NewModerationService(db))
+
+			comment, err := service.SubmitReview(user, ReviewInput{OrderItemID: "i1", Content: "很好", Score: 5, MediaIDs: tt.mediaIDs})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SubmitReview() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var attached int64
+			db.Model(&internal.ReviewMedia{}).Where("user_id = ? AND comment_id <> '' AND id <> ?", "u1", "used").Count(&attached)
+			if tt.wantErr != nil {
+				var comments int64
+				db.Model(&internal.Comment{}).Count(&comments)
+				if comments != 0 || attached != 0 {
+					t.Errorf("comments = %d, attached media = %d, want 0 after failed review", comments, attached)
+				}
+				return
+			}
+			if !comment.HasMedia || attached != int64(len(tt.mediaIDs)) {
+				t.Errorf("HasMedia = %v, attached media = %d, want %d", comment.HasMedia, attached, len(tt.mediaIDs))
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package miniprogram
+
+import (
+	"errors"
+	"time"
+
+	"z26b-backend/internal"
+
+	"gorm.io/gorm"
+)
+
+// FavoriteItem 收藏列表项：收藏时的快照及商品当前价格、库存
+type FavoriteItem struct {
+	internal.Favorite
+	MinPrice  float64 `json:"minPrice"`  // 当前最低价
+	MaxPrice  float64 `json:"maxPrice"`  // 当前最高价
+	Stock     int     `json:"stock"`     // 当前总库存
+	Available bool    `json:"available"` // 商品仍在售
+}
+
+type FavoriteService struct {
+	db *gorm.DB
+}
+
+func NewFavoriteService(db *gorm.DB) FavoriteServiceInterface {
+	return &FavoriteService{db: db}
+}
+
+// AddFavorite 收藏商品，已收藏时返回原记录且 created 为 false
+func (s *FavoriteService) AddFavorite(userID, spuID string) (*internal.Favorite, bool, error) {
+	var spu internal.SPU
+	if err := s.db.First(&spu, "id = ?", spuID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, errors.New("product not found")
+		}
+		return nil, false, err
+	}
+
+	var existing internal.Favorite
+	if err := s.db.Where("user_id = ? AND spu_id = ?", userID, spuID).Limit(1).Find(&existing).Error; err != nil {
+		return nil, false, err
+	}
+	if existing.ID != "" {
+		return &existing, false, nil
+	}
+
+	favorite := internal.Favorite{
+		ID:         internal.GenerateUUID(),
+		UserID:     userID,
+		SPUID:      spuID,
+		Name:       spu.Name,
+		CoverImage: spu.CoverImage,
+		Price:      spu.MinPrice,
+		CreatedAt:  time.Now().UnixMilli(),
+	}
+	if err := s.db.Create(&favorite).Error; err != nil {
+		return nil, false, err
+	}
+	return &favorite, true, nil
+}
+
+// RemoveFavorite 取消收藏
+func (s *FavoriteService) RemoveFavorite(userID, spuID string) error {
+	return s.db.Where("user_id = ? AND spu_id = ?", userID, spuID).Delete(&internal.Favorite{}).Error
+}
+
+// GetFavorites 获取用户收藏列表，按收藏时间倒序；已删除或下架的商品 available 为 false
+func (s *FavoriteService) GetFavorites(userID string, page, pageSize int) ([]FavoriteItem, int64, error) {
+	var favorites []internal.Favorite
+	var total int64
+
+	query := s.db.Model(&internal.Favorite{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	if err := query.Preload("SPU", internal.WithDeleted).Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&favorites).Error; err != nil {
+		return nil, 0, err
+	}
+
+	spuIDs := make([]string, 0, len(favorites))
+	for _, f := range favorites {
+		spuIDs = append(spuIDs, f.SPUID)
+	}
+	var stocks []struct {
+		SPUID string `gorm:"column:spu_id"`
+		Stock int
+	}
+	if len(spuIDs) > 0 {
+		err := s.db.Model(&internal.SKU{}).Select(`"SPUID" AS spu_id, COALESCE(SUM(count), 0) AS stock`).
+			Where(`"SPUID" IN ?`, spuIDs).Group(`sku."SPUID"`).Scan(&stocks).Error
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	stockBySPU := make(map[string]int, len(stocks))
+	for _, st := range stocks {
+		stockBySPU[st.SPUID] = st.Stock
+	}
+
+	now := time.Now().UnixMilli()
+	items := make([]FavoriteItem, 0, len(favorites))
+	for _, f := range favorites {
+		item := FavoriteItem{Favorite: f}
+		if spu := f.SPU; spu != nil {
+			item.MinPrice = spu.MinPrice
+			item.MaxPrice = spu.MaxPrice
+			item.Stock = stockBySPU[f.SPUID]
+			item.Available = !spu.DeletedAt.Valid &&
+				internal.SPUEffectiveStatus(spu.Status, spu.PublishAt, spu.UnpublishAt, now) == "ENABLED"
+		}
+		items = append(items, item)
+	}
+	return items, total, nil
+}
+
+// IsFavorite 用户是否已收藏商品
+func (s *FavoriteService) IsFavorite(userID, spuID string) (bool, error) {
+	var count int64
+	err := s.db.Model(&internal.Favorite{}).Where("user_id = ? AND spu_id = ?", userID, spuID).Count(&count).Error
+	return count > 0, err
+}
+
+// CountFavorites 商品收藏数
+func (s *FavoriteService) CountFavorites(spuID string) (int64, error) {
+	var count int64
+	err := s.db.Model(&internal.Favorite{}).Where("spu_id = ?", spuID).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,123 @@
+package miniprogram
+
+import (
+	"testing"
+	"time"
+
+	"z26b-backend/internal"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB 创建独立的内存 SQLite 数据库并迁移指定模型
+func newTestDB(t *testing.T, models ...interface{}) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestAddFavorite(t *testing.T) {
+	tests := []struct {
+		name        string
+		spuID       string
+		wantCreated bool
+		wantErr     bool
+	}{
+		{"新收藏", "p1", true, false},
+		{"重复收藏", "p2", false, false},
+		{"商品不存在", "missing", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t, &internal.SPU{}, &internal.Favorite{})
+			db.Create(&internal.SPU{ID: "p1", Name: "T恤", CoverImage: "p1.jpg", MinPrice: 59})
+			db.Create(&internal.SPU{ID: "p2", Name: "衬衫"})
+			db.Create(&internal.Favorite{ID: "f2", UserID: "u1", SPUID: "p2"})
+
+			service := NewFavoriteService(db)
+			favorite, created, err := service.AddFavorite("u1", tt.spuID)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("AddFavorite() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AddFavorite() error = %v", err)
+			}
+			if created != tt.wantCreated || favorite.SPUID != tt.spuID {
+				t.Errorf("AddFavorite() = %+v, %v, want created %v", favorite, created, tt.wantCreated)
+			}
+			if created && (favorite.Name != "T恤" || favorite.CoverImage != "p1.jpg" || favorite.Price != 59) {
+				t.Errorf("AddFavorite() snapshot = %+v", favorite)
+			}
+			if count, _ := service.CountFavorites(tt.spuID); count != 1 {
+				t.Errorf("CountFavorites() = %d, want 1", count)
+			}
+		})
+	}
+}
+
+func TestGetFavorites(t *testing.T) {
+	db := newTestDB(t, &internal.SPU{}, &internal.SKU{}, &internal.Favorite{})
+	future := time.Now().Add(time.Hour).UnixMilli()
+	past := time.Now().Add(-time.Hour).UnixMilli()
+	spus := []internal.SPU{
+		{ID: "on", Status: "ENABLED", MinPrice: 10, MaxPrice: 20},
+		{ID: "off", Status: "DISABLED"},
+		{ID: "scheduled", Status: "DISABLED", PublishAt: &past},
+		{ID: "expiring", Status: "ENABLED", UnpublishAt: &past},
+		{ID: "upcoming", Status: "ENABLED", UnpublishAt: &future},
+		{ID: "deleted", Status: "ENABLED"},
+	}
+	for i, spu := range spus {
+		db.Create(&spu)
+		db.Create(&internal.Favorite{ID: "f-" + spu.ID, UserID: "u1", SPUID: spu.ID, CreatedAt: int64(len(spus) - i)})
+	}
+	db.Create(&internal.SKU{ID: "k1", SPUID: "on", Count: 3})
+	db.Create(&internal.SKU{ID: "k2", SPUID: "on", Count: 4})
+	db.Create(&internal.Favorite{ID: "other", UserID: "u2", SPUID: "on"})
+	db.Delete(&internal.SPU{}, "id = ?", "deleted")
+
+	items, total, err := NewFavoriteService(db).GetFavorites("u1", 1, 10)
+	if err != nil {
+		t.Fatalf("GetFavorites() error = %v", err)
+	}
+	if total != int64(len(spus)) || len(items) != len(spus) {
+		t.Fatalf("GetFavorites() = %d items, total %d, want %d", len(items), total, len(spus))
+	}
+
+	tests := []struct {
+		spuID     string
+		available bool
+	}{
+		{"on", true},
+		{"off", false},
+		{"scheduled", true},
+		{"expiring", false},
+		{"upcoming", true},
+		{"deleted", false},
+	}
+	for i, tt := range tests {
+		t.Run(tt.spuID, func(t *testing.T) {
+			item := items[i]
+			if item.SPUID != tt.spuID {
+				t.Fatalf("items[%d] = %s, want %s (created_at DESC)", i, item.SPUID, tt.spuID)
+			}
+			if item.Available != tt.available {
+				t.Errorf("Available = %v, want %v", item.Available, tt.available)
+			}
+		})
+	}
+	if items[0].Stock != 7 || items[0].MinPrice != 10 || items[0].MaxPrice != 20 {
+		t.Errorf("current price/stock = %+v", items[0])
+	}
+}
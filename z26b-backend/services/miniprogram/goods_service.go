@@ -1,6 +1,8 @@
 package miniprogram
 
 import (
+	"time"
+
 	"z26b-backend/internal"
 
 	"gorm.io/gorm"
@@ -15,25 +17,49 @@ func NewGoodsService(db *gorm.DB) GoodsServiceInterface {
 	return &GoodsService{db: db}
 }
 
+// GoodsListQuery 商品列表查询条件
+type GoodsListQuery struct {
+	CategoryID string   // 包含子孙分类
+	Search     string   // 名称/详情模糊匹配
+	TagIDs     []string // 标签筛选
+	TagAll     bool     // 为 true 时需包含全部标签，否则命中任一标签
+	MinPrice   *float64 // 价格区间与商品价格范围有交集即命中
+	MaxPrice   *float64
+	Sort       string // internal.GoodsSort*，默认综合排序
+}
+
 // GetGoodsList 获取商品列表（小程序端）
-func (s *GoodsService) GetGoodsList(page, pageSize int, categoryID, search string) ([]internal.SPU, int64, error) {
+func (s *GoodsService) GetGoodsList(page, pageSize int, q GoodsListQuery) ([]internal.SPU, int64, error) {
 	var goods []internal.SPU
-	query := s.db.Where("status = ?", "ENABLED")
-
-	if categoryID != "" {
-		query = query.Where("category_id = ?", categoryID)
+	query := s.db.Model(&internal.SPU{}).Scopes(internal.SPUVisibleAt(time.Now().UnixMilli()))
+
+	// 按分类筛选时包含所有子孙分类下的商品
+	if q.CategoryID != "" {
+		var categories []internal.Category
+		if err := s.db.Select("id", "parent_id").Find(&categories).Error; err != nil {
+			return nil, 0, err
+		}
+		query = query.Where("spu.category_id IN ?", internal.CategoryDescendantIDs(categories, q.CategoryID))
 	}
-	if search != "" {
-		query = query.Where("name LIKE ? OR detail LIKE ?", "%"+search+"%", "%"+search+"%")
+	if q.Search != "" {
+		query = query.Where("spu.name LIKE ? OR spu.detail LIKE ?", "%"+q.Search+"%", "%"+q.Search+"%")
 	}
+	query = query.Scopes(internal.SPUWithTags(q.TagIDs, q.TagAll), internal.SPUPriceBetween(q.MinPrice, q.MaxPrice))
 
 	var total int64
-	query.Model(&internal.SPU{}).Count(&total)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
 
 	offset := (page - 1) * pageSize
-	err := query.Offset(offset).Limit(pageSize).Find(&goods).Error
-
-	return goods, total, err
+	err := query.Select("spu.*").Scopes(internal.SPUSortBy(q.Sort)).Offset(offset).Limit(pageSize).Find(&goods).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := internal.LoadSPUTags(s.db, goods); err != nil {
+		return nil, 0, err
+	}
+	return goods, total, nil
 }
 
 // GetGoodDetail 获取商品详情
@@ -42,6 +68,14 @@ func (s *GoodsService) GetGoodDetail(id string) (*internal.SPU, []internal.SKU,
 	if err := s.db.First(&good, "id = ?", id).Error; err != nil {
 		return nil, nil, err
 	}
+	// 定时上下架到期但定时任务尚未执行时，按实际状态返回
+	good.Status = internal.SPUEffectiveStatus(good.Status, good.PublishAt, good.UnpublishAt, time.Now().UnixMilli())
+
+	spus := []internal.SPU{good}
+	if err := internal.LoadSPUTags(s.db, spus); err != nil {
+		return nil, nil, err
+	}
+	good.Tags = spus[0].Tags
 
 	var skus []internal.SKU
 	err := s.db.Where(`"SPUID" = ?`, id).Find(&skus).Error
@@ -49,6 +83,20 @@ func (s *GoodsService) GetGoodDetail(id string) (*internal.SPU, []internal.SKU,
 	return &good, skus, err
 }
 
+// GetLowestPrice 商品近 30 天的最低价（所有 SKU），用于价格展示合规
+func (s *GoodsService) GetLowestPrice(spuID string) (float64, error) {
+	var current []float64
+	if err := s.db.Model(&internal.SKU{}).Where(`"SPUID" = ?`, spuID).Pluck("price", &current).Error; err != nil {
+		return 0, err
+	}
+	since := time.Now().Add(-internal.LowestPriceWindow).UnixMilli()
+	var changes []internal.SKUPriceHistory
+	if err := s.db.Where("spu_id = ? AND effective_at >= ?", spuID, since).Find(&changes).Error; err != nil {
+		return 0, err
+	}
+	return internal.LowestPriceSince(current, changes, since), nil
+}
+
 // GetSKUDetail 获取SKU详情
 func (s *GoodsService) GetSKUDetail(id string) (*internal.SKU, error) {
 	var sku internal.SKU
@@ -70,18 +118,25 @@ func (s *GoodsService) GetCategories() ([]internal.Category, error) {
 	return categories, err
 }
 
-// SearchGoods 搜索商品
-func (s *GoodsService) SearchGoods(keyword string, page, pageSize int) ([]internal.SPU, int64, error) {
-	var goods []internal.SPU
-	query := s.db.Where("status = ? AND (name LIKE ? OR detail LIKE ?)", "ENABLED", "%"+keyword+"%", "%"+keyword+"%")
-
-	var total int64
-	query.Model(&internal.SPU{}).Count(&total)
-
-	offset := (page - 1) * pageSize
-	err := query.Offset(offset).Limit(pageSize).Find(&goods).Error
+// GetCategoryTree 获取树形分类
+func (s *GoodsService) GetCategoryTree() ([]internal.Category, error) {
+	categories, err := s.GetCategories()
+	if err != nil {
+		return nil, err
+	}
+	return internal.BuildCategoryTree(categories), nil
+}
 
-	return goods, total, err
+// GetCategoryPath 获取分类面包屑（从根分类到当前分类）
+func (s *GoodsService) GetCategoryPath(categoryID string) ([]internal.Category, error) {
+	if categoryID == "" {
+		return []internal.Category{}, nil
+	}
+	categories, err := s.GetCategories()
+	if err != nil {
+		return nil, err
+	}
+	return internal.CategoryPath(categories, categoryID), nil
 }
 
 // GetHomeSwiper 获取首页轮播图
@@ -101,11 +156,9 @@ func (s *GoodsService) GetHomeContent(key string) (*internal.HomeContent, error)
 	return &content, nil
 }
 
-// GetHomeCategories 获取首页分类
+// GetHomeCategories 获取首页分类（一级分类，附带子分类树）
 func (s *GoodsService) GetHomeCategories() ([]internal.Category, error) {
-	var categories []internal.Category
-	err := s.db.Where("parent_id IS NULL OR parent_id = ''").Order("sort ASC").Find(&categories).Error
-	return categories, err
+	return s.GetCategoryTree()
 }
 
 // GetPromotions 获取促销活动
@@ -6,12 +6,14 @@ import (
 
 // GoodsService 商品服务接口
 type GoodsServiceInterface interface {
-	GetGoodsList(page, pageSize int, categoryID, search string) ([]internal.SPU, int64, error)
+	GetGoodsList(page, pageSize int, q GoodsListQuery) ([]internal.SPU, int64, error)
 	GetGoodDetail(id string) (*internal.SPU, []internal.SKU, error)
+	GetLowestPrice(spuID string) (float64, error)
 	GetSKUDetail(id string) (*internal.SKU, error)
 	GetSKUsBySpuID(spuID string) ([]internal.SKU, error)
 	GetCategories() ([]internal.Category, error)
-	SearchGoods(keyword string, page, pageSize int) ([]internal.SPU, int64, error)
+	GetCategoryTree() ([]internal.Category, error)
+	GetCategoryPath(categoryID string) ([]internal.Category, error)
 	GetHomeSwiper() ([]internal.Swiper, error)
 	GetHomeContent(key string) (*internal.HomeContent, error)
 	GetHomeCategories() ([]internal.Category, error)
@@ -40,23 +42,60 @@ type CartServiceInterface interface {
 type OrderServiceInterface interface {
 	GetOrderList(userID, status string, page, pageSize int) ([]internal.Order, int64, error)
 	GetOrderDetail(orderID, userID string) (*internal.Order, error)
-	CreateOrder(userID string, items []internal.OrderItem, addressID string) (*internal.Order, error)
+	CreateOrder(userID string, items []internal.OrderItem, bundles []BundleLine, addressID string, balanceAmount float64) (*internal.Order, error)
 	UpdateOrderStatus(orderID, userID, status string) error
+	ConfirmReceipt(orderID, userID string) error
 	CancelOrder(orderID, userID string) error
 	GetAdminOrderList(status, orderNo, userID string, page, pageSize int) ([]map[string]interface{}, int64, error)
 	UpdateAdminOrderStatus(orderID, status string) error
 }
 
+// BundleService 组合商品服务接口
+type BundleServiceInterface interface {
+	GetBundles(page, pageSize int) ([]internal.Bundle, int64, error)
+	GetBundle(id string) (*internal.Bundle, error)
+}
+
 // CommentService 评论服务接口
 type CommentServiceInterface interface {
-	GetGoodsComments(spuID string, page, pageSize int) ([]internal.Comment, int64, error)
-	CreateComment(comment *internal.Comment) error
+	GetGoodsComments(spuID string, q CommentQuery, page, pageSize int) ([]internal.Comment, int64, error)
+	SubmitReview(user *internal.User, in ReviewInput) (*internal.Comment, error)
+	CheckMediaQuota(userID string, size int64) error
+	SaveMedia(media *internal.ReviewMedia) error
 	GetCommentByID(id string) (*internal.Comment, error)
 	UpdateComment(id string, updates map[string]interface{}) error
 	DeleteComment(id string) error
 	GetCommentsByUser(userID string, page, pageSize int) ([]internal.Comment, int64, error)
 }
 
+// FavoriteService 收藏服务接口
+type FavoriteServiceInterface interface {
+	AddFavorite(userID, spuID string) (*internal.Favorite, bool, error)
+	RemoveFavorite(userID, spuID string) error
+	GetFavorites(userID string, page, pageSize int) ([]FavoriteItem, int64, error)
+	IsFavorite(userID, spuID string) (bool, error)
+	CountFavorites(spuID string) (int64, error)
+}
+
+// BrowseHistoryService 浏览足迹服务接口
+type BrowseHistoryServiceInterface interface {
+	RecordView(userID, spuID string) error
+	GetHistory(userID string, page, pageSize int) ([]BrowseHistoryGroup, int64, error)
+	DeleteHistory(userID string, spuIDs []string) error
+	ClearHistory(userID string) error
+}
+
+// QuestionService 商品问答服务接口
+type QuestionServiceInterface interface {
+	AskQuestion(user *internal.User, spuID, content string) (*internal.ProductQuestion, error)
+	AnswerQuestion(user *internal.User, questionID, content string) (*internal.ProductAnswer, error)
+	GetQuestions(spuID string, page, pageSize int) ([]internal.ProductQuestion, int64, error)
+	GetQuestion(id string) (*internal.ProductQuestion, error)
+	GetMyQuestions(userID string, page, pageSize int) ([]internal.ProductQuestion, int64, error)
+	MarkHelpful(userID, answerID string) (int, error)
+	CountQuestions(spuID string) (int64, error)
+}
+
 // AddressService 地址服务接口
 type AddressServiceInterface interface {
 	GetAddressList(userID string) ([]internal.Address, error)
@@ -69,6 +108,6 @@ type AddressServiceInterface interface {
 
 // WechatService 微信服务接口
 type WechatServiceInterface interface {
-	WxLogin(code string) (map[string]interface{}, error)
+	WxLogin(code, referralCode string) (map[string]interface{}, error)
 	GenerateSign(params map[string]interface{}, key string) string
 }
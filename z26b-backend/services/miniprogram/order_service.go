@@ -3,19 +3,26 @@ package miniprogram
 import (
 	"encoding/json"
 	"errors"
+	"math"
 	"time"
 
 	"z26b-backend/internal"
+	"z26b-backend/services/distribution"
+	"z26b-backend/services/inventory"
+	"z26b-backend/services/wallet"
 
 	"gorm.io/gorm"
 )
 
 type OrderService struct {
-	db *gorm.DB
+	db                *gorm.DB
+	walletService     *wallet.WalletService
+	inventoryService  *inventory.InventoryService
+	commissionService *distribution.CommissionService
 }
 
-func NewOrderService(db *gorm.DB) OrderServiceInterface {
-	return &OrderService{db: db}
+func NewOrderService(db *gorm.DB, walletService *wallet.WalletService, inventoryService *inventory.InventoryService, commissionService *distribution.CommissionService) OrderServiceInterface {
+	return &OrderService{db: db, walletService: walletService, inventoryService: inventoryService, commissionService: commissionService}
 }
 
 // GetOrderList 获取用户订单列表
@@ -34,19 +41,19 @@ func (s *OrderService) GetOrderList(userID, status string, page, pageSize int) (
 	}
 
 	offset := (page - 1) * pageSize
-	err = query.Preload("Items.SKU").Offset(offset).Limit(pageSize).Order("created_at DESC").Find(&orders).Error
+	err = query.Preload("Items.SKU", internal.WithDeleted).Offset(offset).Limit(pageSize).Order("created_at DESC").Find(&orders).Error
 	return orders, total, err
 }
 
 // GetOrderDetail 获取订单详情
 func (s *OrderService) GetOrderDetail(orderID, userID string) (*internal.Order, error) {
 	var order internal.Order
-	err := s.db.Preload("Items.SKU.SPU").Where("id = ? AND user_id = ?", orderID, userID).First(&order).Error
+	err := s.db.Preload("Items.SKU", internal.WithDeleted).Preload("Items.SKU.SPU", internal.WithDeleted).Where("id = ? AND user_id = ?", orderID, userID).First(&order).Error
 	return &order, err
 }
 
-// CreateOrder 创建订单
-func (s *OrderService) CreateOrder(userID string, items []internal.OrderItem, addressID string) (*internal.Order, error) {
+// CreateOrder 创建订单，bundles 为组合商品（展开为组成项明细），balanceAmount 为使用储值余额支付的金额（超过订单金额时按订单金额扣除）
+func (s *OrderService) CreateOrder(userID string, items []internal.OrderItem, bundles []BundleLine, addressID string, balanceAmount float64) (*internal.Order, error) {
 	// 验证地址 (暂时注释掉)
 	// var address internal.Address
 	// if err := s.db.First(&address, "id = ? AND user_id = ?", addressID, userID).Error; err != nil {
@@ -59,14 +66,13 @@ func (s *OrderService) CreateOrder(userID string, items []internal.OrderItem, ad
 	// 计算总价
 	var totalPrice float64
 	for i := range items {
+		if items[i].Quantity <= 0 {
+			return nil, errors.New("invalid quantity: " + items[i].SKUID)
+		}
 		var sku internal.SKU
 		if err := s.db.First(&sku, "id = ?", items[i].SKUID).Error; err != nil {
 			return nil, errors.New("invalid sku: " + items[i].SKUID)
 		}
-		// 检查库存 (暂时注释掉)
-		// if sku.Count < items[i].Quantity {
-		// 	return nil, errors.New("insufficient stock for sku: " + items[i].SKUID)
-		// }
 		items[i].Price = sku.Price
 		if items[i].Price == 0 {
 			items[i].Price = 1.0 // 默认价格为1，用于测试
@@ -74,6 +80,16 @@ func (s *OrderService) CreateOrder(userID string, items []internal.OrderItem, ad
 		totalPrice += items[i].Price * float64(items[i].Quantity)
 	}
 
+	// 组合商品按组合价计入总价
+	for _, line := range bundles {
+		var bundle internal.Bundle
+		if err := s.db.Where("id = ? AND status = ?", line.BundleID, "ENABLED").First(&bundle).Error; err != nil {
+			return nil, errors.New("invalid bundle: " + line.BundleID)
+		}
+		totalPrice += bundle.Price * float64(line.Quantity)
+	}
+	totalPrice = internal.RoundMoney(totalPrice)
+
 	// 创建订单
 	deliveryInfo := map[string]interface{}{
 		"name":          "Test User",
@@ -84,19 +100,43 @@ func (s *OrderService) CreateOrder(userID string, items []internal.OrderItem, ad
 		"districtName":  "Test District",
 		"detailAddress": "Test Address",
 	}
+	// 收货地址存在时使用实际地址，多仓库按省份分配发货仓库
+	var address internal.Address
+	if addressID != "" && s.db.First(&address, "id = ? AND user_id = ?", addressID, userID).Error == nil {
+		deliveryInfo = map[string]interface{}{
+			"name":          address.Name,
+			"phone":         address.Phone,
+			"countryName":   address.CountryName,
+			"provinceName":  address.ProvinceName,
+			"provinceCode":  address.ProvinceCode,
+			"cityName":      address.CityName,
+			"districtName":  address.DistrictName,
+			"detailAddress": address.DetailAddress,
+		}
+	}
 	deliveryJSON, _ := json.Marshal(deliveryInfo)
 	order := internal.Order{
-		ID:           internal.GenerateUUID(),
-		UserID:       userID,
-		Status:       internal.OrderStatusToSend, // 直接设置为待发货，跳过支付
-		DeliveryInfo: deliveryJSON,
-		TotalPrice:   totalPrice,
-		FinalPrice:   totalPrice,
+		ID:            internal.GenerateUUID(),
+		UserID:        userID,
+		Status:        internal.OrderStatusToSend, // 直接设置为待发货，跳过支付
+		DeliveryInfo:  deliveryJSON,
+		TotalPrice:    totalPrice,
+		FinalPrice:    totalPrice,
+		PaymentMethod: internal.PaymentMethodDirect,
 		// Items:        items, // 移除，因为单独创建
 		CreatedAt: time.Now().Unix(),
 		UpdatedAt: time.Now().Unix(),
 	}
 
+	// 储值余额支付（全部或部分）
+	if balanceAmount > 0 {
+		order.BalancePaid = internal.RoundMoney(math.Min(balanceAmount, order.FinalPrice))
+		order.PaymentMethod = internal.PaymentMethodMixed
+		if order.BalancePaid >= order.FinalPrice {
+			order.PaymentMethod = internal.PaymentMethodBalance
+		}
+	}
+
 	// 开始事务
 	tx := s.db.Begin()
 	defer func() {
@@ -115,14 +155,79 @@ func (s *OrderService) CreateOrder(userID string, items []internal.OrderItem, ad
 		items[i].OrderID = order.ID
 	}
 
+	stock := s.inventoryService.WithTx(tx)
+	multiWarehouse, err := stock.WarehousesEnabled()
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 单仓库：在事务中条件扣减普通明细库存，避免并发超卖；多仓库时由仓库分配统一扣减
+	if !multiWarehouse {
+		for _, item := range items {
+			if _, err := stock.Change(item.SKUID, -item.Quantity, internal.StockReasonOrder, order.ID, "", "下单"); err != nil {
+				tx.Rollback()
+				if errors.Is(err, inventory.ErrInsufficientStock) {
+					return nil, errors.New("insufficient stock")
+				}
+				return nil, err
+			}
+		}
+	}
+
+	// 展开组合商品并扣减组成项库存；多仓库时由仓库分配统一扣减
+	bundleStock := stock
+	if multiWarehouse {
+		bundleStock = nil
+	}
+	for _, line := range bundles {
+		bundleItems, err := expandBundle(tx, bundleStock, order.ID, line)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		for i := range bundleItems {
+			bundleItems[i].OrderID = order.ID
+		}
+		items = append(items, bundleItems...)
+	}
+
+	// 多仓库：为每个明细分配仓库并扣减仓库库存，按仓库拆分发货单
+	if multiWarehouse {
+		items, err = stock.AllocateOrder(order.ID, address.ProvinceName, address.ProvinceCode, items)
+		if errors.Is(err, inventory.ErrInsufficientStock) {
+			tx.Rollback()
+			return nil, errors.New("insufficient stock")
+		}
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if err := createShipments(tx, order.ID, items); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
 	// 创建 order items
 	if err := tx.Create(&items).Error; err != nil {
 		tx.Rollback()
 		return nil, err
 	}
 
+	// 扣减储值余额
+	if order.BalancePaid > 0 {
+		if _, err := s.walletService.WithTx(tx).Pay(userID, order.ID, order.BalancePaid); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
 	// 清空购物车中对应的商品
 	for _, item := range items {
+		if item.BundleID != "" {
+			continue
+		}
 		tx.Where("user_id = ? AND sku_id = ?", userID, item.SKUID).Delete(&internal.CartItem{})
 	}
 
@@ -130,7 +235,7 @@ func (s *OrderService) CreateOrder(userID string, items []internal.OrderItem, ad
 
 	// 重新获取订单，确保包含完整的items和关联数据
 	var createdOrder internal.Order
-	s.db.Preload("Items.SKU.SPU").First(&createdOrder, "id = ?", order.ID)
+	s.db.Preload("Items.SKU", internal.WithDeleted).Preload("Items.SKU.SPU", internal.WithDeleted).Preload("Shipments").First(&createdOrder, "id = ?", order.ID)
 
 	return &createdOrder, nil
 }
@@ -144,22 +249,52 @@ func (s *OrderService) UpdateOrderStatus(orderID, userID, status string) error {
 		}).Error
 }
 
-// CancelOrder 取消订单 - 允许待支付和待发货状态的订单取消
+// ConfirmReceipt 确认收货，订单完成与推荐人佣金生成在同一事务中完成
+func (s *OrderService) ConfirmReceipt(orderID, userID string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&internal.Order{}).Where("id = ? AND user_id = ?", orderID, userID).
+			Updates(map[string]interface{}{
+				"status":     internal.OrderStatusFinished,
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("订单不存在")
+		}
+
+		_, err := s.commissionService.WithTx(tx).CreateOrderCommissions(orderID)
+		return err
+	})
+}
+
+// CancelOrder 取消订单 - 允许待支付和待发货状态的订单取消，归还下单时扣减的库存，余额支付部分退回钱包
 func (s *OrderService) CancelOrder(orderID, userID string) error {
 	// 允许取消的状态：待支付、待发货
 	allowedStatuses := []string{internal.OrderStatusToPay, internal.OrderStatusToSend}
 
-	result := s.db.Model(&internal.Order{}).
-		Where("id = ? AND user_id = ? AND status IN ?", orderID, userID, allowedStatuses).
-		Updates(map[string]interface{}{
-			"status":     internal.OrderStatusCanceled,
-			"updated_at": time.Now(),
-		})
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&internal.Order{}).
+			Where("id = ? AND user_id = ? AND status IN ?", orderID, userID, allowedStatuses).
+			Updates(map[string]interface{}{
+				"status":     internal.OrderStatusCanceled,
+				"updated_at": time.Now().Unix(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("订单不存在或无法取消")
+		}
 
-	if result.RowsAffected == 0 {
-		return errors.New("订单不存在或无法取消")
-	}
-	return result.Error
+		if err := s.inventoryService.WithTx(tx).RestoreOrder(orderID, internal.StockReasonCancel, "", "取消订单"); err != nil {
+			return err
+		}
+
+		_, err := s.walletService.WithTx(tx).RefundOrder(orderID)
+		return err
+	})
 }
 
 // GetAdminOrderList 管理员获取订单列表
@@ -184,7 +319,7 @@ func (s *OrderService) GetAdminOrderList(status, orderNo, userID string, page, p
 	}
 
 	offset := (page - 1) * pageSize
-	err = query.Preload("Items.SKU.SPU").Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&orders).Error
+	err = query.Preload("Items.SKU", internal.WithDeleted).Preload("Items.SKU.SPU", internal.WithDeleted).Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&orders).Error
 	if err != nil {
 		return nil, 0, err
 	}
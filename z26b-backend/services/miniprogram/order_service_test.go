@@ -0,0 +1,50 @@
+package miniprogram
+
+import (
+	"testing"
+
+	"z26b-backend/internal"
+	"z26b-backend/services/distribution"
+	"z26b-backend/services/inventory"
+	"z26b-backend/services/wallet"
+)
+
+func TestCreateOrderDeductsStock(t *testing.T) {
+	tests := []struct {
+		name      string
+		quantity  int
+		wantCount int
+		wantErr   bool
+	}{
+		{"库存充足", 3, 7, false},
+		{"库存不足", 11, 10, true},
+		{"数量无效", 0, 10, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t, &internal.User{}, &internal.SPU{}, &internal.SKU{}, &internal.Order{}, &internal.OrderItem{},
+				&internal.CartItem{}, &internal.Address{}, &internal.Warehouse{}, &internal.WarehouseStock{},
+				&internal.StockMovement{}, &internal.StockAlert{})
+			db.Create(&internal.User{ID: "u1", OpenID: "o1"})
+			db.Create(&internal.SPU{ID: "p1"})
+			db.Create(&internal.SKU{ID: "k1", SPUID: "p1", Price: 5, Count: 10})
+			service := NewOrderService(db, wallet.NewWalletService(db), inventory.NewInventoryService(db), distribution.NewCommissionService(db))
+
+			_, err := service.CreateOrder("u1", []internal.OrderItem{{ID: "i1", SKUID: "k1", Quantity: tt.quantity}}, nil, "", 0)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CreateOrder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var sku internal.SKU
+			db.First(&sku, "id = ?", "k1")
+			if sku.Count != tt.wantCount {
+				t.Errorf("sku count = %d, want %d", sku.Count, tt.wantCount)
+			}
+			var orders int64
+			db.Model(&internal.Order{}).Count(&orders)
+			if tt.wantErr && orders != 0 {
+				t.Errorf("orders = %d, want 0 after failed CreateOrder", orders)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package miniprogram
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"z26b-backend/internal"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MaxQAContentLength 提问和回答的最大字数
+const MaxQAContentLength = 200
+
+var (
+	ErrQuestionNotFound = errors.New("question not found")
+	ErrAnswerNotFound   = errors.New("answer not found")
+	ErrNotBuyer         = errors.New("only customers who have purchased this product can answer")
+)
+
+type QuestionService struct {
+	db *gorm.DB
+}
+
+func NewQuestionService(db *gorm.DB) QuestionServiceInterface {
+	return &QuestionService{db: db}
+}
+
+// qaContent 校验并整理提问/回答内容
+func qaContent(content string) (string, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "", errors.New("content is required")
+	}
+	if utf8.RuneCountInString(content) > MaxQAContentLength {
+		return "", errors.New("content is too long")
+	}
+	return content, nil
+}
+
+// approvedAnswers 预加载审核通过的回答：商家回答在前，其余按“有用”数排序
+func approvedAnswers(db *gorm.DB) *gorm.DB {
+	return db.Where("status = ?", internal.QAStatusApproved).
+		Order("is_official DESC, helpful_count DESC, created_at ASC")
+}
+
+// AskQuestion 对商品提问，审核通过后展示
+func (s *QuestionService) AskQuestion(user *internal.User, spuID, content string) (*internal.ProductQuestion, error) {
+	content, err := qaContent(content)
+	if err != nil {
+		return nil, err
+	}
+	var count int64
+	if err := s.db.Model(&internal.SPU{}).Where("id = ?", spuID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, errors.New("product not found")
+	}
+
+	now := time.Now().UnixMilli()
+	question := internal.ProductQuestion{
+		ID:          internal.GenerateUUID(),
+		SPUID:       spuID,
+		UserID:      user.ID,
+		UserName:    user.NickName,
+		UserHeadURL: user.Avatar,
+		Content:     content,
+		Status:      internal.QAStatusPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.db.Create(&question).Error; err != nil {
+		return nil, err
+	}
+	return &question, nil
+}
+
+// AnswerQuestion 回答审核通过的提问，仅已购买该商品的用户可以回答
+func (s *QuestionService) AnswerQuestion(user *internal.User, questionID, content string) (*internal.ProductAnswer, error) {
+	content, err := qaContent(content)
+	if err != nil {
+		return nil, err
+	}
+	var question internal.ProductQuestion
+	if err := s.db.Where("id = ? AND status = ?", questionID, internal.QAStatusApproved).Limit(1).Find(&question).Error; err != nil {
+		return nil, err
+	}
+	if question.ID == "" {
+		return nil, ErrQuestionNotFound
+	}
+
+	purchased, err := internal.HasPurchasedSPU(s.db, user.ID, question.SPUID)
+	if err != nil {
+		return nil, err
+	}
+	if !purchased {
+		return nil, ErrNotBuyer
+	}
+
+	now := time.Now().UnixMilli()
+	answer := internal.ProductAnswer{
+		ID:          internal.GenerateUUID(),
+		QuestionID:  question.ID,
+		SPUID:       question.SPUID,
+		UserID:      user.ID,
+		UserName:    user.NickName,
+		UserHeadURL: user.Avatar,
+		Content:     content,
+		IsBuyer:     true,
+		Status:      internal.QAStatusPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.db.Create(&answer).Error; err != nil {
+		return nil, err
+	}
+	return &answer, nil
+}
+
+// GetQuestions 获取商品审核通过的提问，按回答的“有用”数、回答数排序，附带审核通过的回答
+func (s *QuestionService) GetQuestions(spuID string, page, pageSize int) ([]internal.ProductQuestion, int64, error) {
+	questions := []internal.ProductQuestion{}
+	var total int64
+
+	query := s.db.Model(&internal.ProductQuestion{}).Where("spu_id = ? AND status = ?", spuID, internal.QAStatusApproved)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	err := query.Preload("Answers", approvedAnswers).
+		Order("helpful_count DESC, answer_count DESC, created_at DESC, id ASC").
+		Offset(offset).Limit(pageSize).Find(&questions).Error
+	return questions, total, err
+}
+
+// GetQuestion 获取审核通过的提问及其回答
+func (s *QuestionService) GetQuestion(id string) (*internal.ProductQuestion, error) {
+	var question internal.ProductQuestion
+	err := s.db.Preload("Answers", approvedAnswers).
+		Where("id = ? AND status = ?", id, internal.QAStatusApproved).Limit(1).Find(&question).Error
+	if err != nil {
+		return nil, err
+	}
+	if question.ID == "" {
+		return nil, ErrQuestionNotFound
+	}
+	return &question, nil
+}
+
+// GetMyQuestions 获取用户自己的提问（含审核中和未通过的），附带审核通过的回答
+func (s *QuestionService) GetMyQuestions(userID string, page, pageSize int) ([]internal.ProductQuestion, int64, error) {
+	questions := []internal.ProductQuestion{}
+	var total int64
+
+	query := s.db.Model(&internal.ProductQuestion{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	err := query.Preload("SPU", internal.WithDeleted).Preload("Answers", approvedAnswers).
+		Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&questions).Error
+	return questions, total, err
+}
+
+// MarkHelpful 标记回答“有用”，重复标记不累计，返回回答当前的“有用”数
+func (s *QuestionService) MarkHelpful(userID, answerID string) (int, error) {
+	var answer internal.ProductAnswer
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND status = ?", answerID, internal.QAStatusApproved).Limit(1).Find(&answer).Error; err != nil {
+			return err
+		}
+		if answer.ID == "" {
+			return ErrAnswerNotFound
+		}
+
+		vote := internal.AnswerVote{
+			ID:        internal.GenerateUUID(),
+			AnswerID:  answerID,
+			UserID:    userID,
+			CreatedAt: time.Now().UnixMilli(),
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&vote)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		if err := tx.Model(&internal.ProductAnswer{}).Where("id = ?", answerID).
+			UpdateColumn("helpful_count", gorm.Expr("helpful_count + 1")).Error; err != nil {
+			return err
+		}
+		answer.HelpfulCount++
+		return internal.RefreshQuestionCounts(tx, answer.QuestionID)
+	})
+	return answer.HelpfulCount, err
+}
+
+// CountQuestions 商品审核通过的提问数
+func (s *QuestionService) CountQuestions(spuID string) (int64, error) {
+	var count int64
+	err := s.db.Model(&internal.ProductQuestion{}).
+		Where("spu_id = ? AND status = ?", spuID, internal.QAStatusApproved).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,72 @@
+package miniprogram
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"z26b-backend/internal"
+)
+
+func TestQAContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{"去除首尾空白", "  能机洗吗？ \n", "能机洗吗？", false},
+		{"内容为空", "   ", "", true},
+		{"按字数计算长度", strings.Repeat("好", MaxQAContentLength), strings.Repeat("好", MaxQAContentLength), false},
+		{"超过最大字数", strings.Repeat("好", MaxQAContentLength+1), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := qaContent(tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("qaContent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("qaContent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkHelpful(t *testing.T) {
+	db := newTestDB(t, &internal.ProductQuestion{}, &internal.ProductAnswer{}, &internal.AnswerVote{})
+	db.Create(&internal.ProductQuestion{ID: "q1", Status: internal.QAStatusApproved})
+	db.Create(&internal.ProductAnswer{ID: "a1", QuestionID: "q1", Status: internal.QAStatusApproved, HelpfulCount: 2})
+	db.Create(&internal.ProductAnswer{ID: "a2", QuestionID: "q1", Status: internal.QAStatusPending})
+	service := NewQuestionService(db)
+
+	tests := []struct {
+		name     string
+		userID   string
+		answerID string
+		want     int
+		wantErr  error
+	}{
+		{"首次标记", "u1", "a1", 3, nil},
+		{"重复标记不累计", "u1", "a1", 3, nil},
+		{"其他用户", "u2", "a1", 4, nil},
+		{"未审核的回答", "u1", "a2", 0, ErrAnswerNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := service.MarkHelpful(tt.userID, tt.answerID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MarkHelpful() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("MarkHelpful() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	var question internal.ProductQuestion
+	db.First(&question, "id = ?", "q1")
+	if question.HelpfulCount != 4 {
+		t.Errorf("question helpful_count = %d, want 4", question.HelpfulCount)
+	}
+}
@@ -24,8 +24,9 @@ func (s *UserService) GetOrCreateUser(openID string) (*internal.User, error) {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			// 用户不存在，创建新用户
 			user = internal.User{
-				ID:     internal.GenerateUUID(),
-				OpenID: openID,
+				ID:           internal.GenerateUUID(),
+				OpenID:       openID,
+				ReferralCode: internal.GenerateReferralCode(),
 			}
 			if err := s.db.Create(&user).Error; err != nil {
 				return nil, err
@@ -34,6 +35,14 @@ func (s *UserService) GetOrCreateUser(openID string) (*internal.User, error) {
 			return nil, err
 		}
 	}
+
+	// 老用户补发推荐码
+	if user.ReferralCode == nil {
+		user.ReferralCode = internal.GenerateReferralCode()
+		if err := s.db.Model(&user).Update("referral_code", *user.ReferralCode).Error; err != nil {
+			return nil, err
+		}
+	}
 	return &user, nil
 }
 
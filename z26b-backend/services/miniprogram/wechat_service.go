@@ -10,6 +10,7 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"z26b-backend/internal"
 
@@ -41,8 +42,8 @@ func (s *WechatService) getWechatConfig() WechatConfig {
 	}
 }
 
-// WxLogin 微信登录
-func (s *WechatService) WxLogin(code string) (map[string]interface{}, error) {
+// WxLogin 微信登录，新用户首次登录时通过 referralCode 绑定推荐人
+func (s *WechatService) WxLogin(code, referralCode string) (map[string]interface{}, error) {
 	config := s.getWechatConfig()
 
 	// 调用微信API获取openid
@@ -75,7 +76,7 @@ func (s *WechatService) WxLogin(code string) (map[string]interface{}, error) {
 	}
 
 	// 获取或创建用户
-	user, err := s.dbGetOrCreateUser(openID)
+	user, err := s.dbGetOrCreateUser(openID, referralCode)
 	if err != nil {
 		return nil, err
 	}
@@ -90,16 +91,18 @@ func (s *WechatService) WxLogin(code string) (map[string]interface{}, error) {
 }
 
 // dbGetOrCreateUser 数据库操作：获取或创建用户
-func (s *WechatService) dbGetOrCreateUser(openID string) (*internal.User, error) {
+func (s *WechatService) dbGetOrCreateUser(openID, referralCode string) (*internal.User, error) {
 	var user internal.User
 	err := s.db.Where("open_id = ?", openID).First(&user).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			// 用户不存在，创建新用户
 			user = internal.User{
-				ID:     internal.GenerateUUID(),
-				OpenID: openID,
+				ID:           internal.GenerateUUID(),
+				OpenID:       openID,
+				ReferralCode: internal.GenerateReferralCode(),
 			}
+			s.bindReferrer(&user, referralCode)
 			if err := s.db.Create(&user).Error; err != nil {
 				return nil, err
 			}
@@ -110,6 +113,26 @@ func (s *WechatService) dbGetOrCreateUser(openID string) (*internal.User, error)
 	return &user, nil
 }
 
+// bindReferrer 根据推荐码为新用户绑定推荐人，推荐码无效时忽略
+func (s *WechatService) bindReferrer(user *internal.User, referralCode string) {
+	referralCode = strings.ToUpper(strings.TrimSpace(referralCode))
+	if referralCode == "" {
+		return
+	}
+
+	var referrer internal.User
+	if err := s.db.Where("referral_code = ?", referralCode).First(&referrer).Error; err != nil {
+		return
+	}
+	if referrer.ID == user.ID {
+		return
+	}
+
+	now := time.Now()
+	user.ReferrerID = referrer.ID
+	user.ReferredAt = &now
+}
+
 // GenerateSign 生成签名
 func (s *WechatService) GenerateSign(params map[string]interface{}, key string) string {
 	// 排序参数
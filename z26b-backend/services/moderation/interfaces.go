@@ -0,0 +1,17 @@
+package moderation
+
+import "z26b-backend/internal"
+
+// ModerationServiceInterface 内容审核服务接口
+type ModerationServiceInterface interface {
+	// Check 用敏感词词典检查文本，返回审核状态和处理后的内容
+	Check(text string) (*Result, error)
+	// GetWords 获取敏感词列表
+	GetWords(keyword, level string, page, pageSize int) ([]internal.SensitiveWord, int64, error)
+	// AddWords 批量添加敏感词，已存在的词更新级别，返回处理条数
+	AddWords(words []string, level, adminID string) (int, error)
+	// UpdateWord 修改敏感词级别
+	UpdateWord(id, level string) error
+	// DeleteWords 删除敏感词
+	DeleteWords(ids []string) (int64, error)
+}
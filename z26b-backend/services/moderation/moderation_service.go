@@ -0,0 +1,195 @@
+package moderation
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"z26b-backend/internal"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// cacheTTL 词典缓存时间，多实例部署时其他实例的修改最迟在该时间后生效
+const cacheTTL = time.Minute
+
+var (
+	ErrWordNotFound = errors.New("敏感词不存在")
+	ErrInvalidLevel = errors.New("敏感词级别不正确")
+)
+
+// Result 文本检查结果
+type Result struct {
+	Status  string   // 评论审核状态：APPROVED / PENDING / REJECTED
+	Content string   // MASK 级别的敏感词替换为 * 后的内容
+	Words   []string // 命中的敏感词
+}
+
+// ModerationService 内容审核服务：维护敏感词词典，用多模式匹配检查用户提交的内容
+type ModerationService struct {
+	db *gorm.DB
+
+	mu       sync.RWMutex
+	matcher  *internal.SensitiveMatcher
+	levels   map[string]string // 敏感词 -> 级别
+	loadedAt time.Time
+}
+
+// NewModerationService 创建内容审核服务实例
+func NewModerationService(db *gorm.DB) *ModerationService {
+	return &ModerationService{db: db}
+}
+
+// validLevel 敏感词级别是否合法
+func validLevel(level string) bool {
+	return level == internal.SensitiveLevelMask || level == internal.SensitiveLevelReview || level == internal.SensitiveLevelBlock
+}
+
+// load 返回当前词典的匹配器，缓存过期时从数据库重新加载
+func (s *ModerationService) load() (*internal.SensitiveMatcher, map[string]string, error) {
+	s.mu.RLock()
+	if s.matcher != nil && time.Since(s.loadedAt) < cacheTTL {
+		defer s.mu.RUnlock()
+		return s.matcher, s.levels, nil
+	}
+	s.mu.RUnlock()
+
+	var words []internal.SensitiveWord
+	if err := s.db.Select("word", "level").Find(&words).Error; err != nil {
+		return nil, nil, err
+	}
+	list := make([]string, 0, len(words))
+	levels := make(map[string]string, len(words))
+	for _, w := range words {
+		list = append(list, w.Word)
+		levels[w.Word] = w.Level
+	}
+	matcher := internal.NewSensitiveMatcher(list)
+
+	s.mu.Lock()
+	s.matcher, s.levels, s.loadedAt = matcher, levels, time.Now()
+	s.mu.Unlock()
+	return matcher, levels, nil
+}
+
+// invalidate 词典变更后清空缓存
+func (s *ModerationService) invalidate() {
+	s.mu.Lock()
+	s.matcher = nil
+	s.mu.Unlock()
+}
+
+// Check 检查文本：命中 BLOCK 级别直接驳回，命中 REVIEW 级别转人工审核，MASK 级别替换为 * 后发布
+func (s *ModerationService) Check(text string) (*Result, error) {
+	matcher, levels, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Status: internal.CommentStatusApproved, Content: text}
+	hits := matcher.Match(text)
+	if len(hits) == 0 {
+		return result, nil
+	}
+	result.Words = internal.HitWords(hits)
+
+	var masked []internal.SensitiveHit
+	for _, hit := range hits {
+		switch levels[hit.Word] {
+		case internal.SensitiveLevelBlock:
+			result.Status = internal.CommentStatusRejected
+		case internal.SensitiveLevelReview:
+			if result.Status != internal.CommentStatusRejected {
+				result.Status = internal.CommentStatusPending
+			}
+		default:
+			masked = append(masked, hit)
+		}
+	}
+	result.Content = internal.MaskSensitive(text, masked)
+	return result, nil
+}
+
+// GetWords 获取敏感词列表，按添加时间倒序
+func (s *ModerationService) GetWords(keyword, level string, page, pageSize int) ([]internal.SensitiveWord, int64, error) {
+	words := []internal.SensitiveWord{}
+	var total int64
+
+	query := s.db.Model(&internal.SensitiveWord{})
+	if keyword != "" {
+		query = query.Where("word LIKE ?", "%"+strings.ToLower(keyword)+"%")
+	}
+	if level != "" {
+		query = query.Where("level = ?", level)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("created_at DESC, word ASC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&words).Error
+	return words, total, err
+}
+
+// AddWords 批量添加敏感词，已存在的词更新级别
+func (s *ModerationService) AddWords(words []string, level, adminID string) (int, error) {
+	if !validLevel(level) {
+		return 0, ErrInvalidLevel
+	}
+	now := time.Now().UnixMilli()
+	rows := make([]internal.SensitiveWord, 0, len(words))
+	seen := make(map[string]bool, len(words))
+	for _, word := range words {
+		word = strings.Map(unicode.ToLower, strings.TrimSpace(word))
+		if word == "" || seen[word] {
+			continue
+		}
+		seen[word] = true
+		rows = append(rows, internal.SensitiveWord{
+			ID: internal.GenerateUUID(), Word: word, Level: level, CreatedBy: adminID, CreatedAt: now,
+		})
+	}
+	if len(rows) == 0 {
+		return 0, errors.New("请填写敏感词")
+	}
+
+	err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "word"}},
+		DoUpdates: clause.AssignmentColumns([]string{"level"}),
+	}).CreateInBatches(&rows, 500).Error
+	if err != nil {
+		return 0, err
+	}
+	s.invalidate()
+	return len(rows), nil
+}
+
+// UpdateWord 修改敏感词级别
+func (s *ModerationService) UpdateWord(id, level string) error {
+	if !validLevel(level) {
+		return ErrInvalidLevel
+	}
+	result := s.db.Model(&internal.SensitiveWord{}).Where("id = ?", id).Update("level", level)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrWordNotFound
+	}
+	s.invalidate()
+	return nil
+}
+
+// DeleteWords 删除敏感词
+func (s *ModerationService) DeleteWords(ids []string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := s.db.Where("id IN ?", ids).Delete(&internal.SensitiveWord{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	s.invalidate()
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,293 @@
+package recommend
+
+import (
+	"sort"
+	"time"
+
+	"z26b-backend/internal"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultNeighbors 每个商品保存的相似商品数
+	DefaultNeighbors = 20
+
+	purchaseWeight = 0.7 // 共同购买权重
+	viewWeight     = 0.3 // 共同浏览权重
+
+	viewLookback = 90 * 24 * time.Hour // 参与计算的浏览事件时间范围
+	maxGroupSize = 50                  // 超过该商品数的订单/会话不参与计算，避免两两组合过多
+	maxSeeds     = 30                  // 个性化推荐使用的用户近期行为商品数
+)
+
+// excludedOrderStatuses 不计入共同购买的订单状态
+var excludedOrderStatuses = []string{internal.OrderStatusCanceled, internal.OrderStatusReturnFinish}
+
+// RecommendService 商品推荐服务：离线计算商品相似度，提供相关商品和首页个性化推荐
+type RecommendService struct {
+	db *gorm.DB
+}
+
+// NewRecommendService 创建商品推荐服务实例
+func NewRecommendService(db *gorm.DB) *RecommendService {
+	return &RecommendService{db: db}
+}
+
+// Compute 根据订单共同购买和同一用户当天的共同浏览重新计算商品相似度，整体替换已有结果，返回写入条数
+func (s *RecommendService) Compute(now time.Time) (int, error) {
+	var purchases []struct {
+		OrderID string
+		SPUID   string `gorm:"column:spu_id"`
+	}
+	err := s.db.Table("order_item").
+		Select(`order_item.order_id, sku."SPUID" AS spu_id`).
+		Joins(`JOIN sku ON sku.id = order_item.sku_id`).
+		Joins(`JOIN "order" ON "order".id = order_item.order_id`).
+		Where(`"order".status NOT IN ?`, excludedOrderStatuses).
+		Scan(&purchases).Error
+	if err != nil {
+		return 0, err
+	}
+	baskets := make(map[string][]string)
+	for _, p := range purchases {
+		baskets[p.OrderID] = append(baskets[p.OrderID], p.SPUID)
+	}
+
+	var views []struct {
+		UserID    string
+		SPUID     string `gorm:"column:spu_id"`
+		CreatedAt int64
+	}
+	err = s.db.Model(&internal.CRMEvent{}).
+		Select("user_id, spu_id, created_at").
+		Where("event_type = ? AND user_id <> '' AND spu_id <> '' AND created_at >= ?",
+			internal.CRMEventTypeView, now.Add(-viewLookback).UnixMilli()).
+		Scan(&views).Error
+	if err != nil {
+		return 0, err
+	}
+	sessions := make(map[string][]string)
+	for _, v := range views {
+		key := v.UserID + "|" + time.UnixMilli(v.CreatedAt).Format("2006-01-02")
+		sessions[key] = append(sessions[key], v.SPUID)
+	}
+
+	purchaseSim := internal.ItemSimilarity(groups(baskets))
+	viewSim := internal.ItemSimilarity(groups(sessions))
+
+	computedAt := now.UnixMilli()
+	var rows []internal.ProductSimilarity
+	for spuID, neighbors := range combine(purchaseSim, viewSim) {
+		sort.Slice(neighbors, func(i, j int) bool {
+			if neighbors[i].Score != neighbors[j].Score {
+				return neighbors[i].Score > neighbors[j].Score
+			}
+			return neighbors[i].RelatedSPUID < neighbors[j].RelatedSPUID
+		})
+		if len(neighbors) > DefaultNeighbors {
+			neighbors = neighbors[:DefaultNeighbors]
+		}
+		for _, n := range neighbors {
+			n.ID = internal.GenerateUUID()
+			n.SPUID = spuID
+			n.ComputedAt = computedAt
+			rows = append(rows, n)
+		}
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&internal.ProductSimilarity{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(&rows, 500).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(rows), nil
+}
+
+// groups 将分组结果转为集合列表，跳过商品过多的分组
+func groups(byKey map[string][]string) [][]string {
+	result := make([][]string, 0, len(byKey))
+	for _, items := range byKey {
+		if len(items) > 1 && len(items) <= maxGroupSize {
+			result = append(result, items)
+		}
+	}
+	return result
+}
+
+// combine 按权重合并共同购买和共同浏览相似度
+func combine(purchaseSim, viewSim map[string]map[string]float64) map[string][]internal.ProductSimilarity {
+	merged := make(map[string]map[string]*internal.ProductSimilarity)
+	add := func(sim map[string]map[string]float64, apply func(p *internal.ProductSimilarity, score float64)) {
+		for a, neighbors := range sim {
+			if merged[a] == nil {
+				merged[a] = make(map[string]*internal.ProductSimilarity)
+			}
+			for b, score := range neighbors {
+				p := merged[a][b]
+				if p == nil {
+					p = &internal.ProductSimilarity{RelatedSPUID: b}
+					merged[a][b] = p
+				}
+				apply(p, score)
+			}
+		}
+	}
+	add(purchaseSim, func(p *internal.ProductSimilarity, score float64) { p.PurchaseScore = score })
+	add(viewSim, func(p *internal.ProductSimilarity, score float64) { p.ViewScore = score })
+
+	result := make(map[string][]internal.ProductSimilarity, len(merged))
+	for a, neighbors := range merged {
+		list := make([]internal.ProductSimilarity, 0, len(neighbors))
+		for _, p := range neighbors {
+			p.Score = purchaseWeight*p.PurchaseScore + viewWeight*p.ViewScore
+			list = append(list, *p)
+		}
+		result[a] = list
+	}
+	return result
+}
+
+// GetRelated 获取与商品相似的在售商品，不足 limit 个时用人工推荐商品补足
+func (s *RecommendService) GetRelated(spuID string, limit int) ([]internal.SPU, error) {
+	var related []string
+	err := s.db.Model(&internal.ProductSimilarity{}).Where("spu_id = ?", spuID).
+		Order("score DESC").Limit(DefaultNeighbors).Pluck("related_spu_id", &related).Error
+	if err != nil {
+		return nil, err
+	}
+	return s.fill(related, map[string]bool{spuID: true}, limit)
+}
+
+// GetHomeFeed 首页个性化推荐：按用户近期浏览、收藏和购买的商品汇总相似商品得分，
+// 新用户或没有相似数据时使用人工推荐商品
+func (s *RecommendService) GetHomeFeed(userID string, limit int) ([]internal.SPU, error) {
+	seeds, err := s.userSeeds(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	exclude := make(map[string]bool, len(seeds))
+	seedIDs := make([]string, 0, len(seeds))
+	for id := range seeds {
+		exclude[id] = true
+		seedIDs = append(seedIDs, id)
+	}
+
+	var candidates []string
+	if len(seedIDs) > 0 {
+		var sims []internal.ProductSimilarity
+		if err := s.db.Where("spu_id IN ?", seedIDs).Find(&sims).Error; err != nil {
+			return nil, err
+		}
+		scores := make(map[string]float64)
+		for _, sim := range sims {
+			if !exclude[sim.RelatedSPUID] {
+				scores[sim.RelatedSPUID] += sim.Score * seeds[sim.SPUID]
+			}
+		}
+		for id := range scores {
+			candidates = append(candidates, id)
+		}
+		sort.Slice(candidates, func(i, j int) bool {
+			if scores[candidates[i]] != scores[candidates[j]] {
+				return scores[candidates[i]] > scores[candidates[j]]
+			}
+			return candidates[i] < candidates[j]
+		})
+	}
+	return s.fill(candidates, exclude, limit)
+}
+
+// userSeeds 用户近期行为商品及权重：购买 > 收藏 > 浏览
+func (s *RecommendService) userSeeds(userID string) (map[string]float64, error) {
+	seeds := make(map[string]float64)
+	if userID == "" {
+		return seeds, nil
+	}
+	add := func(ids []string, weight float64) {
+		for _, id := range ids {
+			if weight > seeds[id] {
+				seeds[id] = weight
+			}
+		}
+	}
+
+	var viewed []string
+	if err := s.db.Model(&internal.BrowseHistory{}).Where("user_id = ?", userID).
+		Order("viewed_at DESC").Limit(maxSeeds).Pluck("spu_id", &viewed).Error; err != nil {
+		return nil, err
+	}
+	add(viewed, 0.5)
+
+	var favorited []string
+	if err := s.db.Model(&internal.Favorite{}).Where("user_id = ?", userID).
+		Order("created_at DESC").Limit(maxSeeds).Pluck("spu_id", &favorited).Error; err != nil {
+		return nil, err
+	}
+	add(favorited, 0.8)
+
+	var purchased []string
+	if err := s.db.Table("order_item").
+		Joins(`JOIN sku ON sku.id = order_item.sku_id`).
+		Joins(`JOIN "order" ON "order".id = order_item.order_id`).
+		Where(`"order".user_id = ? AND "order".status NOT IN ?`, userID, excludedOrderStatuses).
+		Order(`"order".created_at DESC`).Limit(maxSeeds).
+		Pluck(`sku."SPUID"`, &purchased).Error; err != nil {
+		return nil, err
+	}
+	add(purchased, 1)
+	return seeds, nil
+}
+
+// fill 按 ids 顺序返回在售商品，不足 limit 个时用人工推荐商品补足，exclude 中的商品不返回
+func (s *RecommendService) fill(ids []string, exclude map[string]bool, limit int) ([]internal.SPU, error) {
+	var curated []string
+	err := s.db.Model(&internal.RecommendedProduct{}).Order("priority DESC, created_at DESC").Pluck("spu_id", &curated).Error
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(exclude))
+	for id := range exclude {
+		seen[id] = true
+	}
+	ordered := make([]string, 0, len(ids)+len(curated))
+	for _, id := range append(ids, curated...) {
+		if !seen[id] {
+			seen[id] = true
+			ordered = append(ordered, id)
+		}
+	}
+	if len(ordered) == 0 {
+		return []internal.SPU{}, nil
+	}
+
+	var spus []internal.SPU
+	err = s.db.Where("id IN ?", ordered).Scopes(internal.SPUVisibleAt(time.Now().UnixMilli())).Find(&spus).Error
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]internal.SPU, len(spus))
+	for _, spu := range spus {
+		byID[spu.ID] = spu
+	}
+
+	result := make([]internal.SPU, 0, limit)
+	for _, id := range ordered {
+		if spu, ok := byID[id]; ok {
+			result = append(result, spu)
+			if len(result) == limit {
+				break
+			}
+		}
+	}
+	return result, nil
+}
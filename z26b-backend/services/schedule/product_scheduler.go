@@ -0,0 +1,85 @@
+package schedule
+
+import (
+	"time"
+
+	"z26b-backend/internal"
+	"z26b-backend/services/search"
+
+	"gorm.io/gorm"
+)
+
+// ProductScheduler 商品定时上下架任务：将到期的 PublishAt/UnpublishAt 写入 SPU.Status 并清空
+// 小程序端查询时已按时间计算实际状态，任务延迟执行不影响展示
+type ProductScheduler struct {
+	db            *gorm.DB
+	searchService *search.SearchService
+}
+
+// NewProductScheduler 创建商品定时上下架任务
+func NewProductScheduler(db *gorm.DB, searchService *search.SearchService) *ProductScheduler {
+	return &ProductScheduler{db: db, searchService: searchService}
+}
+
+// Start 按固定间隔执行，阻塞运行，需在 goroutine 中调用
+func (s *ProductScheduler) Start(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.ApplyDue(time.Now().UnixMilli()); err != nil {
+			internal.GlobalLogger.Error("Failed to apply product schedules", err)
+		}
+		<-ticker.C
+	}
+}
+
+// ApplyDue 应用 now 之前到期的定时上下架，返回状态被处理的商品ID
+func (s *ProductScheduler) ApplyDue(now int64) ([]string, error) {
+	var spus []internal.SPU
+	err := s.db.Select("id", "status", "publish_at", "unpublish_at").
+		Where("publish_at <= ? OR unpublish_at <= ?", now, now).
+		Find(&spus).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []string
+	for _, spu := range spus {
+		updates := map[string]interface{}{
+			"status":     internal.SPUEffectiveStatus(spu.Status, spu.PublishAt, spu.UnpublishAt, now),
+			"updated_at": now,
+		}
+		if spu.PublishAt != nil && *spu.PublishAt <= now {
+			updates["publish_at"] = nil
+		}
+		if spu.UnpublishAt != nil && *spu.UnpublishAt <= now {
+			updates["unpublish_at"] = nil
+		}
+
+		// 仅在定时设置未被修改时更新，避免覆盖管理员刚保存的新计划
+		query := s.db.Model(&internal.SPU{}).Where("id = ?", spu.ID)
+		query = whereTime(query, "publish_at", spu.PublishAt)
+		query = whereTime(query, "unpublish_at", spu.UnpublishAt)
+		result := query.Updates(updates)
+		if result.Error != nil {
+			return applied, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		applied = append(applied, spu.ID)
+		internal.GlobalLogger.Info("Applied product schedule", map[string]interface{}{"spuId": spu.ID, "status": updates["status"]})
+		if err := s.searchService.IndexProduct(spu.ID); err != nil {
+			internal.GlobalLogger.Warn("Failed to sync search index", map[string]interface{}{"spuId": spu.ID, "error": err.Error()})
+		}
+	}
+	return applied, nil
+}
+
+func whereTime(query *gorm.DB, column string, value *int64) *gorm.DB {
+	if value == nil {
+		return query.Where(column + " IS NULL")
+	}
+	return query.Where(column+" = ?", *value)
+}
@@ -0,0 +1,33 @@
+package schedule
+
+import (
+	"time"
+
+	"z26b-backend/internal"
+	admin_services "z26b-backend/services/admin_services"
+)
+
+// RecycleBinPurger 回收站清理任务：彻底删除超过保留时长的商品、SKU、分类和标签
+type RecycleBinPurger struct {
+	recycleBinService admin_services.AdminRecycleBinServiceInterface
+}
+
+// NewRecycleBinPurger 创建回收站清理任务
+func NewRecycleBinPurger(recycleBinService admin_services.AdminRecycleBinServiceInterface) *RecycleBinPurger {
+	return &RecycleBinPurger{recycleBinService: recycleBinService}
+}
+
+// Start 按固定间隔执行，阻塞运行，需在 goroutine 中调用
+func (p *RecycleBinPurger) Start(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		purged, err := p.recycleBinService.PurgeExpired(time.Now())
+		if err != nil {
+			internal.GlobalLogger.Error("Failed to purge recycle bin", err)
+		} else if purged > 0 {
+			internal.GlobalLogger.Info("Purged expired recycle bin items", map[string]interface{}{"count": purged})
+		}
+		<-ticker.C
+	}
+}
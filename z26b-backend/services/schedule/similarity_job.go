@@ -0,0 +1,33 @@
+package schedule
+
+import (
+	"time"
+
+	"z26b-backend/internal"
+	"z26b-backend/services/recommend"
+)
+
+// SimilarityJob 商品相似度计算任务：定期根据订单和浏览数据重新计算“猜你喜欢”
+type SimilarityJob struct {
+	recommendService *recommend.RecommendService
+}
+
+// NewSimilarityJob 创建商品相似度计算任务
+func NewSimilarityJob(recommendService *recommend.RecommendService) *SimilarityJob {
+	return &SimilarityJob{recommendService: recommendService}
+}
+
+// Start 按固定间隔执行，阻塞运行，需在 goroutine 中调用
+func (j *SimilarityJob) Start(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		count, err := j.recommendService.Compute(time.Now())
+		if err != nil {
+			internal.GlobalLogger.Error("Failed to compute product similarity", err)
+		} else {
+			internal.GlobalLogger.Info("Computed product similarity", map[string]interface{}{"count": count})
+		}
+		<-ticker.C
+	}
+}
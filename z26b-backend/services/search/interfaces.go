@@ -0,0 +1,52 @@
+package search
+
+import "z26b-backend/internal"
+
+// Document 商品搜索文档
+type Document struct {
+	SPUID        string
+	Name         string
+	Keywords     string // 分类名、标签名
+	Content      string // 详情纯文本
+	CategoryID   string
+	CategoryName string
+	TagIDs       []string
+	TagNames     []string // 与 TagIDs 一一对应
+	MinPrice     float64
+	MaxPrice     float64
+	Priority     int
+	Status       string
+	PublishAt    *int64 // 定时上架时间
+	UnpublishAt  *int64 // 定时下架时间
+}
+
+// visibleAt 文档在 now 时刻是否上架（考虑定时上下架）
+func (d Document) visibleAt(now int64) bool {
+	return internal.SPUEffectiveStatus(d.Status, d.PublishAt, d.UnpublishAt, now) == "ENABLED"
+}
+
+// Hit 检索命中结果
+type Hit struct {
+	Document
+	Score float64
+}
+
+// Index 可替换的商品搜索索引
+type Index interface {
+	// Upsert 写入或更新商品文档
+	Upsert(doc Document) error
+
+	// Delete 从索引中删除商品
+	Delete(spuID string) error
+
+	// Rebuild 清空并用给定文档重建索引
+	Rebuild(docs []Document) error
+
+	// Search 检索关键词，按相关度降序返回命中文档；关键词为空时返回全部文档。
+	// 实现可以预先排除未上架的文档，或只返回前 maxSearchHits+1 个
+	Search(keyword string) ([]Hit, error)
+
+	// Suggest 搜索联想：按商品名分词或拼音前缀查找当前上架的商品，
+	// 商品名以关键词开头的排在前面，最多返回 limit 个
+	Suggest(keyword string, limit int) ([]Hit, error)
+}
@@ -0,0 +1,275 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 字段权重：商品名 > 分类/标签 > 详情
+const (
+	weightName     = 3.0
+	weightKeywords = 2.0
+	weightContent  = 1.0
+)
+
+// memoryDoc 内存索引中的文档
+type memoryDoc struct {
+	doc      Document
+	terms    map[string]float64 // 词 → 加权词频
+	names    map[string]bool    // 商品名分词，用于联想
+	pinyin   string
+	initials string
+}
+
+// MemoryIndex 进程内倒排索引，用于 SQLite 等不支持全文检索的数据库
+// 索引只保存在当前进程中，启动时需要调用 Rebuild 全量构建
+type MemoryIndex struct {
+	mu       sync.RWMutex
+	docs     map[string]*memoryDoc
+	postings map[string]map[string]float64 // 词 → 商品ID → 加权词频
+}
+
+// NewMemoryIndex 创建内存索引
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{
+		docs:     make(map[string]*memoryDoc),
+		postings: make(map[string]map[string]float64),
+	}
+}
+
+// Upsert 写入或更新文档
+func (idx *MemoryIndex) Upsert(doc Document) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(doc.SPUID)
+	idx.add(doc)
+	return nil
+}
+
+// Delete 删除文档
+func (idx *MemoryIndex) Delete(spuID string) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(spuID)
+	return nil
+}
+
+// Rebuild 清空并重建索引
+func (idx *MemoryIndex) Rebuild(docs []Document) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.docs = make(map[string]*memoryDoc, len(docs))
+	idx.postings = make(map[string]map[string]float64)
+	for _, doc := range docs {
+		idx.add(doc)
+	}
+	return nil
+}
+
+func (idx *MemoryIndex) add(doc Document) {
+	md := &memoryDoc{doc: doc, terms: make(map[string]float64), names: make(map[string]bool)}
+	for _, term := range Tokenize(doc.Name) {
+		md.names[term] = true
+	}
+	for _, field := range []struct {
+		text   string
+		weight float64
+	}{
+		{doc.Name, weightName},
+		{doc.Keywords, weightKeywords},
+		{doc.Content, weightContent},
+	} {
+		for _, term := range Tokenize(field.text) {
+			md.terms[term] += field.weight
+		}
+	}
+	md.pinyin, md.initials = Pinyin(doc.Name)
+
+	idx.docs[doc.SPUID] = md
+	for term, tf := range md.terms {
+		if idx.postings[term] == nil {
+			idx.postings[term] = make(map[string]float64)
+		}
+		idx.postings[term][doc.SPUID] = tf
+	}
+}
+
+func (idx *MemoryIndex) remove(spuID string) {
+	md, ok := idx.docs[spuID]
+	if !ok {
+		return
+	}
+	for term := range md.terms {
+		delete(idx.postings[term], spuID)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	delete(idx.docs, spuID)
+}
+
+// match 返回命中词的文档及加权词频，前缀匹配时合并所有以该词开头的词
+func (idx *MemoryIndex) match(term string, prefix bool) map[string]float64 {
+	if !prefix {
+		return idx.postings[term]
+	}
+	matched := make(map[string]float64)
+	for t, docs := range idx.postings {
+		if !strings.HasPrefix(t, term) {
+			continue
+		}
+		for id, tf := range docs {
+			matched[id] += tf
+		}
+	}
+	return matched
+}
+
+// Search 检索关键词，按相关度降序返回全部命中文档；关键词为空时返回全部文档
+func (idx *MemoryIndex) Search(keyword string) ([]Hit, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	scores := make(map[string]float64)
+	if strings.TrimSpace(keyword) == "" {
+		for id := range idx.docs {
+			scores[id] = 0
+		}
+		return idx.hits(scores), nil
+	}
+
+	n := float64(len(idx.docs))
+	bm := func(tf, df float64) float64 {
+		return math.Log(1+n/df) * tf / (tf + 1.2)
+	}
+
+	q := analyzeQuery(keyword)
+	for i, term := range q.Required {
+		docs := idx.match(term, q.Prefix[term])
+		next := make(map[string]float64)
+		for id, tf := range docs {
+			if i > 0 {
+				if _, ok := scores[id]; !ok {
+					continue
+				}
+			}
+			next[id] = scores[id] + bm(tf, float64(len(docs)))
+		}
+		scores = next
+		if len(scores) == 0 {
+			break
+		}
+	}
+	for _, term := range q.Boost {
+		docs := idx.postings[term]
+		for id := range scores {
+			if tf, ok := docs[id]; ok {
+				scores[id] += 1.5 * bm(tf, float64(len(docs)))
+			}
+		}
+	}
+
+	// 拼音全拼/首字母匹配商品名
+	if isPinyinQuery(keyword) {
+		kw := strings.ToLower(strings.ReplaceAll(keyword, " ", ""))
+		for id, md := range idx.docs {
+			bonus := 0.0
+			switch {
+			case strings.HasPrefix(md.pinyin, kw) || strings.HasPrefix(md.initials, kw):
+				bonus = 2
+			case strings.Contains(md.pinyin, kw) || (len(kw) > 1 && strings.Contains(md.initials, kw)):
+				bonus = 1
+			}
+			if bonus > 0 {
+				scores[id] += bonus
+			}
+		}
+	}
+
+	return idx.hits(scores), nil
+}
+
+// Suggest 搜索联想：商品名分词命中全部联想词或拼音以关键词开头的上架商品，
+// 商品名以关键词开头的得分更高
+func (idx *MemoryIndex) Suggest(keyword string, limit int) ([]Hit, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	kw := strings.ToLower(strings.TrimSpace(keyword))
+	q := suggestTerms(keyword)
+	pinyin := isPinyinQuery(keyword)
+	compact := strings.ReplaceAll(kw, " ", "")
+	now := time.Now().UnixMilli()
+
+	hits := []Hit{}
+	for _, md := range idx.docs {
+		if !md.doc.visibleAt(now) {
+			continue
+		}
+		matched := len(q.Required) > 0 && md.hasNameTerms(q)
+		if !matched && pinyin {
+			matched = strings.HasPrefix(md.pinyin, compact) || strings.HasPrefix(md.initials, compact)
+		}
+		if !matched {
+			continue
+		}
+		score := 0.0
+		if strings.HasPrefix(strings.ToLower(md.doc.Name), kw) {
+			score = 1
+		}
+		hits = append(hits, Hit{Document: md.doc, Score: score})
+	}
+	sortHits(hits)
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+// hasNameTerms 商品名分词是否包含全部联想词，前缀词匹配以其开头的分词
+func (md *memoryDoc) hasNameTerms(q queryTerms) bool {
+	for _, term := range q.Required {
+		if md.names[term] {
+			continue
+		}
+		found := false
+		if q.Prefix[term] {
+			for name := range md.names {
+				if strings.HasPrefix(name, term) {
+					found = true
+					break
+				}
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// hits 按得分、优先级排序
+func (idx *MemoryIndex) hits(scores map[string]float64) []Hit {
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, Hit{Document: idx.docs[id].doc, Score: score})
+	}
+	sortHits(hits)
+	return hits
+}
+
+// sortHits 得分降序，得分相同时按优先级降序、商品ID升序，保证分页稳定
+func sortHits(hits []Hit) {
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if hits[i].Priority != hits[j].Priority {
+			return hits[i].Priority > hits[j].Priority
+		}
+		return hits[i].SPUID < hits[j].SPUID
+	})
+}
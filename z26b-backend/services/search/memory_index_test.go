@@ -0,0 +1,112 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testMemoryIndex(t *testing.T) *MemoryIndex {
+	t.Helper()
+	idx := NewMemoryIndex()
+	err := idx.Rebuild([]Document{
+		{SPUID: "name", Name: "夏季连衣裙", Priority: 1},
+		{SPUID: "keyword", Name: "长裙", Keywords: "连衣裙 新品"},
+		{SPUID: "content", Name: "半身裙", Content: "可搭配连衣裙外套"},
+		{SPUID: "shoe", Name: "Nike Air 跑鞋", Keywords: "运动"},
+		{SPUID: "shirt", Name: "纯棉T恤", Priority: 5},
+	})
+	if err != nil {
+		t.Fatalf("Rebuild() error = %v", err)
+	}
+	return idx
+}
+
+func hitIDs(hits []Hit) []string {
+	ids := make([]string, 0, len(hits))
+	for _, h := range hits {
+		ids = append(ids, h.SPUID)
+	}
+	return ids
+}
+
+func TestMemoryIndexSearch(t *testing.T) {
+	idx := testMemoryIndex(t)
+	tests := []struct {
+		name    string
+		keyword string
+		want    []string
+	}{
+		{"name outranks keywords outranks content", "连衣裙", []string{"name", "keyword", "content"}},
+		{"all characters required", "连衣裤", []string{}},
+		{"latin prefix", "ni", []string{"shoe"}},
+		{"latin words all required", "nike run", []string{}},
+		{"pinyin initials match name only", "lyq", []string{"name"}},
+		{"pinyin prefix", "chunmian", []string{"shirt"}},
+		{"empty keyword returns all by priority", "", []string{"shirt", "name", "content", "keyword", "shoe"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits, err := idx.Search(tt.keyword)
+			if err != nil {
+				t.Fatalf("Search(%q) error = %v", tt.keyword, err)
+			}
+			if got := hitIDs(hits); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %q, want %q", tt.keyword, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryIndexUpsertDelete(t *testing.T) {
+	idx := testMemoryIndex(t)
+
+	idx.Upsert(Document{SPUID: "shirt", Name: "亚麻衬衫"})
+	if hits, _ := idx.Search("T恤"); len(hits) != 0 {
+		t.Errorf("Search() after Upsert = %q, want old terms removed", hitIDs(hits))
+	}
+	if hits, _ := idx.Search("衬衫"); !reflect.DeepEqual(hitIDs(hits), []string{"shirt"}) {
+		t.Errorf("Search() after Upsert = %q, want [shirt]", hitIDs(hits))
+	}
+
+	idx.Delete("shoe")
+	if hits, _ := idx.Search("nike"); len(hits) != 0 {
+		t.Errorf("Search() after Delete = %q, want none", hitIDs(hits))
+	}
+}
+
+func TestMemoryIndexSuggest(t *testing.T) {
+	idx := NewMemoryIndex()
+	idx.Rebuild([]Document{
+		{SPUID: "summer", Name: "夏季连衣裙", Priority: 1, Status: "ENABLED"},
+		{SPUID: "long", Name: "连衣裙长款", Status: "ENABLED"},
+		{SPUID: "shoe", Name: "Nike Air 跑鞋", Status: "ENABLED"},
+		{SPUID: "shirt", Name: "纯棉T恤", Priority: 5, Status: "ENABLED"},
+		{SPUID: "offline", Name: "连衣裙下架", Priority: 9, Status: "DISABLED"},
+	})
+	tests := []struct {
+		name    string
+		keyword string
+		limit   int
+		want    []string
+	}{
+		{"name prefix ranks first", "连衣", 10, []string{"long", "summer"}},
+		{"limit", "连衣", 1, []string{"long"}},
+		{"chinese must be contiguous", "连裙", 10, []string{}},
+		{"single character", "恤", 10, []string{"shirt"}},
+		{"latin prefix", "ni", 10, []string{"shoe"}},
+		{"latin words all required", "nike ru", 10, []string{}},
+		{"pinyin initials prefix", "lyq", 10, []string{"long"}},
+		{"pinyin full prefix", "xiaji", 10, []string{"summer"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits, err := idx.Suggest(tt.keyword, tt.limit)
+			if err != nil {
+				t.Fatalf("Suggest(%q) error = %v", tt.keyword, err)
+			}
+			if got := hitIDs(hits); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Suggest(%q) = %q, want %q", tt.keyword, got, tt.want)
+			}
+		})
+	}
+}
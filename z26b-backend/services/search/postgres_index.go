@@ -0,0 +1,235 @@
+package search
+
+import (
+	"strings"
+	"time"
+
+	"z26b-backend/internal"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// tsvectorSQL 由分词结果生成 tsvector：中文分词由应用完成，词之间以空格分隔，
+// simple 配置只按空格切分并保留词位置，setweight 才能为商品名/分类标签/详情加权
+const tsvectorSQL = `UPDATE search_document SET tsv =
+	setweight(to_tsvector('simple', COALESCE(name_terms, '')), 'A') ||
+	setweight(to_tsvector('simple', COALESCE(key_terms, '')), 'B') ||
+	setweight(to_tsvector('simple', COALESCE(body_terms, '')), 'C')
+	WHERE spu_id IN ?`
+
+// PostgresIndex 基于 search_document 表和 tsvector/GIN 索引的全文检索
+type PostgresIndex struct {
+	db *gorm.DB
+}
+
+// NewPostgresIndex 创建 PostgreSQL 全文检索索引
+func NewPostgresIndex(db *gorm.DB) *PostgresIndex {
+	return &PostgresIndex{db: db}
+}
+
+// toRow 文档转换为索引表记录
+func toRow(doc Document) internal.SearchDocument {
+	pinyin, initials := Pinyin(doc.Name)
+	return internal.SearchDocument{
+		SPUID:       doc.SPUID,
+		Name:        doc.Name,
+		Keywords:    doc.Keywords,
+		Content:     doc.Content,
+		NameTerms:   strings.Join(Tokenize(doc.Name), " "),
+		KeyTerms:    strings.Join(Tokenize(doc.Keywords), " "),
+		BodyTerms:   strings.Join(Tokenize(doc.Content), " "),
+		Pinyin:      pinyin,
+		Initials:    initials,
+		CategoryID:  doc.CategoryID,
+		Category:    doc.CategoryName,
+		TagIDs:      strings.Join(doc.TagIDs, ","),
+		TagNames:    strings.Join(doc.TagNames, ","),
+		MinPrice:    doc.MinPrice,
+		MaxPrice:    doc.MaxPrice,
+		Priority:    doc.Priority,
+		Status:      doc.Status,
+		PublishAt:   doc.PublishAt,
+		UnpublishAt: doc.UnpublishAt,
+		UpdatedAt:   time.Now().UnixMilli(),
+	}
+}
+
+// toDocument 索引表记录转换为文档
+func toDocument(row internal.SearchDocument) Document {
+	doc := Document{
+		SPUID:        row.SPUID,
+		Name:         row.Name,
+		Keywords:     row.Keywords,
+		CategoryID:   row.CategoryID,
+		CategoryName: row.Category,
+		MinPrice:     row.MinPrice,
+		MaxPrice:     row.MaxPrice,
+		Priority:     row.Priority,
+		Status:       row.Status,
+		PublishAt:    row.PublishAt,
+		UnpublishAt:  row.UnpublishAt,
+	}
+	if row.TagIDs != "" {
+		doc.TagIDs = strings.Split(row.TagIDs, ",")
+		doc.TagNames = strings.Split(row.TagNames, ",")
+	}
+	return doc
+}
+
+// Upsert 写入或更新文档
+func (idx *PostgresIndex) Upsert(doc Document) error {
+	return idx.upsert(idx.db, []Document{doc})
+}
+
+func (idx *PostgresIndex) upsert(db *gorm.DB, docs []Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	rows := make([]internal.SearchDocument, 0, len(docs))
+	ids := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		rows = append(rows, toRow(doc))
+		ids = append(ids, doc.SPUID)
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).CreateInBatches(&rows, 200).Error; err != nil {
+			return err
+		}
+		return tx.Exec(tsvectorSQL, ids).Error
+	})
+}
+
+// hasUnweighted 是否存在没有词位置的 tsvector（旧版本生成，权重不生效），需要重建索引
+func (idx *PostgresIndex) hasUnweighted() (bool, error) {
+	var count int64
+	err := idx.db.Model(&internal.SearchDocument{}).Where("length(tsv) > 0 AND tsv = strip(tsv)").Count(&count).Error
+	return count > 0, err
+}
+
+// Delete 删除文档
+func (idx *PostgresIndex) Delete(spuID string) error {
+	return idx.db.Delete(&internal.SearchDocument{}, "spu_id = ?", spuID).Error
+}
+
+// Rebuild 清空并重建索引
+func (idx *PostgresIndex) Rebuild(docs []Document) error {
+	return idx.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM search_document").Error; err != nil {
+			return err
+		}
+		return idx.upsert(tx, docs)
+	})
+}
+
+// tsquery 拼接 tsquery 文本，分词结果只包含字母数字和汉字，无需转义；weight 非空时只匹配该权重的词
+func tsquery(terms []string, prefix map[string]bool, op, weight string) string {
+	parts := make([]string, 0, len(terms))
+	for _, t := range terms {
+		p := "'" + t + "'"
+		if prefix[t] || weight != "" {
+			p += ":"
+		}
+		if prefix[t] {
+			p += "*"
+		}
+		parts = append(parts, p+weight)
+	}
+	return strings.Join(parts, op)
+}
+
+// Search 检索关键词，按相关度降序返回当前上架的命中文档；最多返回 maxSearchHits+1 条，
+// 调用方据此判断命中数是否超过上限
+func (idx *PostgresIndex) Search(keyword string) ([]Hit, error) {
+	type row struct {
+		internal.SearchDocument
+		Score float64
+	}
+	var rows []row
+
+	query := idx.db.Model(&internal.SearchDocument{}).Scopes(internal.SPUVisibleAt(time.Now().UnixMilli()))
+	if strings.TrimSpace(keyword) == "" {
+		query = query.Select("*, 0 AS score")
+	} else {
+		q := analyzeQuery(keyword)
+		var conds []string
+		var args []interface{}
+		score := "0"
+		var scoreArgs []interface{}
+
+		if len(q.Required) > 0 {
+			conds = append(conds, "tsv @@ ?::tsquery")
+			args = append(args, tsquery(q.Required, q.Prefix, " & ", ""))
+			score = "ts_rank(tsv, ?::tsquery)"
+			scoreArgs = append(scoreArgs, tsquery(q.Required, q.Prefix, " & ", ""))
+			if len(q.Boost) > 0 {
+				score += " + 1.5 * ts_rank(tsv, ?::tsquery)"
+				scoreArgs = append(scoreArgs, tsquery(q.Boost, nil, " | ", ""))
+			}
+		}
+		if isPinyinQuery(keyword) {
+			kw := strings.ToLower(strings.ReplaceAll(keyword, " ", ""))
+			conds = append(conds, "pinyin LIKE ? OR initials LIKE ?")
+			args = append(args, "%"+kw+"%", "%"+kw+"%")
+			score += " + CASE WHEN pinyin LIKE ? OR initials LIKE ? THEN 0.2 ELSE 0 END"
+			scoreArgs = append(scoreArgs, kw+"%", kw+"%")
+		}
+		if len(conds) == 0 {
+			return []Hit{}, nil
+		}
+
+		query = query.Select("*, ("+score+") AS score", scoreArgs...).
+			Where("("+strings.Join(conds, ") OR (")+")", args...)
+	}
+
+	err := query.Order("score DESC, priority DESC, spu_id ASC").Limit(maxSearchHits + 1).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]Hit, 0, len(rows))
+	for _, r := range rows {
+		hits = append(hits, Hit{Document: toDocument(r.SearchDocument), Score: r.Score})
+	}
+	return hits, nil
+}
+
+// Suggest 搜索联想：只匹配商品名（权重 A）的分词，走 GIN 索引；拼音按前缀匹配
+func (idx *PostgresIndex) Suggest(keyword string, limit int) ([]Hit, error) {
+	kw := strings.ToLower(strings.TrimSpace(keyword))
+	q := suggestTerms(keyword)
+	var conds []string
+	var args []interface{}
+	if len(q.Required) > 0 {
+		conds = append(conds, "tsv @@ ?::tsquery")
+		args = append(args, tsquery(q.Required, q.Prefix, " & ", "A"))
+	}
+	if isPinyinQuery(keyword) {
+		compact := strings.ReplaceAll(kw, " ", "") + "%"
+		conds = append(conds, "pinyin LIKE ? OR initials LIKE ?")
+		args = append(args, compact, compact)
+	}
+	if len(conds) == 0 {
+		return []Hit{}, nil
+	}
+
+	type row struct {
+		internal.SearchDocument
+		Score float64
+	}
+	var rows []row
+	err := idx.db.Model(&internal.SearchDocument{}).Scopes(internal.SPUVisibleAt(time.Now().UnixMilli())).
+		Select(`*, CASE WHEN lower(name) LIKE ? ESCAPE '\' THEN 1 ELSE 0 END AS score`, escapeLike(kw)+"%").
+		Where("("+strings.Join(conds, ") OR (")+")", args...).
+		Order("score DESC, priority DESC, spu_id ASC").Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]Hit, 0, len(rows))
+	for _, r := range rows {
+		hits = append(hits, Hit{Document: toDocument(r.SearchDocument), Score: r.Score})
+	}
+	return hits, nil
+}
@@ -0,0 +1,198 @@
+package search
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"z26b-backend/internal"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxKeywordLength 记录日志时关键词最大长度（字符数）
+const maxKeywordLength = 64
+
+// hotKeywordDays 热门搜索统计的天数
+const hotKeywordDays = 7
+
+// Suggestion 搜索联想项
+type Suggestion struct {
+	Text  string `json:"text"`
+	Type  string `json:"type"`            // query: 热门搜索词, product: 商品名
+	SPUID string `json:"spuId,omitempty"` // Type 为 product 时的商品ID
+}
+
+// 联想项类型
+const (
+	SuggestionTypeQuery   = "query"
+	SuggestionTypeProduct = "product"
+)
+
+// HotKeyword 热门搜索词
+type HotKeyword struct {
+	Keyword string `json:"keyword"`
+	Count   int64  `json:"count"` // 搜索人数
+}
+
+// distinctUsers 搜索人数，未识别用户的搜索不计入
+const distinctUsers = "COUNT(DISTINCT NULLIF(user_id, ''))"
+
+// QueryStat 搜索词统计
+type QueryStat struct {
+	Keyword        string  `json:"keyword"`
+	Searches       int64   `json:"searches"`       // 搜索次数
+	Users          int64   `json:"users"`          // 搜索人数
+	AvgResults     float64 `json:"avgResults"`     // 平均结果数
+	LastSearchedAt int64   `json:"lastSearchedAt"` // 最近搜索时间
+}
+
+// QueryReport 搜索词报表
+type QueryReport struct {
+	TotalSearches      int64       `json:"totalSearches"`
+	ZeroResultSearches int64       `json:"zeroResultSearches"`
+	ZeroResultRate     float64     `json:"zeroResultRate"` // 无结果搜索占比（百分比）
+	TopQueries         []QueryStat `json:"topQueries"`
+	ZeroResultQueries  []QueryStat `json:"zeroResultQueries"`
+}
+
+// NormalizeKeyword 规范化搜索关键词：去除首尾空白、合并连续空白、字母转小写、截断过长内容
+func NormalizeKeyword(keyword string) string {
+	keyword = strings.ToLower(strings.Join(strings.Fields(keyword), " "))
+	if utf8.RuneCountInString(keyword) > maxKeywordLength {
+		keyword = strings.TrimSpace(string([]rune(keyword)[:maxKeywordLength]))
+	}
+	return keyword
+}
+
+// LogQuery 记录一次搜索，空关键词不记录
+func (s *SearchService) LogQuery(keyword, userID string, resultCount int64) error {
+	keyword = NormalizeKeyword(keyword)
+	if keyword == "" {
+		return nil
+	}
+	return s.db.Create(&internal.SearchLog{
+		ID:          uuid.New().String(),
+		Keyword:     keyword,
+		UserID:      userID,
+		ResultCount: resultCount,
+		CreatedAt:   time.Now().UnixMilli(),
+	}).Error
+}
+
+// escapeLike 转义 LIKE 通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// Suggest 搜索联想：优先返回以输入开头的热门搜索词，再补充商品名包含输入或拼音以输入开头的上架商品名
+func (s *SearchService) Suggest(keyword string, limit int) ([]Suggestion, error) {
+	if limit < 1 || limit > 20 {
+		limit = 10
+	}
+	suggestions := []Suggestion{}
+	keyword = NormalizeKeyword(keyword)
+	if keyword == "" {
+		return suggestions, nil
+	}
+	seen := make(map[string]bool)
+
+	// 近期有结果的热门搜索词，按搜索人数排序
+	var queries []HotKeyword
+	since := time.Now().AddDate(0, 0, -30).UnixMilli()
+	err := s.db.Model(&internal.SearchLog{}).
+		Select("keyword, "+distinctUsers+" AS count").
+		Where("keyword LIKE ? ESCAPE '\\' AND keyword <> ? AND result_count > 0 AND created_at >= ?",
+			escapeLike(keyword)+"%", keyword, since).
+		Group("keyword").
+		Having(distinctUsers + " > 0").
+		Order("count DESC, keyword ASC").
+		Limit(limit).
+		Scan(&queries).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, q := range queries {
+		seen[q.Keyword] = true
+		suggestions = append(suggestions, Suggestion{Text: q.Keyword, Type: SuggestionTypeQuery})
+	}
+
+	// 商品名（含拼音匹配）
+	hits, err := s.index.Suggest(keyword, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range hits {
+		if len(suggestions) >= limit {
+			break
+		}
+		name := strings.TrimSpace(h.Name)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		suggestions = append(suggestions, Suggestion{Text: name, Type: SuggestionTypeProduct, SPUID: h.SPUID})
+	}
+	return suggestions, nil
+}
+
+// HotKeywords 最近 hotKeywordDays 天有结果的热门搜索词，按搜索人数排序，同一用户重复搜索只计一次
+func (s *SearchService) HotKeywords(limit int) ([]HotKeyword, error) {
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+	keywords := []HotKeyword{}
+	since := time.Now().AddDate(0, 0, -hotKeywordDays).UnixMilli()
+	err := s.db.Model(&internal.SearchLog{}).
+		Select("keyword, "+distinctUsers+" AS count").
+		Where("created_at >= ? AND result_count > 0", since).
+		Group("keyword").
+		Having(distinctUsers + " > 0").
+		Order("count DESC, keyword ASC").
+		Limit(limit).
+		Scan(&keywords).Error
+	return keywords, err
+}
+
+// QueryReport 统计时间范围内的热门搜索词和无结果搜索词，startTime/endTime 为 0 表示不限
+func (s *SearchService) QueryReport(startTime, endTime int64, limit int) (*QueryReport, error) {
+	if limit < 1 || limit > 200 {
+		limit = 20
+	}
+	base := func() *gorm.DB {
+		q := s.db.Model(&internal.SearchLog{})
+		if startTime > 0 {
+			q = q.Where("created_at >= ?", startTime)
+		}
+		if endTime > 0 {
+			q = q.Where("created_at <= ?", endTime)
+		}
+		return q
+	}
+
+	report := &QueryReport{TopQueries: []QueryStat{}, ZeroResultQueries: []QueryStat{}}
+	if err := base().Count(&report.TotalSearches).Error; err != nil {
+		return nil, err
+	}
+	if err := base().Where("result_count = 0").Count(&report.ZeroResultSearches).Error; err != nil {
+		return nil, err
+	}
+	if report.TotalSearches > 0 {
+		report.ZeroResultRate = float64(report.ZeroResultSearches) * 100 / float64(report.TotalSearches)
+	}
+
+	const stats = "keyword, COUNT(*) AS searches, " + distinctUsers + " AS users, " +
+		"AVG(result_count) AS avg_results, MAX(created_at) AS last_searched_at"
+	if err := base().Select(stats).Group("keyword").
+		Order("searches DESC, keyword ASC").Limit(limit).
+		Scan(&report.TopQueries).Error; err != nil {
+		return nil, err
+	}
+	if err := base().Select(stats).Where("result_count = 0").Group("keyword").
+		Order("searches DESC, keyword ASC").Limit(limit).
+		Scan(&report.ZeroResultQueries).Error; err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,108 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"z26b-backend/internal"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestNormalizeKeyword(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"   ", ""},
+		{"  连衣裙  ", "连衣裙"},
+		{"Nike   AIR\t跑鞋", "nike air 跑鞋"},
+		{strings.Repeat("裙", 70), strings.Repeat("裙", maxKeywordLength)},
+		{strings.Repeat("a", 63) + " b", strings.Repeat("a", 63)},
+	}
+	for _, tt := range tests {
+		if got := NormalizeKeyword(tt.in); got != tt.want {
+			t.Errorf("NormalizeKeyword(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// testSearchService 使用内存 SQLite 和内存索引创建搜索服务，logs 的 CreatedAt 填写为几天前
+func testSearchService(t *testing.T, docs []Document, logs []internal.SearchLog) *SearchService {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&internal.SearchLog{}); err != nil {
+		t.Fatal(err)
+	}
+	for i := range logs {
+		logs[i].ID = internal.GenerateUUID()
+		logs[i].CreatedAt = time.Now().AddDate(0, 0, -int(logs[i].CreatedAt)).UnixMilli()
+	}
+	if len(logs) > 0 {
+		if err := db.Create(&logs).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	idx := NewMemoryIndex()
+	idx.Rebuild(docs)
+	return NewSearchServiceWithIndex(db, idx)
+}
+
+func TestHotKeywords(t *testing.T) {
+	s := testSearchService(t, nil, []internal.SearchLog{
+		{Keyword: "重复", UserID: "u1", ResultCount: 3},
+		{Keyword: "重复", UserID: "u1", ResultCount: 3},
+		{Keyword: "重复", UserID: "u1", ResultCount: 3},
+		{Keyword: "多人", UserID: "u1", ResultCount: 3},
+		{Keyword: "多人", UserID: "u2", ResultCount: 3},
+		{Keyword: "无结果", UserID: "u1", ResultCount: 0},
+		{Keyword: "无结果", UserID: "u2", ResultCount: 0},
+		{Keyword: "匿名", UserID: "", ResultCount: 3},
+		{Keyword: "过期", UserID: "u1", ResultCount: 3, CreatedAt: hotKeywordDays + 1},
+		{Keyword: "过期", UserID: "u2", ResultCount: 3, CreatedAt: hotKeywordDays + 1},
+		{Keyword: "过期", UserID: "u3", ResultCount: 3, CreatedAt: hotKeywordDays + 1},
+	})
+
+	got, err := s.HotKeywords(10)
+	if err != nil {
+		t.Fatalf("HotKeywords() error = %v", err)
+	}
+	want := []HotKeyword{{Keyword: "多人", Count: 2}, {Keyword: "重复", Count: 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("HotKeywords() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	s := testSearchService(t, []Document{
+		{SPUID: "summer", Name: "夏季连衣裙", Status: "ENABLED"},
+		{SPUID: "same", Name: "连衣裙", Status: "ENABLED"},
+	}, []internal.SearchLog{
+		{Keyword: "连衣裙 长款", UserID: "u1", ResultCount: 3},
+		{Keyword: "连衣裙 长款", UserID: "u1", ResultCount: 3},
+		{Keyword: "连衣裙 长款", UserID: "u1", ResultCount: 3},
+		{Keyword: "连衣裙", UserID: "u1", ResultCount: 3},
+		{Keyword: "连衣裙", UserID: "u2", ResultCount: 3},
+		{Keyword: "连衣裤", UserID: "u1", ResultCount: 0},
+	})
+
+	got, err := s.Suggest(" 连衣 ", 10)
+	if err != nil {
+		t.Fatalf("Suggest() error = %v", err)
+	}
+	want := []Suggestion{
+		{Text: "连衣裙", Type: SuggestionTypeQuery},
+		{Text: "连衣裙 长款", Type: SuggestionTypeQuery},
+		{Text: "夏季连衣裙", Type: SuggestionTypeProduct, SPUID: "summer"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Suggest() = %+v, want %+v", got, want)
+	}
+}
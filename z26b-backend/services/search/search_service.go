@@ -0,0 +1,384 @@
+package search
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"z26b-backend/internal"
+
+	"gorm.io/gorm"
+)
+
+// Query 搜索条件
+type Query struct {
+	Keyword    string
+	CategoryID string   // 包含子孙分类
+	TagIDs     []string // 标签筛选
+	TagAll     bool     // 为 true 时需包含全部标签，否则命中任一标签
+	MinPrice   *float64
+	MaxPrice   *float64
+	Sort       string // 为空时按相关度排序，否则按 internal.GoodsSort* 排序
+	Page       int
+	PageSize   int
+}
+
+// FacetBucket 分面统计项
+type FacetBucket struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+// Facets 搜索结果分面统计（基于关键词命中的全部商品，不受分类/标签/价格筛选影响）
+type Facets struct {
+	Categories []FacetBucket `json:"categories"`
+	Tags       []FacetBucket `json:"tags"`
+	Prices     []FacetBucket `json:"prices"`
+}
+
+// Result 搜索结果
+type Result struct {
+	Records   []internal.SPU `json:"records"`
+	Total     int64          `json:"total"`
+	Facets    Facets         `json:"facets"`
+	Truncated bool           `json:"truncated"` // 命中数超过上限，total、分面和排序只基于相关度最高的 maxSearchHits 个商品
+}
+
+// maxSearchHits 参与筛选、分面统计和排序的最大命中数
+const maxSearchHits = 1000
+
+// priceRange 价格分面区间 [Min, Max)，Max 为 0 表示不设上限
+type priceRange struct {
+	Min, Max float64
+	Value    string
+	Label    string
+}
+
+var priceRanges = []priceRange{
+	{0, 50, "0-50", "50元以下"},
+	{50, 100, "50-100", "50-100元"},
+	{100, 200, "100-200", "100-200元"},
+	{200, 500, "200-500", "200-500元"},
+	{500, 0, "500-", "500元以上"},
+}
+
+// ErrInvalidPriceRange 最低价大于最高价
+var ErrInvalidPriceRange = errors.New("invalid price range")
+
+// SearchService 商品搜索服务
+type SearchService struct {
+	db    *gorm.DB
+	index Index
+}
+
+// NewSearchService 创建商品搜索服务，PostgreSQL 使用 tsvector 全文检索，其他数据库使用进程内索引
+func NewSearchService(db *gorm.DB) *SearchService {
+	var index Index
+	if db.Dialector.Name() == "postgres" {
+		index = NewPostgresIndex(db)
+	} else {
+		index = NewMemoryIndex()
+	}
+	return NewSearchServiceWithIndex(db, index)
+}
+
+// NewSearchServiceWithIndex 使用指定索引创建商品搜索服务
+func NewSearchServiceWithIndex(db *gorm.DB, index Index) *SearchService {
+	return &SearchService{db: db, index: index}
+}
+
+// Init 启动时初始化索引：内存索引全量构建，持久化索引为空或由旧版本生成时构建
+func (s *SearchService) Init() error {
+	if _, ok := s.index.(*MemoryIndex); !ok {
+		var count int64
+		if err := s.db.Model(&internal.SearchDocument{}).Count(&count).Error; err != nil {
+			return err
+		}
+		stale := false
+		if pg, ok := s.index.(*PostgresIndex); ok && count > 0 {
+			var err error
+			if stale, err = pg.hasUnweighted(); err != nil {
+				return err
+			}
+		}
+		if count > 0 && !stale {
+			return nil
+		}
+	}
+	_, err := s.Rebuild()
+	return err
+}
+
+// Rebuild 全量重建索引，返回索引的商品数
+func (s *SearchService) Rebuild() (int, error) {
+	docs, err := s.loadDocuments(nil)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.index.Rebuild(docs); err != nil {
+		return 0, err
+	}
+	return len(docs), nil
+}
+
+// IndexProduct 同步单个商品到索引，商品不存在时从索引删除
+func (s *SearchService) IndexProduct(spuID string) error {
+	docs, err := s.loadDocuments([]string{spuID})
+	if err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return s.index.Delete(spuID)
+	}
+	return s.index.Upsert(docs[0])
+}
+
+// IndexProducts 批量同步商品到索引
+func (s *SearchService) IndexProducts(spuIDs []string) error {
+	for _, id := range spuIDs {
+		if err := s.IndexProduct(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveProduct 从索引删除商品
+func (s *SearchService) RemoveProduct(spuID string) error {
+	return s.index.Delete(spuID)
+}
+
+// loadDocuments 从数据库加载商品搜索文档，ids 为空时加载全部商品
+func (s *SearchService) loadDocuments(ids []string) ([]Document, error) {
+	var spus []internal.SPU
+	query := s.db.Preload("Category")
+	if ids != nil {
+		query = query.Where("id IN ?", ids)
+	}
+	if err := query.Find(&spus).Error; err != nil {
+		return nil, err
+	}
+	if len(spus) == 0 {
+		return nil, nil
+	}
+
+	spuIDs := make([]string, 0, len(spus))
+	for _, spu := range spus {
+		spuIDs = append(spuIDs, spu.ID)
+	}
+	var spuTags []internal.SPUTag
+	if err := s.db.Preload("Tag").Where("spu_id IN ?", spuIDs).Find(&spuTags).Error; err != nil {
+		return nil, err
+	}
+	tagsBySPU := make(map[string][]internal.Tag)
+	for _, st := range spuTags {
+		if st.Tag != nil {
+			tagsBySPU[st.SPUID] = append(tagsBySPU[st.SPUID], *st.Tag)
+		}
+	}
+
+	docs := make([]Document, 0, len(spus))
+	for _, spu := range spus {
+		doc := Document{
+			SPUID:       spu.ID,
+			Name:        spu.Name,
+			Content:     StripHTML(spu.Detail),
+			CategoryID:  spu.CategoryID,
+			MinPrice:    spu.MinPrice,
+			MaxPrice:    spu.MaxPrice,
+			Priority:    spu.Priority,
+			Status:      spu.Status,
+			PublishAt:   spu.PublishAt,
+			UnpublishAt: spu.UnpublishAt,
+		}
+		keywords := make([]string, 0, 4)
+		if spu.Category != nil {
+			doc.CategoryName = spu.Category.Name
+			keywords = append(keywords, spu.Category.Name)
+		}
+		for _, tag := range tagsBySPU[spu.ID] {
+			doc.TagIDs = append(doc.TagIDs, tag.ID)
+			doc.TagNames = append(doc.TagNames, tag.Name)
+			keywords = append(keywords, tag.Name)
+		}
+		doc.Keywords = strings.Join(keywords, " ")
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// Search 搜索商品：按相关度排序，返回分页结果和分面统计
+func (s *SearchService) Search(q Query) (*Result, error) {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.PageSize < 1 {
+		q.PageSize = 10
+	}
+
+	hits, err := s.index.Search(q.Keyword)
+	if err != nil {
+		return nil, err
+	}
+
+	// 只返回上架商品，定时上下架按当前时间判断
+	now := time.Now().UnixMilli()
+	visible := hits[:0]
+	for _, h := range hits {
+		if h.visibleAt(now) {
+			visible = append(visible, h)
+		}
+	}
+
+	result := &Result{Records: []internal.SPU{}}
+	if len(visible) > maxSearchHits {
+		visible = visible[:maxSearchHits]
+		result.Truncated = true
+	}
+	result.Facets = buildFacets(visible)
+
+	filtered, err := s.filter(visible, q)
+	if err != nil {
+		return nil, err
+	}
+	result.Total = int64(len(filtered))
+
+	start := (q.Page - 1) * q.PageSize
+	if start >= len(filtered) {
+		return result, nil
+	}
+
+	var ids []string
+	if q.Sort != "" {
+		// 指定排序时由数据库对全部命中商品排序分页
+		all := make([]string, 0, len(filtered))
+		for _, h := range filtered {
+			all = append(all, h.SPUID)
+		}
+		err := s.db.Model(&internal.SPU{}).Where("spu.id IN ?", all).Scopes(internal.SPUSortBy(q.Sort)).
+			Offset(start).Limit(q.PageSize).Pluck("spu.id", &ids).Error
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		end := start + q.PageSize
+		if end > len(filtered) {
+			end = len(filtered)
+		}
+		for _, h := range filtered[start:end] {
+			ids = append(ids, h.SPUID)
+		}
+	}
+	var spus []internal.SPU
+	if err := s.db.Where("id IN ?", ids).Find(&spus).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[string]internal.SPU, len(spus))
+	for _, spu := range spus {
+		byID[spu.ID] = spu
+	}
+	for _, id := range ids {
+		if spu, ok := byID[id]; ok {
+			result.Records = append(result.Records, spu)
+		}
+	}
+	if err := internal.LoadSPUTags(s.db, result.Records); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// filter 按分类（含子孙分类）、标签、价格筛选
+func (s *SearchService) filter(hits []Hit, q Query) ([]Hit, error) {
+	if q.MinPrice != nil && q.MaxPrice != nil && *q.MinPrice > *q.MaxPrice {
+		return nil, ErrInvalidPriceRange
+	}
+
+	var categories map[string]bool
+	if q.CategoryID != "" {
+		var all []internal.Category
+		if err := s.db.Select("id", "parent_id").Find(&all).Error; err != nil {
+			return nil, err
+		}
+		categories = make(map[string]bool)
+		for _, id := range internal.CategoryDescendantIDs(all, q.CategoryID) {
+			categories[id] = true
+		}
+	}
+
+	out := make([]Hit, 0, len(hits))
+	for _, h := range hits {
+		if categories != nil && !categories[h.CategoryID] {
+			continue
+		}
+		if !internal.MatchTags(h.TagIDs, q.TagIDs, q.TagAll) {
+			continue
+		}
+		// 价格区间与商品价格范围有交集即命中
+		if q.MinPrice != nil && h.MaxPrice < *q.MinPrice {
+			continue
+		}
+		if q.MaxPrice != nil && h.MinPrice > *q.MaxPrice {
+			continue
+		}
+		out = append(out, h)
+	}
+	return out, nil
+}
+
+// buildFacets 统计分类、标签、价格区间分面，按数量降序
+func buildFacets(hits []Hit) Facets {
+	categories := make(map[string]*FacetBucket)
+	tags := make(map[string]*FacetBucket)
+	prices := make([]FacetBucket, len(priceRanges))
+	for i, r := range priceRanges {
+		prices[i] = FacetBucket{Value: r.Value, Label: r.Label}
+	}
+
+	for _, h := range hits {
+		if h.CategoryID != "" {
+			if categories[h.CategoryID] == nil {
+				categories[h.CategoryID] = &FacetBucket{Value: h.CategoryID, Label: h.CategoryName}
+			}
+			categories[h.CategoryID].Count++
+		}
+		for i, id := range h.TagIDs {
+			if tags[id] == nil {
+				label := ""
+				if i < len(h.TagNames) {
+					label = h.TagNames[i]
+				}
+				tags[id] = &FacetBucket{Value: id, Label: label}
+			}
+			tags[id].Count++
+		}
+		for i, r := range priceRanges {
+			if h.MinPrice >= r.Min && (r.Max == 0 || h.MinPrice < r.Max) {
+				prices[i].Count++
+				break
+			}
+		}
+	}
+
+	return Facets{
+		Categories: sortedBuckets(categories),
+		Tags:       sortedBuckets(tags),
+		Prices:     prices,
+	}
+}
+
+func sortedBuckets(m map[string]*FacetBucket) []FacetBucket {
+	buckets := make([]FacetBucket, 0, len(m))
+	for _, b := range m {
+		buckets = append(buckets, *b)
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].Count != buckets[j].Count {
+			return buckets[i].Count > buckets[j].Count
+		}
+		return buckets[i].Value < buckets[j].Value
+	})
+	return buckets
+}
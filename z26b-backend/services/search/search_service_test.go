@@ -0,0 +1,31 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildFacets(t *testing.T) {
+	hits := []Hit{
+		{Document: Document{SPUID: "1", CategoryID: "c1", CategoryName: "服装", TagIDs: []string{"t1", "t2"}, TagNames: []string{"新品", "热卖"}, MinPrice: 30}},
+		{Document: Document{SPUID: "2", CategoryID: "c1", CategoryName: "服装", TagIDs: []string{"t2"}, TagNames: []string{"热卖"}, MinPrice: 50}},
+		{Document: Document{SPUID: "3", CategoryID: "c2", CategoryName: "鞋靴", MinPrice: 800}},
+		{Document: Document{SPUID: "4", MinPrice: 99.9}},
+	}
+	facets := buildFacets(hits)
+
+	wantCategories := []FacetBucket{{"c1", "服装", 2}, {"c2", "鞋靴", 1}}
+	if !reflect.DeepEqual(facets.Categories, wantCategories) {
+		t.Errorf("Categories = %v, want %v", facets.Categories, wantCategories)
+	}
+	wantTags := []FacetBucket{{"t2", "热卖", 2}, {"t1", "新品", 1}}
+	if !reflect.DeepEqual(facets.Tags, wantTags) {
+		t.Errorf("Tags = %v, want %v", facets.Tags, wantTags)
+	}
+	wantPrices := []int{1, 2, 0, 0, 1}
+	for i, b := range facets.Prices {
+		if b.Count != wantPrices[i] {
+			t.Errorf("Prices[%s] = %d, want %d", b.Value, b.Count, wantPrices[i])
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package search
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// pinyinOf 汉字 → 拼音，由 pinyinGroups 展开
+var pinyinOf = func() map[rune]string {
+	m := make(map[rune]string, 6800)
+	for py, chars := range pinyinGroups {
+		for _, r := range chars {
+			m[r] = py
+		}
+	}
+	return m
+}()
+
+var htmlTagPattern = regexp.MustCompile(`(?s)<[^>]*>`)
+
+// StripHTML 去除 HTML 标签并还原实体，用于索引商品详情正文
+func StripHTML(s string) string {
+	s = htmlTagPattern.ReplaceAllString(s, " ")
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
+
+// segment 文本片段：连续的汉字或连续的字母数字
+type segment struct {
+	text string
+	han  bool
+}
+
+// segments 按汉字/字母数字切分文本，其余字符作为分隔符；字母统一转小写
+func segments(text string) []segment {
+	var segs []segment
+	var buf []rune
+	han := false
+
+	flush := func() {
+		if len(buf) > 0 {
+			segs = append(segs, segment{text: string(buf), han: han})
+			buf = buf[:0]
+		}
+	}
+
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			if !han {
+				flush()
+			}
+			han = true
+			buf = append(buf, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if han {
+				flush()
+			}
+			han = false
+			buf = append(buf, unicode.ToLower(r))
+		default:
+			flush()
+		}
+	}
+	flush()
+	return segs
+}
+
+// Tokenize 索引分词：字母数字按单词切分，中文按单字和相邻双字（bigram）切分
+func Tokenize(text string) []string {
+	var terms []string
+	for _, seg := range segments(text) {
+		if !seg.han {
+			terms = append(terms, seg.text)
+			continue
+		}
+		runes := []rune(seg.text)
+		for i := range runes {
+			terms = append(terms, string(runes[i]))
+			if i+1 < len(runes) {
+				terms = append(terms, string(runes[i:i+2]))
+			}
+		}
+	}
+	return terms
+}
+
+// queryTerms 查询分词结果
+type queryTerms struct {
+	Required []string // 必须全部命中：中文单字、字母数字单词（按前缀匹配）
+	Boost    []string // 命中时加分：中文双字
+	Prefix   map[string]bool
+}
+
+// analyzeQuery 查询分词，中文单字要求全部命中，双字用于提升相关度
+func analyzeQuery(keyword string) queryTerms {
+	q := queryTerms{Prefix: make(map[string]bool)}
+	seen := make(map[string]bool)
+	for _, seg := range segments(keyword) {
+		if !seg.han {
+			if !seen[seg.text] {
+				seen[seg.text] = true
+				q.Required = append(q.Required, seg.text)
+				q.Prefix[seg.text] = true
+			}
+			continue
+		}
+		runes := []rune(seg.text)
+		for i := range runes {
+			if t := string(runes[i]); !seen[t] {
+				seen[t] = true
+				q.Required = append(q.Required, t)
+			}
+			if i+1 < len(runes) {
+				if t := string(runes[i : i+2]); !seen[t] {
+					seen[t] = true
+					q.Boost = append(q.Boost, t)
+				}
+			}
+		}
+	}
+	return q
+}
+
+// suggestTerms 联想分词：中文要求相邻双字（只有一个字时为该字）全部出现在商品名中，
+// 即商品名包含输入的中文片段；字母数字单词按前缀匹配
+func suggestTerms(keyword string) queryTerms {
+	q := queryTerms{Prefix: make(map[string]bool)}
+	seen := make(map[string]bool)
+	add := func(t string, prefix bool) {
+		if !seen[t] {
+			seen[t] = true
+			q.Required = append(q.Required, t)
+			q.Prefix[t] = prefix
+		}
+	}
+	for _, seg := range segments(keyword) {
+		runes := []rune(seg.text)
+		switch {
+		case !seg.han:
+			add(seg.text, true)
+		case len(runes) == 1:
+			add(seg.text, false)
+		default:
+			for i := 0; i+1 < len(runes); i++ {
+				add(string(runes[i:i+2]), false)
+			}
+		}
+	}
+	return q
+}
+
+// Pinyin 返回文本的全拼和首字母（均为小写、无分隔），字母数字原样保留，未收录的汉字忽略
+func Pinyin(text string) (full, initials string) {
+	var f, i strings.Builder
+	for _, seg := range segments(text) {
+		if !seg.han {
+			f.WriteString(seg.text)
+			i.WriteString(seg.text)
+			continue
+		}
+		for _, r := range seg.text {
+			if py, ok := pinyinOf[r]; ok {
+				f.WriteString(py)
+				i.WriteByte(py[0])
+			}
+		}
+	}
+	return f.String(), i.String()
+}
+
+// isPinyinQuery 关键词是否可能是拼音或首字母输入（仅包含字母）
+func isPinyinQuery(keyword string) bool {
+	keyword = strings.ReplaceAll(strings.TrimSpace(keyword), " ", "")
+	if keyword == "" {
+		return false
+	}
+	for _, r := range keyword {
+		if r > unicode.MaxASCII || !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,120 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"latin words lowercased", "iPhone 15 Pro", []string{"iphone", "15", "pro"}},
+		{"chinese unigrams and bigrams", "连衣裙", []string{"连", "连衣", "衣", "衣裙", "裙"}},
+		{"mixed text split at script change", "夏季T恤", []string{"夏", "夏季", "季", "t", "恤"}},
+		{"punctuation as separator", "纯棉/短袖", []string{"纯", "纯棉", "棉", "短", "短袖", "袖"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Tokenize(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		keyword  string
+		required []string
+		boost    []string
+		prefix   []string
+	}{
+		{"chinese", "连衣裙", []string{"连", "衣", "裙"}, []string{"连衣", "衣裙"}, nil},
+		{"latin words match by prefix", "Nike air", []string{"nike", "air"}, nil, []string{"nike", "air"}},
+		{"duplicates removed", "裙 裙子", []string{"裙", "子"}, []string{"裙子"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := analyzeQuery(tt.keyword)
+			if !reflect.DeepEqual(q.Required, tt.required) {
+				t.Errorf("analyzeQuery(%q).Required = %q, want %q", tt.keyword, q.Required, tt.required)
+			}
+			if !reflect.DeepEqual(q.Boost, tt.boost) {
+				t.Errorf("analyzeQuery(%q).Boost = %q, want %q", tt.keyword, q.Boost, tt.boost)
+			}
+			if len(q.Prefix) != len(tt.prefix) {
+				t.Errorf("analyzeQuery(%q).Prefix = %v, want %q", tt.keyword, q.Prefix, tt.prefix)
+			}
+			for _, p := range tt.prefix {
+				if !q.Prefix[p] {
+					t.Errorf("analyzeQuery(%q).Prefix missing %q", tt.keyword, p)
+				}
+			}
+		})
+	}
+}
+
+func TestPinyin(t *testing.T) {
+	tests := []struct {
+		text         string
+		wantFull     string
+		wantInitials string
+	}{
+		{"连衣裙", "lianyiqun", "lyq"},
+		{"夏季T恤", "xiajitxu", "xjtx"},
+		{"iPhone 15", "iphone15", "iphone15"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			full, initials := Pinyin(tt.text)
+			if full != tt.wantFull || initials != tt.wantInitials {
+				t.Errorf("Pinyin(%q) = %q, %q, want %q, %q", tt.text, full, initials, tt.wantFull, tt.wantInitials)
+			}
+		})
+	}
+}
+
+func TestIsPinyinQuery(t *testing.T) {
+	tests := []struct {
+		keyword string
+		want    bool
+	}{
+		{"lyq", true},
+		{"lian yi qun", true},
+		{"连衣裙", false},
+		{"iphone15", false},
+		{"  ", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.keyword, func(t *testing.T) {
+			if got := isPinyinQuery(tt.keyword); got != tt.want {
+				t.Errorf("isPinyinQuery(%q) = %v, want %v", tt.keyword, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"tags removed", "<p>纯棉<b>面料</b></p>", "纯棉 面料"},
+		{"entities decoded", "A &amp; B&nbsp;C", "A & B C"},
+		{"multiline tag", "<img\nsrc=\"a.png\">说明", "说明"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripHTML(tt.html); got != tt.want {
+				t.Errorf("StripHTML(%q) = %q, want %q", tt.html, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package wallet
+
+import "z26b-backend/internal"
+
+// WalletServiceInterface 储值钱包服务接口
+type WalletServiceInterface interface {
+	// GetOrCreateWallet 获取或创建用户钱包
+	GetOrCreateWallet(userID string) (*internal.Wallet, error)
+	// GetWallets 获取钱包列表
+	GetWallets(page, pageSize int, keyword string) ([]internal.Wallet, int64, error)
+	// GetTransactions 获取用户钱包流水
+	GetTransactions(userID, txnType string, page, pageSize int, withEntries bool) ([]internal.WalletTransaction, int64, error)
+	// TopUp 充值
+	TopUp(userID string, amount float64, operatorID, remark string) (*internal.WalletTransaction, error)
+	// Adjust 人工调整余额，amount 为负数时扣减
+	Adjust(userID string, amount float64, operatorID, remark string) (*internal.WalletTransaction, error)
+	// Pay 订单使用余额支付
+	Pay(userID, orderID string, amount float64) (*internal.WalletTransaction, error)
+	// RefundOrder 将订单的余额支付部分退回钱包
+	RefundOrder(orderID string) (float64, error)
+}
@@ -0,0 +1,251 @@
+package wallet
+
+import (
+	"errors"
+	"time"
+
+	"z26b-backend/internal"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrInsufficientBalance 余额不足
+	ErrInsufficientBalance = errors.New("余额不足")
+	// ErrUserNotFound 用户不存在，不为其创建钱包
+	ErrUserNotFound = errors.New("用户不存在")
+)
+
+// WalletService 储值钱包服务
+type WalletService struct {
+	db *gorm.DB
+}
+
+// NewWalletService 创建储值钱包服务实例
+func NewWalletService(db *gorm.DB) *WalletService {
+	return &WalletService{db: db}
+}
+
+// WithTx 返回绑定到指定事务的服务实例，用于与订单等操作共用事务
+func (s *WalletService) WithTx(tx *gorm.DB) *WalletService {
+	return &WalletService{db: tx}
+}
+
+// userAccount 用户钱包科目
+func userAccount(userID string) string {
+	return "user:" + userID
+}
+
+// GetOrCreateWallet 获取或创建用户钱包，用户不存在时返回 ErrUserNotFound
+func (s *WalletService) GetOrCreateWallet(userID string) (*internal.Wallet, error) {
+	return getOrCreateWallet(s.db, userID)
+}
+
+func getOrCreateWallet(db *gorm.DB, userID string) (*internal.Wallet, error) {
+	var wallet internal.Wallet
+	err := db.Where("user_id = ?", userID).First(&wallet).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		var users int64
+		if err := db.Model(&internal.User{}).Where("id = ?", userID).Count(&users).Error; err != nil {
+			return nil, err
+		}
+		if users == 0 {
+			return nil, ErrUserNotFound
+		}
+		wallet = internal.Wallet{
+			ID:        uuid.New().String(),
+			UserID:    userID,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		// 并发创建时以已存在的钱包为准
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&wallet).Error; err != nil {
+			return nil, err
+		}
+		if err := db.Where("user_id = ?", userID).First(&wallet).Error; err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+// GetWallets 获取钱包列表
+func (s *WalletService) GetWallets(page, pageSize int, keyword string) ([]internal.Wallet, int64, error) {
+	var wallets []internal.Wallet
+	var total int64
+
+	query := s.db.Model(&internal.Wallet{})
+	if keyword != "" {
+		query = query.Where(`user_id = ? OR user_id IN (SELECT id FROM "user" WHERE nick_name LIKE ?)`, keyword, "%"+keyword+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Preload("User").Order("balance DESC").Offset(offset).Limit(pageSize).Find(&wallets).Error
+	return wallets, total, err
+}
+
+// GetTransactions 获取用户钱包流水
+func (s *WalletService) GetTransactions(userID, txnType string, page, pageSize int, withEntries bool) ([]internal.WalletTransaction, int64, error) {
+	var txns []internal.WalletTransaction
+	var total int64
+
+	query := s.db.Model(&internal.WalletTransaction{}).Where("user_id = ?", userID)
+	if txnType != "" {
+		query = query.Where("type = ?", txnType)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if withEntries {
+		query = query.Preload("Entries")
+	}
+	offset := (page - 1) * pageSize
+	err := query.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&txns).Error
+	return txns, total, err
+}
+
+// TopUp 充值（门店售卡收款）
+func (s *WalletService) TopUp(userID string, amount float64, operatorID, remark string) (*internal.WalletTransaction, error) {
+	if amount <= 0 {
+		return nil, errors.New("充值金额必须大于0")
+	}
+	return s.post(userID, internal.WalletTxnTopUp, amount, internal.WalletAccountStoreCash, "", operatorID, remark)
+}
+
+// Adjust 人工调整余额，amount 为负数时扣减
+func (s *WalletService) Adjust(userID string, amount float64, operatorID, remark string) (*internal.WalletTransaction, error) {
+	if amount == 0 {
+		return nil, errors.New("调整金额不能为0")
+	}
+	if remark == "" {
+		return nil, errors.New("请填写调整原因")
+	}
+	return s.post(userID, internal.WalletTxnAdjust, amount, internal.WalletAccountStoreAdjustment, "", operatorID, remark)
+}
+
+// Pay 订单使用余额支付
+func (s *WalletService) Pay(userID, orderID string, amount float64) (*internal.WalletTransaction, error) {
+	if amount <= 0 {
+		return nil, errors.New("支付金额必须大于0")
+	}
+	return s.post(userID, internal.WalletTxnPay, -amount, internal.WalletAccountStoreSales, orderID, "", "订单支付")
+}
+
+// RefundOrder 将订单的余额支付部分退回钱包，重复调用不会重复退款；
+// 检查和退款前锁定订单行，并发调用时后到的请求会看到已有的退款流水
+func (s *WalletService) RefundOrder(orderID string) (float64, error) {
+	var refund float64
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var order internal.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, "id = ?", orderID).Error; err != nil {
+			return err
+		}
+		if order.BalancePaid <= 0 {
+			return nil
+		}
+
+		var refunded int64
+		if err := tx.Model(&internal.WalletTransaction{}).Where("order_id = ? AND type = ?", orderID, internal.WalletTxnRefund).Count(&refunded).Error; err != nil {
+			return err
+		}
+		if refunded > 0 {
+			return nil
+		}
+
+		if _, err := s.WithTx(tx).post(order.UserID, internal.WalletTxnRefund, order.BalancePaid, internal.WalletAccountStoreSales, orderID, "", "订单退款"); err != nil {
+			return err
+		}
+		refund = order.BalancePaid
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return refund, nil
+}
+
+// post 记账：变动用户余额并写入一借一贷两条分录
+// amount 为正数时贷记用户账户（余额增加），为负数时借记用户账户（余额减少）
+func (s *WalletService) post(userID, txnType string, amount float64, counterAccount, orderID, operatorID, remark string) (*internal.WalletTransaction, error) {
+	amount = internal.RoundMoney(amount)
+	var txn *internal.WalletTransaction
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		wallet, err := getOrCreateWallet(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"balance":    gorm.Expr("balance + ?", amount),
+			"updated_at": time.Now(),
+		}
+		switch txnType {
+		case internal.WalletTxnTopUp:
+			updates["total_recharged"] = gorm.Expr("total_recharged + ?", amount)
+		case internal.WalletTxnPay:
+			updates["total_spent"] = gorm.Expr("total_spent + ?", -amount)
+		case internal.WalletTxnRefund:
+			updates["total_spent"] = gorm.Expr("total_spent - ?", amount)
+		}
+
+		// 扣减时在同一条语句中校验余额，避免并发超扣
+		query := tx.Model(&internal.Wallet{}).Where("id = ?", wallet.ID)
+		if amount < 0 {
+			query = query.Where("balance >= ?", -amount)
+		}
+		result := query.Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInsufficientBalance
+		}
+
+		if err := tx.First(wallet, "id = ?", wallet.ID).Error; err != nil {
+			return err
+		}
+
+		now := time.Now().UnixMilli()
+		txn = &internal.WalletTransaction{
+			ID:           uuid.New().String(),
+			UserID:       userID,
+			Type:         txnType,
+			Amount:       amount,
+			BalanceAfter: internal.RoundMoney(wallet.Balance),
+			OrderID:      orderID,
+			OperatorID:   operatorID,
+			Remark:       remark,
+			CreatedAt:    now,
+		}
+
+		debit, credit := counterAccount, userAccount(userID)
+		if amount < 0 {
+			debit, credit = credit, debit
+		}
+		magnitude := amount
+		if magnitude < 0 {
+			magnitude = -magnitude
+		}
+		txn.Entries = []internal.WalletEntry{
+			{ID: uuid.New().String(), TransactionID: txn.ID, Account: debit, Direction: internal.WalletEntryDebit, Amount: magnitude, CreatedAt: now},
+			{ID: uuid.New().String(), TransactionID: txn.ID, Account: credit, Direction: internal.WalletEntryCredit, Amount: magnitude, CreatedAt: now},
+		}
+
+		return tx.Create(txn).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return txn, nil
+}
@@ -0,0 +1,69 @@
+package wallet
+
+import (
+	"testing"
+
+	"z26b-backend/internal"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.AutoMigrate(&internal.User{}, &internal.Order{}, &internal.Wallet{}, &internal.WalletTransaction{}, &internal.WalletEntry{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestRefundOrder(t *testing.T) {
+	tests := []struct {
+		name        string
+		balancePaid float64
+		want        float64
+		wantTxns    int64
+	}{
+		{"余额支付部分退回", 30, 30, 1},
+		{"未使用余额", 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			db.Create(&internal.User{ID: "u1", OpenID: "o1"})
+			db.Create(&internal.Order{ID: "o1", UserID: "u1", BalancePaid: tt.balancePaid})
+			service := NewWalletService(db)
+
+			got, err := service.RefundOrder("o1")
+			if err != nil {
+				t.Fatalf("RefundOrder() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RefundOrder() = %v, want %v", got, tt.want)
+			}
+
+			// 重复调用不重复退款
+			again, err := service.RefundOrder("o1")
+			if err != nil || again != 0 {
+				t.Errorf("RefundOrder() again = %v, %v, want 0", again, err)
+			}
+			var refunds int64
+			db.Model(&internal.WalletTransaction{}).Where("order_id = ? AND type = ?", "o1", internal.WalletTxnRefund).Count(&refunds)
+			if refunds != tt.wantTxns {
+				t.Errorf("refund transactions = %d, want %d", refunds, tt.wantTxns)
+			}
+		})
+	}
+
+	t.Run("订单不存在", func(t *testing.T) {
+		if _, err := NewWalletService(newTestDB(t)).RefundOrder("missing"); err == nil {
+			t.Error("RefundOrder() error = nil, want error")
+		}
+	})
+}